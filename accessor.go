@@ -2,6 +2,7 @@ package gtly
 
 import (
 	"github.com/viant/xunsafe"
+	"reflect"
 	"time"
 )
 
@@ -16,11 +17,38 @@ func (m *Accessor) init(index int, field *xunsafe.Field) {
 	m.Field = field
 }
 
-//Value returns value
+//Value returns value, nil nested object or array is returned as nil
 func (m *Accessor) Value(object *Object) interface{} {
+	switch m.Field.Type {
+	case typeObject:
+		if nested := m.Object(object); nested != nil {
+			return nested
+		}
+		return nil
+	case typeArray:
+		if nested := m.Array(object); nested != nil {
+			return nested
+		}
+		return nil
+	}
+	switch m.Field.Type.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Struct, reflect.Array:
+		//direct interface types are read with reflect, xunsafe runtime type flag layout does not match every Go version
+		return reflect.NewAt(m.Field.Type, m.Field.Pointer(object.addr)).Elem().Interface()
+	}
 	return m.Field.Value(object.addr)
 }
 
+//Object returns nested object value
+func (m *Accessor) Object(object *Object) *Object {
+	return *(**Object)(m.Field.Pointer(object.addr))
+}
+
+//Array returns nested array value
+func (m *Accessor) Array(object *Object) *Array {
+	return *(**Array)(m.Field.Pointer(object.addr))
+}
+
 //Int returns int value
 func (m *Accessor) Int(object *Object) int {
 	return m.Field.Int(object.addr)
@@ -63,3 +63,36 @@ func (a *Array) First() *Object {
 	}
 	return a._data[0]
 }
+
+//Filter returns a new array with objects matching predicate
+func (a *Array) Filter(predicate Predicate) (*Array, error) {
+	matcher, err := predicate(a.Proto())
+	if err != nil {
+		return nil, err
+	}
+	result := &Array{_provider: a._provider}
+	for _, item := range a._data {
+		if matcher(item) {
+			result._data = append(result._data, item)
+		}
+	}
+	return result, nil
+}
+
+//Find returns the first object matching predicate or nil
+func (a *Array) Find(predicate Predicate) (*Object, error) {
+	return find(a, predicate)
+}
+
+//FindAll returns all objects matching predicate
+func (a *Array) FindAll(predicate Predicate) ([]*Object, error) {
+	return findAll(a, predicate)
+}
+
+func (a *Array) asMaps() []map[string]interface{} {
+	var result = make([]map[string]interface{}, len(a._data))
+	for i, item := range a._data {
+		result[i] = item.AsMap()
+	}
+	return result
+}
@@ -190,3 +190,25 @@ func initObjectValues(values map[string]interface{}, object *gtly.Object) {
 		object.SetValue(k, v)
 	}
 }
+
+func TestArray_Filter(t *testing.T) {
+	provider := newPredicateProvider(t)
+	array := newPredicateArray(t, provider)
+	filtered, err := array.Filter(gtly.Eq("city", "Warsaw"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 2, filtered.Size())
+	assert.Equal(t, 4, array.Size())
+	assert.Equal(t, array.Proto(), filtered.Proto())
+
+	found, err := array.Find(gtly.Gt("income", 1000))
+	if assert.Nil(t, err) && assert.NotNil(t, found) {
+		assert.Equal(t, 1, found.Value("id"))
+	}
+	found, err = array.Find(gtly.Gt("income", 5000))
+	assert.Nil(t, err)
+	assert.Nil(t, found)
+	_, err = array.Filter(gtly.IsNull("country"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,311 @@
+package avro
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"github.com/viant/gtly/internal/memory"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+//Codec represents avro binary codec for objects sharing the same proto
+type Codec struct {
+	provider *gtly.Provider
+	record   *record
+	config   *config
+	schema   string
+}
+
+//Schema returns avro record schema JSON, object fields are nullable unions since object fields may not be set
+func (c *Codec) Schema() string {
+	return c.schema
+}
+
+//Marshal encodes an object in avro binary format
+func (c *Codec) Marshal(object *gtly.Object) ([]byte, error) {
+	return c.appendObject(nil, object), nil
+}
+
+//Unmarshal decodes avro binary data into an object
+func (c *Codec) Unmarshal(data []byte, object *gtly.Object) error {
+	return c.decodeObject(&reader{data: data}, object)
+}
+
+func (c *Codec) appendObject(buf []byte, object *gtly.Object) []byte {
+	return appendObject(buf, c.record, object)
+}
+
+func (c *Codec) decodeObject(in *reader, object *gtly.Object) error {
+	return decodeObject(in, c.record, object)
+}
+
+func appendObject(buf []byte, aRecord *record, object *gtly.Object) []byte {
+	addr := object.Addr()
+	for _, aField := range aRecord.fields {
+		buf = appendField(buf, aField, addr, object.SetAt(aField.index))
+	}
+	return buf
+}
+
+func decodeObject(in *reader, aRecord *record, object *gtly.Object) error {
+	addr := object.Addr()
+	for _, aField := range aRecord.fields {
+		set, err := decodeField(in, aField, addr)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode %v.%v", aRecord.name, aField.name)
+		}
+		if set {
+			object.MarkSetAt(aField.index)
+		}
+	}
+	return nil
+}
+
+func appendField(buf []byte, aField *field, addr unsafe.Pointer, set bool) []byte {
+	ptr := aField.xField.Pointer(addr)
+	if aField.pointer {
+		if ptr = *(*unsafe.Pointer)(ptr); ptr == nil {
+			set = false
+		}
+	}
+	if (aField.kind == kindObject || aField.kind == kindObjects) && *(*unsafe.Pointer)(ptr) == nil {
+		set = false
+	}
+	if aField.nullable {
+		if !set {
+			return appendLong(buf, 0)
+		}
+		buf = appendLong(buf, 1)
+	}
+	return appendValue(buf, &aField.avroType, ptr)
+}
+
+func appendValue(buf []byte, aType *avroType, ptr unsafe.Pointer) []byte {
+	switch aType.kind {
+	case kindInt, kindLong:
+		return appendLong(buf, memory.ReadInt(aType.rType.Kind(), ptr))
+	case kindFloat:
+		return appendFloat(buf, *(*float32)(ptr))
+	case kindDouble:
+		return appendDouble(buf, *(*float64)(ptr))
+	case kindBoolean:
+		if *(*bool)(ptr) {
+			return append(buf, 1)
+		}
+		return append(buf, 0)
+	case kindString:
+		return appendString(buf, *(*string)(ptr))
+	case kindBytes:
+		return appendBytes(buf, *(*[]byte)(ptr))
+	case kindTimestamp:
+		ts := (*time.Time)(ptr)
+		return appendLong(buf, ts.Unix()*int64(time.Second/time.Microsecond)+int64(ts.Nanosecond())/int64(time.Microsecond))
+	case kindArray:
+		slice := memory.Slice(aType.items.rType, ptr)
+		length := slice.Len()
+		if length > 0 {
+			data := unsafe.Pointer(slice.Pointer())
+			buf = appendLong(buf, int64(length))
+			itemSize := aType.items.rType.Size()
+			for i := 0; i < length; i++ {
+				buf = appendValue(buf, aType.items, unsafe.Pointer(uintptr(data)+uintptr(i)*itemSize))
+			}
+		}
+		return appendLong(buf, 0)
+	case kindObject:
+		return appendObject(buf, aType.record, *(**gtly.Object)(ptr))
+	case kindObjects:
+		array := *(**gtly.Array)(ptr)
+		if size := array.Size(); size > 0 {
+			buf = appendLong(buf, int64(size))
+			_ = array.Objects(func(item *gtly.Object) (bool, error) {
+				buf = appendObject(buf, aType.record, item)
+				return true, nil
+			})
+		}
+		return appendLong(buf, 0)
+	}
+	for _, aField := range aType.record.fields {
+		buf = appendField(buf, aField, ptr, true)
+	}
+	return buf
+}
+
+//decodeField decodes field value, returns false if null was decoded
+func decodeField(in *reader, aField *field, addr unsafe.Pointer) (bool, error) {
+	if aField.nullable {
+		branch, err := in.long()
+		if err != nil {
+			return false, err
+		}
+		switch branch {
+		case 0:
+			return false, nil
+		case 1:
+		default:
+			return false, errors.Errorf("invalid union branch: %v", branch)
+		}
+	}
+	ptr := aField.xField.Pointer(addr)
+	if aField.pointer {
+		target := (*unsafe.Pointer)(ptr)
+		if *target == nil {
+			*target = unsafe.Pointer(reflect.New(aField.xField.Type.Elem()).Pointer())
+		}
+		ptr = *target
+	}
+	return true, decodeValue(in, &aField.avroType, ptr)
+}
+
+func decodeValue(in *reader, aType *avroType, ptr unsafe.Pointer) error {
+	switch aType.kind {
+	case kindInt, kindLong:
+		value, err := in.long()
+		if err != nil {
+			return err
+		}
+		memory.WriteInt(aType.rType.Kind(), ptr, value)
+	case kindFloat:
+		value, err := in.float()
+		if err != nil {
+			return err
+		}
+		*(*float32)(ptr) = value
+	case kindDouble:
+		value, err := in.double()
+		if err != nil {
+			return err
+		}
+		*(*float64)(ptr) = value
+	case kindBoolean:
+		value, err := in.boolean()
+		if err != nil {
+			return err
+		}
+		*(*bool)(ptr) = value
+	case kindString:
+		value, err := in.bytes()
+		if err != nil {
+			return err
+		}
+		*(*string)(ptr) = string(value)
+	case kindBytes:
+		value, err := in.bytes()
+		if err != nil {
+			return err
+		}
+		*(*[]byte)(ptr) = append([]byte{}, value...)
+	case kindTimestamp:
+		value, err := in.long()
+		if err != nil {
+			return err
+		}
+		micros := int64(time.Second / time.Microsecond)
+		seconds, remainder := value/micros, value%micros
+		if remainder < 0 {
+			seconds, remainder = seconds-1, remainder+micros
+		}
+		*(*time.Time)(ptr) = time.Unix(seconds, remainder*int64(time.Microsecond)).UTC()
+	case kindArray:
+		return decodeArray(in, aType, ptr)
+	case kindObject:
+		object := aType.record.provider.NewObject()
+		if err := decodeObject(in, aType.record, object); err != nil {
+			return err
+		}
+		*(**gtly.Object)(ptr) = object
+	case kindObjects:
+		return decodeObjects(in, aType, ptr)
+	default:
+		for _, aField := range aType.record.fields {
+			if _, err := decodeField(in, aField, ptr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func decodeArray(in *reader, aType *avroType, ptr unsafe.Pointer) error {
+	slice := reflect.NewAt(aType.rType, ptr).Elem()
+	for {
+		count, err := in.long()
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+		if count < 0 { //negative count is followed by block byte size
+			count = -count
+			if _, err = in.long(); err != nil {
+				return err
+			}
+		}
+		for i := int64(0); i < count; i++ {
+			item := reflect.New(aType.items.rType)
+			if err = decodeValue(in, aType.items, unsafe.Pointer(item.Pointer())); err != nil {
+				return err
+			}
+			slice.Set(reflect.Append(slice, item.Elem()))
+		}
+	}
+}
+
+func decodeObjects(in *reader, aType *avroType, ptr unsafe.Pointer) error {
+	array := aType.record.provider.NewArray()
+	*(**gtly.Array)(ptr) = array
+	for {
+		count, err := in.long()
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+		if count < 0 { //negative count is followed by block byte size
+			count = -count
+			if _, err = in.long(); err != nil {
+				return err
+			}
+		}
+		for i := int64(0); i < count; i++ {
+			item := aType.record.provider.NewObject()
+			if err = decodeObject(in, aType.record, item); err != nil {
+				return err
+			}
+			array.AddObject(item)
+		}
+	}
+}
+
+//NewCodec creates an avro codec for supplied proto
+func NewCodec(proto *gtly.Proto, options ...Option) (*Codec, error) {
+	config := &config{compression: CompressionNull, blockSize: defaultBlockSize}
+	for _, option := range options {
+		option(config)
+	}
+	name := config.name
+	if name == "" {
+		name = proto.SimpleName()
+	}
+	aRegistry := newRegistry()
+	provider := &gtly.Provider{Proto: proto}
+	aRecord, err := aRegistry.protoRecord(name, provider)
+	if err != nil {
+		return nil, err
+	}
+	result := &Codec{
+		provider: provider,
+		record:   aRecord,
+		config:   config,
+	}
+	schema, err := json.Marshal(result.record.schema(map[*record]bool{}))
+	if err != nil {
+		return nil, err
+	}
+	result.schema = string(schema)
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package avro
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"testing"
+	"time"
+)
+
+func TestCodec_Schema(t *testing.T) {
+	address, _ := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+	)
+	provider, _ := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("updated", gtly.FieldTypeTime),
+		gtly.NewField("numbers", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeFloat32)),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(address)),
+		gtly.NewField("addresses", gtly.FieldTypeArray, gtly.ProviderOpt(address)),
+	)
+	codec, err := NewCodec(provider.Proto)
+	if !assert.Nil(t, err) {
+		return
+	}
+	expect := `{"type":"record","name":"Foo","fields":[` +
+		`{"name":"id","type":["null","long"],"default":null},` +
+		`{"name":"updated","type":["null",{"type":"long","logicalType":"timestamp-micros"}],"default":null},` +
+		`{"name":"numbers","type":["null",{"type":"array","items":"float"}],"default":null},` +
+		`{"name":"address","type":["null",{"type":"record","name":"Address","fields":[{"name":"city","type":["null","string"],"default":null}]}],"default":null},` +
+		`{"name":"addresses","type":["null",{"type":"array","items":"Address"}],"default":null}]}`
+	assert.Equal(t, expect, codec.Schema())
+}
+
+func TestCodec_Marshal(t *testing.T) {
+	provider, _ := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+	)
+	codec, err := NewCodec(provider.Proto)
+	if !assert.Nil(t, err) {
+		return
+	}
+	object := provider.NewObject()
+	object.SetValue("id", -2)
+	data, err := codec.Marshal(object)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x02, 0x03, 0x00}, data)
+
+	object.SetValue("name", "ab")
+	data, err = codec.Marshal(object)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x02, 0x03, 0x02, 0x04, 'a', 'b'}, data)
+}
+
+func TestCodec_Unmarshal(t *testing.T) {
+	address, _ := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("zip", gtly.FieldTypeInt),
+	)
+	provider, _ := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("count", gtly.FieldTypeInt64),
+		gtly.NewField("ratio", gtly.FieldTypeFloat32),
+		gtly.NewField("price", gtly.FieldTypeFloat64),
+		gtly.NewField("active", gtly.FieldTypeBool),
+		gtly.NewField("name", gtly.FieldTypeString),
+		gtly.NewField("data", gtly.FieldTypeBytes),
+		gtly.NewField("updated", gtly.FieldTypeTime),
+		gtly.NewField("numbers", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeInt)),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(address)),
+		gtly.NewField("addresses", gtly.FieldTypeArray, gtly.ProviderOpt(address)),
+		gtly.NewField("unset", gtly.FieldTypeString),
+	)
+	codec, err := NewCodec(provider.Proto)
+	if !assert.Nil(t, err) {
+		return
+	}
+	home := map[string]interface{}{"city": "Warsaw", "zip": 12}
+	values := map[string]interface{}{
+		"id":        -3,
+		"count":     int64(1) << 40,
+		"ratio":     float32(0.25),
+		"price":     10.5,
+		"active":    true,
+		"name":      "Foo",
+		"data":      []byte{1, 2, 3},
+		"updated":   time.Date(1969, 11, 1, 10, 0, 0, 1000, time.UTC),
+		"numbers":   []int{1, -2, 300},
+		"address":   home,
+		"addresses": []map[string]interface{}{home},
+	}
+	object := provider.NewObject()
+	for k, v := range values {
+		object.SetValue(k, v)
+	}
+	data, err := codec.Marshal(object)
+	if !assert.Nil(t, err) {
+		return
+	}
+	decoded := provider.NewObject()
+	if !assert.Nil(t, codec.Unmarshal(data, decoded)) {
+		return
+	}
+	assert.EqualValues(t, object.AsMap(), decoded.AsMap())
+	assert.False(t, decoded.SetAt(provider.Field("unset").Index))
+	assert.NotNil(t, codec.Unmarshal(data[:len(data)-1], provider.NewObject()))
+}
@@ -0,0 +1,267 @@
+package avro
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+)
+
+const (
+	syncSize       = 16
+	schemaKey      = "avro.schema"
+	compressionKey = "avro.codec"
+)
+
+var magic = []byte{'O', 'b', 'j', 1}
+
+//WriteContainer writes collection as avro object container file
+func (c *Codec) WriteContainer(writer io.Writer, collection gtly.Collection) error {
+	compress, err := compressor(c.config.compression)
+	if err != nil {
+		return err
+	}
+	sync := make([]byte, syncSize)
+	if _, err = rand.Read(sync); err != nil {
+		return err
+	}
+	header := append([]byte{}, magic...)
+	header = appendLong(header, 2)
+	header = appendString(header, schemaKey)
+	header = appendString(header, c.schema)
+	header = appendString(header, compressionKey)
+	header = appendString(header, c.config.compression)
+	header = appendLong(header, 0)
+	header = append(header, sync...)
+	if _, err = writer.Write(header); err != nil {
+		return err
+	}
+	var block []byte
+	count := 0
+	flush := func() error {
+		if count == 0 {
+			return nil
+		}
+		data, err := compress(block)
+		if err != nil {
+			return err
+		}
+		prefix := appendLong(appendLong(nil, int64(count)), int64(len(data)))
+		for _, chunk := range [][]byte{prefix, data, sync} {
+			if _, err = writer.Write(chunk); err != nil {
+				return err
+			}
+		}
+		block = block[:0]
+		count = 0
+		return nil
+	}
+	err = collection.Objects(func(item *gtly.Object) (bool, error) {
+		block = c.appendObject(block, item)
+		if count++; count >= c.config.blockSize {
+			return true, flush()
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+//ReadContainer reads avro object container file, decoded objects are added to the collection
+func (c *Codec) ReadContainer(source io.Reader, collection gtly.Collection) error {
+	in := bufio.NewReader(source)
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(in, header); err != nil {
+		return errors.Wrap(err, "failed to read avro header")
+	}
+	if !bytes.Equal(header, magic) {
+		return errors.New("invalid avro object container file")
+	}
+	metadata, err := readMetadata(in)
+	if err != nil {
+		return errors.Wrap(err, "failed to read avro metadata")
+	}
+	if err = c.checkSchema(metadata[schemaKey]); err != nil {
+		return err
+	}
+	compression := string(metadata[compressionKey])
+	if compression == "" {
+		compression = CompressionNull
+	}
+	decompress, err := decompressor(compression)
+	if err != nil {
+		return err
+	}
+	sync := make([]byte, syncSize)
+	if _, err = io.ReadFull(in, sync); err != nil {
+		return errors.Wrap(err, "failed to read avro sync marker")
+	}
+	blockSync := make([]byte, syncSize)
+	for block := 1; ; block++ {
+		count, err := binary.ReadVarint(in)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read block %v", block)
+		}
+		data, err := readBytes(in)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read block %v", block)
+		}
+		if _, err = io.ReadFull(in, blockSync); err != nil {
+			return errors.Wrapf(err, "failed to read block %v", block)
+		}
+		if !bytes.Equal(sync, blockSync) {
+			return errors.Errorf("invalid sync marker after block %v", block)
+		}
+		if data, err = decompress(data); err != nil {
+			return errors.Wrapf(err, "failed to decompress block %v", block)
+		}
+		blockReader := &reader{data: data}
+		for i := int64(0); i < count; i++ {
+			item := c.provider.NewObject()
+			if err = c.decodeObject(blockReader, item); err != nil {
+				return errors.Wrapf(err, "failed to decode block %v", block)
+			}
+			collection.AddObject(item)
+		}
+	}
+}
+
+//checkSchema checks that writer schema record fields match codec record fields
+func (c *Codec) checkSchema(schema []byte) error {
+	writerSchema := &schemaRecord{}
+	if err := json.Unmarshal(schema, writerSchema); err != nil {
+		return errors.Wrap(err, "failed to parse avro schema")
+	}
+	if writerSchema.Type != "record" || len(writerSchema.Fields) != len(c.record.fields) {
+		return errors.Errorf("incompatible avro schema: %s", schema)
+	}
+	for i, aField := range writerSchema.Fields {
+		if aField.Name != c.record.fields[i].name {
+			return errors.Errorf("incompatible avro schema field %v, expected %v", aField.Name, c.record.fields[i].name)
+		}
+	}
+	return nil
+}
+
+func readMetadata(in *bufio.Reader) (map[string][]byte, error) {
+	result := map[string][]byte{}
+	for {
+		count, err := binary.ReadVarint(in)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return result, nil
+		}
+		if count < 0 { //negative count is followed by block byte size
+			count = -count
+			size, err := binary.ReadVarint(in)
+			if err != nil {
+				return nil, err
+			}
+			if size < 0 {
+				return nil, errors.Errorf("invalid size: %v", size)
+			}
+		}
+		for i := int64(0); i < count; i++ {
+			key, err := readBytes(in)
+			if err != nil {
+				return nil, err
+			}
+			value, err := readBytes(in)
+			if err != nil {
+				return nil, err
+			}
+			result[string(key)] = value
+		}
+	}
+}
+
+func readBytes(in *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadVarint(in)
+	if err != nil {
+		return nil, err
+	}
+	if size < 0 {
+		return nil, errors.Errorf("invalid size: %v", size)
+	}
+	result, err := ioutil.ReadAll(io.LimitReader(in, size)) //size is not trusted, buffer grows with the remaining input
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(result)) < size {
+		return nil, errors.Errorf("invalid size: %v, remaining input: %v", size, len(result))
+	}
+	return result, nil
+}
+
+func compressor(compression string) (func(data []byte) ([]byte, error), error) {
+	switch compression {
+	case CompressionNull:
+		return func(data []byte) ([]byte, error) {
+			return data, nil
+		}, nil
+	case CompressionDeflate:
+		return func(data []byte) ([]byte, error) {
+			buffer := new(bytes.Buffer)
+			writer, err := flate.NewWriter(buffer, flate.DefaultCompression)
+			if err != nil {
+				return nil, err
+			}
+			if _, err = writer.Write(data); err != nil {
+				return nil, err
+			}
+			err = writer.Close()
+			return buffer.Bytes(), err
+		}, nil
+	case CompressionSnappy:
+		return func(data []byte) ([]byte, error) {
+			compressed := snappy.Encode(nil, data)
+			var checksum [4]byte
+			binary.BigEndian.PutUint32(checksum[:], crc32.ChecksumIEEE(data))
+			return append(compressed, checksum[:]...), nil
+		}, nil
+	}
+	return nil, errors.Errorf("unsupported avro compression: %v", compression)
+}
+
+func decompressor(compression string) (func(data []byte) ([]byte, error), error) {
+	switch compression {
+	case CompressionNull:
+		return func(data []byte) ([]byte, error) {
+			return data, nil
+		}, nil
+	case CompressionDeflate:
+		return func(data []byte) ([]byte, error) {
+			return ioutil.ReadAll(flate.NewReader(bytes.NewReader(data)))
+		}, nil
+	case CompressionSnappy:
+		return func(data []byte) ([]byte, error) {
+			if len(data) < 4 {
+				return nil, errTruncated
+			}
+			result, err := snappy.Decode(nil, data[:len(data)-4])
+			if err != nil {
+				return nil, err
+			}
+			if binary.BigEndian.Uint32(data[len(data)-4:]) != crc32.ChecksumIEEE(result) {
+				return nil, errors.New("invalid snappy block checksum")
+			}
+			return result, nil
+		}, nil
+	}
+	return nil, errors.Errorf("unsupported avro compression: %v", compression)
+}
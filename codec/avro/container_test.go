@@ -0,0 +1,102 @@
+package avro
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"testing"
+	"time"
+)
+
+func TestCodec_WriteContainer(t *testing.T) {
+	provider, _ := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+		gtly.NewField("updated", gtly.FieldTypeTime),
+	)
+	array := provider.NewArray()
+	for i := 0; i < 5; i++ {
+		item := provider.NewObject()
+		item.SetValue("id", i)
+		if i%2 == 0 {
+			item.SetValue("name", "name")
+		}
+		item.SetValue("updated", time.Unix(int64(i), 0).UTC())
+		array.AddObject(item)
+	}
+
+	testCases := []struct {
+		description string
+		options     []Option
+		expectError bool
+	}{
+		{description: "uncompressed"},
+		{description: "deflate", options: []Option{CompressionOpt(CompressionDeflate), BlockSizeOpt(2)}},
+		{description: "snappy", options: []Option{CompressionOpt(CompressionSnappy), BlockSizeOpt(3)}},
+		{description: "unsupported", options: []Option{CompressionOpt("lzma")}, expectError: true},
+	}
+
+	for _, testCase := range testCases {
+		codec, err := NewCodec(provider.Proto, testCase.options...)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		buffer := new(bytes.Buffer)
+		err = codec.WriteContainer(buffer, array)
+		if testCase.expectError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		decoded := provider.NewMap(gtly.NewKeyProvider("id"))
+		if !assert.Nil(t, codec.ReadContainer(buffer, decoded), testCase.description) {
+			continue
+		}
+		assert.Equal(t, 5, decoded.Size(), testCase.description)
+		assert.Equal(t, "name", decoded.Object(4).Value("name"), testCase.description)
+		assert.Nil(t, decoded.Object(3).Value("name"), testCase.description)
+		assert.Equal(t, time.Unix(3, 0).UTC(), decoded.Object(3).Value("updated"), testCase.description)
+	}
+}
+
+func TestCodec_ReadContainer(t *testing.T) {
+	writerProvider, _ := gtly.NewProvider("foo", gtly.NewField("id", gtly.FieldTypeInt))
+	readerProvider, _ := gtly.NewProvider("foo", gtly.NewField("name", gtly.FieldTypeString))
+	writerCodec, _ := NewCodec(writerProvider.Proto)
+	readerCodec, _ := NewCodec(readerProvider.Proto)
+	buffer := new(bytes.Buffer)
+	assert.Nil(t, writerCodec.WriteContainer(buffer, writerProvider.NewArray(writerProvider.NewObject())))
+	assert.NotNil(t, readerCodec.ReadContainer(bytes.NewReader(buffer.Bytes()), readerProvider.NewArray()))
+	assert.NotNil(t, writerCodec.ReadContainer(bytes.NewReader([]byte("invalid")), writerProvider.NewArray()))
+
+	var testCases = []struct {
+		description string
+		metadata    []byte
+		expectError string
+	}{
+		{
+			description: "negative length",
+			metadata:    appendLong(appendLong(nil, 1), -3),
+			expectError: "invalid size: -3",
+		},
+		{
+			description: "length larger than input",
+			metadata:    append(appendLong(appendLong(nil, 1), 1<<40), "avro"...),
+			expectError: "invalid size: 1099511627776, remaining input: 4",
+		},
+		{
+			description: "negative block size",
+			metadata:    appendLong(appendLong(nil, -1), -8),
+			expectError: "invalid size: -8",
+		},
+	}
+	for _, testCase := range testCases {
+		data := append(append([]byte{}, magic...), testCase.metadata...)
+		err := writerCodec.ReadContainer(bytes.NewReader(data), writerProvider.NewArray())
+		if assert.NotNil(t, err, testCase.description) {
+			assert.EqualValues(t, "failed to read avro metadata: "+testCase.expectError, err.Error(), testCase.description)
+		}
+	}
+}
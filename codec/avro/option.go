@@ -0,0 +1,42 @@
+package avro
+
+const defaultBlockSize = 1000
+
+const ( //Object container file compression codecs
+	//CompressionNull stores blocks uncompressed
+	CompressionNull = "null"
+	//CompressionDeflate compresses blocks with raw deflate
+	CompressionDeflate = "deflate"
+	//CompressionSnappy compresses blocks with snappy followed by CRC32 checksum
+	CompressionSnappy = "snappy"
+)
+
+//Option represents codec option
+type Option func(c *config)
+
+type config struct {
+	name        string
+	compression string
+	blockSize   int
+}
+
+//RecordNameOpt returns an option overriding top level record name, proto simple name is used by default
+func RecordNameOpt(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+//CompressionOpt returns an object container file compression option
+func CompressionOpt(compression string) Option {
+	return func(c *config) {
+		c.compression = compression
+	}
+}
+
+//BlockSizeOpt returns an object container file option controlling number of objects per block
+func BlockSizeOpt(blockSize int) Option {
+	return func(c *config) {
+		c.blockSize = blockSize
+	}
+}
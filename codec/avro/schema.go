@@ -0,0 +1,300 @@
+package avro
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"github.com/viant/xunsafe"
+	"reflect"
+	"strconv"
+	"time"
+	"unicode"
+)
+
+var (
+	typeTime   = reflect.TypeOf(time.Time{})
+	typeBytes  = reflect.TypeOf([]byte{})
+	typeObject = reflect.TypeOf(&gtly.Object{})
+	typeArray  = reflect.TypeOf(&gtly.Array{})
+	nullValue  = json.RawMessage("null")
+)
+
+//typeKind represents avro type kind
+type typeKind int
+
+const (
+	kindInt = typeKind(iota)
+	kindLong
+	kindFloat
+	kindDouble
+	kindBoolean
+	kindString
+	kindBytes
+	kindTimestamp
+	kindArray
+	kindRecord
+	kindObject
+	kindObjects
+)
+
+//avroType represents avro type bound to go type
+type avroType struct {
+	kind   typeKind
+	rType  reflect.Type
+	items  *avroType
+	record *record
+}
+
+//field represents avro record field
+type field struct {
+	name     string
+	index    int
+	pointer  bool
+	nullable bool
+	avroType
+	xField *xunsafe.Field
+}
+
+//record represents avro record, provider is set for proto based records
+type record struct {
+	name     string
+	fields   []*field
+	provider *gtly.Provider
+}
+
+//registry represents compiled records registry
+type registry struct {
+	byType map[reflect.Type]*record
+	names  map[string]bool
+}
+
+func (r *registry) uniqueName(name string) string {
+	candidate := name
+	for i := 2; r.names[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	r.names[candidate] = true
+	return candidate
+}
+
+//structRecord returns a record for a nested struct type
+func (r *registry) structRecord(name string, structType reflect.Type) (*record, error) {
+	if result, ok := r.byType[structType]; ok {
+		return result, nil
+	}
+	result := &record{name: r.uniqueName(recordName(name))}
+	r.byType[structType] = result
+	for i := 0; i < structType.NumField(); i++ {
+		xField := xunsafe.FieldByIndex(structType, i)
+		aField, err := r.newField(xField.Name, xField, nil)
+		if err != nil {
+			return nil, err
+		}
+		aField.index = -1
+		result.fields = append(result.fields, aField)
+	}
+	return result, nil
+}
+
+//protoRecord returns a record for provider proto, fields are nullable since object fields may not be set
+func (r *registry) protoRecord(name string, provider *gtly.Provider) (*record, error) {
+	if result, ok := r.byType[provider.Type()]; ok {
+		return result, nil
+	}
+	result := &record{name: r.uniqueName(recordName(name)), provider: provider}
+	r.byType[provider.Type()] = result
+	fields := provider.Fields()
+	for i := range fields {
+		protoField := &fields[i]
+		aField, err := r.newField(protoField.OutputName(), provider.AccessorAt(protoField.Index).Field, protoField.ItemProvider())
+		if err != nil {
+			return nil, err
+		}
+		aField.index = protoField.Index
+		aField.nullable = true
+		result.fields = append(result.fields, aField)
+	}
+	return result, nil
+}
+
+func (r *registry) newField(name string, xField *xunsafe.Field, itemProvider *gtly.Provider) (*field, error) {
+	result := &field{name: fieldName(name), xField: xField}
+	if itemProvider != nil && (xField.Type == typeObject || xField.Type == typeArray) {
+		recordName := itemProvider.SimpleName()
+		if recordName == "" {
+			recordName = name
+		}
+		nested, err := r.protoRecord(recordName, itemProvider)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unsupported field %v", name)
+		}
+		result.avroType = avroType{kind: kindObject, rType: xField.Type, record: nested}
+		if xField.Type == typeArray {
+			result.kind = kindObjects
+		}
+		return result, nil
+	}
+	rType := xField.Type
+	if rType.Kind() == reflect.Ptr {
+		result.pointer = true
+		result.nullable = true
+		rType = rType.Elem()
+	}
+	aType, err := r.newType(name, rType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unsupported field %v", name)
+	}
+	result.avroType = *aType
+	return result, nil
+}
+
+func (r *registry) newType(name string, rType reflect.Type) (*avroType, error) {
+	result := &avroType{rType: rType}
+	switch rType.Kind() {
+	case reflect.Int32, reflect.Int16, reflect.Int8, reflect.Uint16, reflect.Uint8:
+		result.kind = kindInt
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Uint32:
+		result.kind = kindLong
+	case reflect.Float32:
+		result.kind = kindFloat
+	case reflect.Float64:
+		result.kind = kindDouble
+	case reflect.Bool:
+		result.kind = kindBoolean
+	case reflect.String:
+		result.kind = kindString
+	case reflect.Slice:
+		if rType == typeBytes {
+			result.kind = kindBytes
+			break
+		}
+		items, err := r.newType(name, rType.Elem())
+		if err != nil {
+			return nil, err
+		}
+		result.kind = kindArray
+		result.items = items
+	case reflect.Struct:
+		if rType == typeTime {
+			result.kind = kindTimestamp
+			break
+		}
+		nested, err := r.structRecord(name, rType)
+		if err != nil {
+			return nil, err
+		}
+		result.kind = kindRecord
+		result.record = nested
+	default:
+		return nil, errors.Errorf("unsupported type: %v", rType)
+	}
+	return result, nil
+}
+
+func newRegistry() *registry {
+	return &registry{byType: map[reflect.Type]*record{}, names: map[string]bool{}}
+}
+
+type schemaRecord struct {
+	Type   string        `json:"type"`
+	Name   string        `json:"name"`
+	Fields []schemaField `json:"fields"`
+}
+
+type schemaField struct {
+	Name    string          `json:"name"`
+	Type    interface{}     `json:"type"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+type schemaArray struct {
+	Type  string      `json:"type"`
+	Items interface{} `json:"items"`
+}
+
+type schemaLogical struct {
+	Type        string `json:"type"`
+	LogicalType string `json:"logicalType"`
+}
+
+//schema returns JSON schema representation, named records are defined only once
+func (r *record) schema(defined map[*record]bool) interface{} {
+	if defined[r] {
+		return r.name
+	}
+	defined[r] = true
+	result := schemaRecord{Type: "record", Name: r.name, Fields: make([]schemaField, len(r.fields))}
+	for i, aField := range r.fields {
+		result.Fields[i] = schemaField{Name: aField.name, Type: aField.avroType.schema(defined)}
+		if aField.nullable {
+			result.Fields[i].Type = []interface{}{"null", result.Fields[i].Type}
+			result.Fields[i].Default = nullValue
+		}
+	}
+	return result
+}
+
+func (t *avroType) schema(defined map[*record]bool) interface{} {
+	switch t.kind {
+	case kindInt:
+		return "int"
+	case kindLong:
+		return "long"
+	case kindFloat:
+		return "float"
+	case kindDouble:
+		return "double"
+	case kindBoolean:
+		return "boolean"
+	case kindString:
+		return "string"
+	case kindBytes:
+		return "bytes"
+	case kindTimestamp:
+		return schemaLogical{Type: "long", LogicalType: "timestamp-micros"}
+	case kindArray:
+		return schemaArray{Type: "array", Items: t.items.schema(defined)}
+	case kindObjects:
+		return schemaArray{Type: "array", Items: t.record.schema(defined)}
+	}
+	return t.record.schema(defined)
+}
+
+//recordName returns a valid avro record name
+func recordName(name string) string {
+	result := make([]rune, 0, len(name))
+	upper := true
+	for _, r := range name {
+		if !isNameRune(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		result = append(result, r)
+	}
+	if len(result) == 0 || unicode.IsDigit(result[0]) {
+		result = append([]rune("Record"), result...)
+	}
+	return string(result)
+}
+
+//fieldName returns a valid avro field name
+func fieldName(name string) string {
+	result := []rune(name)
+	for i, r := range result {
+		if !isNameRune(r) {
+			result[i] = '_'
+		}
+	}
+	if len(result) == 0 || unicode.IsDigit(result[0]) {
+		result = append([]rune("f_"), result...)
+	}
+	return string(result)
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || (r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)))
+}
@@ -0,0 +1,92 @@
+package avro
+
+import (
+	"encoding/binary"
+	"github.com/pkg/errors"
+	"math"
+)
+
+var errTruncated = errors.New("unexpected end of avro data")
+
+//appendLong appends zig-zag varint encoded long
+func appendLong(buf []byte, value int64) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	size := binary.PutVarint(scratch[:], value)
+	return append(buf, scratch[:size]...)
+}
+
+func appendBytes(buf []byte, value []byte) []byte {
+	buf = appendLong(buf, int64(len(value)))
+	return append(buf, value...)
+}
+
+func appendString(buf []byte, value string) []byte {
+	buf = appendLong(buf, int64(len(value)))
+	return append(buf, value...)
+}
+
+func appendFloat(buf []byte, value float32) []byte {
+	bits := math.Float32bits(value)
+	return append(buf, byte(bits), byte(bits>>8), byte(bits>>16), byte(bits>>24))
+}
+
+func appendDouble(buf []byte, value float64) []byte {
+	bits := math.Float64bits(value)
+	return append(buf, byte(bits), byte(bits>>8), byte(bits>>16), byte(bits>>24),
+		byte(bits>>32), byte(bits>>40), byte(bits>>48), byte(bits>>56))
+}
+
+//reader represents avro binary data reader
+type reader struct {
+	data []byte
+	pos  int
+}
+
+func (r *reader) long() (int64, error) {
+	value, size := binary.Varint(r.data[r.pos:])
+	if size <= 0 {
+		return 0, errTruncated
+	}
+	r.pos += size
+	return value, nil
+}
+
+func (r *reader) boolean() (bool, error) {
+	if r.pos >= len(r.data) {
+		return false, errTruncated
+	}
+	value := r.data[r.pos] != 0
+	r.pos++
+	return value, nil
+}
+
+func (r *reader) float() (float32, error) {
+	if r.pos+4 > len(r.data) {
+		return 0, errTruncated
+	}
+	data := r.data[r.pos:]
+	r.pos += 4
+	return math.Float32frombits(binary.LittleEndian.Uint32(data)), nil
+}
+
+func (r *reader) double() (float64, error) {
+	if r.pos+8 > len(r.data) {
+		return 0, errTruncated
+	}
+	data := r.data[r.pos:]
+	r.pos += 8
+	return math.Float64frombits(binary.LittleEndian.Uint64(data)), nil
+}
+
+func (r *reader) bytes() ([]byte, error) {
+	size, err := r.long()
+	if err != nil {
+		return nil, err
+	}
+	if size < 0 || size > int64(len(r.data)-r.pos) {
+		return nil, errTruncated
+	}
+	result := r.data[r.pos : r.pos+int(size)]
+	r.pos += int(size)
+	return result, nil
+}
@@ -0,0 +1,12 @@
+//Package csv provides CSV/TSV encoder and decoder for gtly collections
+package csv
+
+import (
+	"reflect"
+	"time"
+)
+
+var (
+	typeTime  = reflect.TypeOf(time.Time{})
+	typeBytes = reflect.TypeOf([]byte{})
+)
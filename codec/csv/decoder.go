@@ -0,0 +1,201 @@
+package csv
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const byteOrderMark = "\ufeff"
+
+//Decoder represents CSV decoder, the first row is a header which is matched to provider fields
+type Decoder struct {
+	reader   *csv.Reader
+	provider *gtly.Provider
+	config   *config
+	columns  []*gtly.Field
+	headers  []string
+	row      int
+}
+
+//Objects calls handler with every decoded row object, columns without matching field are skipped
+func (d *Decoder) Objects(handler func(item *gtly.Object) (toContinue bool, err error)) error {
+	if d.columns == nil {
+		if err := d.readHeader(); err != nil {
+			return err
+		}
+	}
+	for {
+		record, err := d.reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		d.row++
+		if err != nil {
+			return errors.Wrapf(err, "failed to read row %v", d.row)
+		}
+		object := d.provider.NewObject()
+		if err = d.decodeRecord(record, object); err != nil {
+			return err
+		}
+		toContinue, err := handler(object)
+		if err != nil {
+			return errors.Wrapf(err, "failed to handle row %v", d.row)
+		}
+		if !toContinue {
+			return nil
+		}
+	}
+}
+
+//Decode adds every decoded row object to supplied collection
+func (d *Decoder) Decode(collection gtly.Collection) error {
+	return d.Objects(func(item *gtly.Object) (bool, error) {
+		collection.AddObject(item)
+		return true, nil
+	})
+}
+
+//Row returns last read row number, header is row 1
+func (d *Decoder) Row() int {
+	return d.row
+}
+
+func (d *Decoder) readHeader() error {
+	headers, err := d.reader.Read()
+	d.row++
+	if err == io.EOF {
+		return errors.New("missing CSV header")
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to read CSV header")
+	}
+	if len(headers) > 0 {
+		headers[0] = strings.TrimPrefix(headers[0], byteOrderMark)
+	}
+	d.headers = append([]string{}, headers...)
+	d.columns = make([]*gtly.Field, len(headers))
+	for i, header := range d.headers {
+		d.columns[i] = d.matchField(strings.TrimSpace(header))
+	}
+	return nil
+}
+
+//matchField matches header with field Name, InputName, configured case format or case insensitive Name
+func (d *Decoder) matchField(header string) *gtly.Field {
+	proto := d.provider.Proto
+	if field := proto.Lookup(header); field != nil {
+		return field
+	}
+	fields := proto.Fields()
+	if d.config.sourceFormat != d.config.headerFormat {
+		for i := range fields {
+			if d.config.sourceFormat.Format(fields[i].Name, d.config.headerFormat) == header {
+				return &fields[i]
+			}
+		}
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].Name, header) {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+func (d *Decoder) decodeRecord(record []string, object *gtly.Object) error {
+	proto := object.Proto()
+	for i, cell := range record {
+		if i >= len(d.columns) || d.columns[i] == nil || cell == d.config.null {
+			continue
+		}
+		field := d.columns[i]
+		mutator := proto.MutatorAt(field.Index)
+		value, err := parseCell(cell, mutator.Type, field.TimeLayout())
+		if err != nil {
+			return errors.Wrapf(err, "invalid cell at row %v, column %v (%v)", d.row, i+1, d.headers[i])
+		}
+		mutator.SetValue(object, value.Interface())
+	}
+	return nil
+}
+
+//parseCell converts cell text to supplied type
+func parseCell(text string, target reflect.Type, timeLayout string) (reflect.Value, error) {
+	switch target.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(text).Convert(target), nil
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		value, err := strconv.ParseInt(strings.TrimSpace(text), 10, target.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(value).Convert(target), nil
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		value, err := strconv.ParseUint(strings.TrimSpace(text), 10, target.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(value).Convert(target), nil
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(strings.TrimSpace(text), target.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(value).Convert(target), nil
+	case reflect.Bool:
+		value, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(value), nil
+	case reflect.Ptr:
+		value, err := parseCell(text, target.Elem(), timeLayout)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result := reflect.New(target.Elem())
+		result.Elem().Set(value)
+		return result, nil
+	case reflect.Interface:
+		return reflect.ValueOf(text), nil
+	}
+	switch target {
+	case typeTime:
+		value, err := time.Parse(timeLayout, strings.TrimSpace(text))
+		if err != nil {
+			return reflect.Value{}, errors.Wrapf(err, "invalid time layout %v", timeLayout)
+		}
+		return reflect.ValueOf(value), nil
+	case typeBytes:
+		value, err := base64.StdEncoding.DecodeString(text)
+		return reflect.ValueOf(value), err
+	}
+	switch target.Kind() {
+	case reflect.Slice, reflect.Struct, reflect.Map:
+		value := reflect.New(target)
+		if err := json.Unmarshal([]byte(text), value.Interface()); err != nil {
+			return reflect.Value{}, err
+		}
+		return value.Elem(), nil
+	}
+	return reflect.Value{}, errors.Errorf("unsupported type: %v", target)
+}
+
+//NewDecoder creates CSV decoder
+func NewDecoder(reader io.Reader, provider *gtly.Provider, options ...Option) *Decoder {
+	config := newConfig(options)
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = config.delimiter
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = config.quoting == QuoteNone
+	csvReader.ReuseRecord = true
+	return &Decoder{reader: csvReader, provider: provider, config: config}
+}
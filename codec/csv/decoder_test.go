@@ -0,0 +1,131 @@
+package csv
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"github.com/viant/toolbox/format"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecoder_Decode(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		options     []Option
+		expect      []map[string]interface{}
+		expectError string
+	}{
+		{
+			description: "header matched by name",
+			input:       "id,name,active,score,created\n1,Foo,true,1.5,2021-03-04\n2,Bar,false,,2021-03-05\n",
+			expect: []map[string]interface{}{
+				{"id": 1, "name": "Foo", "active": true, "score": 1.5, "created": time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)},
+				{"id": 2, "name": "Bar", "active": false, "score": nil, "created": time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			description: "header matched by input name and case insensitive name, unknown column skipped",
+			input:       "\ufeffID,full_name,extra\n1,Foo,x\n",
+			expect: []map[string]interface{}{
+				{"id": 1, "name": "Foo"},
+			},
+		},
+		{
+			description: "header matched by case format",
+			input:       "ID,NAME,IS_ACTIVE\n1,Foo,1\n",
+			options:     []Option{CaseFormatOpt(format.CaseLowerCamel, format.CaseUpperUnderscore)},
+			expect: []map[string]interface{}{
+				{"id": 1, "name": "Foo", "active": nil},
+			},
+		},
+		{
+			description: "tsv with null representation",
+			input:       "id\tname\tscore\n1\t\\N\t2\n",
+			options:     []Option{DelimiterOpt('\t'), NullOpt(`\N`)},
+			expect: []map[string]interface{}{
+				{"id": 1, "name": nil, "score": 2.0},
+			},
+		},
+		{
+			description: "quoted cells",
+			input:       "id,name\n1,\"Foo, \"\"Bar\"\"\nBaz\"\n",
+			expect: []map[string]interface{}{
+				{"id": 1, "name": "Foo, \"Bar\"\nBaz"},
+			},
+		},
+		{
+			description: "invalid int cell",
+			input:       "id,name\n1,Foo\nx,Bar\n",
+			expectError: "row 3, column 1 (id)",
+		},
+		{
+			description: "invalid date cell",
+			input:       "name,created\nFoo,03/04/2021\n",
+			expectError: "row 2, column 2 (created)",
+		},
+		{
+			description: "missing header",
+			input:       "",
+			expectError: "missing CSV header",
+		},
+	}
+
+	for _, testCase := range testCases {
+		provider, err := gtly.NewProvider("foo",
+			gtly.NewField("id", gtly.FieldTypeInt),
+			gtly.NewField("name", gtly.FieldTypeString, func(field *gtly.Field) {
+				field.InputName = "full_name"
+			}),
+			gtly.NewField("isActive", gtly.FieldTypeBool),
+			gtly.NewField("active", gtly.FieldTypeBool),
+			gtly.NewField("score", gtly.FieldTypeFloat64),
+			gtly.NewField("created", gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02")),
+		)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		array := provider.NewArray()
+		err = NewDecoder(strings.NewReader(testCase.input), provider, testCase.options...).Decode(array)
+		if testCase.expectError != "" {
+			if assert.NotNil(t, err, testCase.description) {
+				assert.Contains(t, err.Error(), testCase.expectError, testCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) || !assert.Equal(t, len(testCase.expect), array.Size(), testCase.description) {
+			continue
+		}
+		i := 0
+		_ = array.Objects(func(item *gtly.Object) (bool, error) {
+			for name, expect := range testCase.expect[i] {
+				assert.EqualValues(t, expect, item.Value(name), testCase.description+" "+name)
+			}
+			i++
+			return true, nil
+		})
+	}
+}
+
+func TestDecoder_Objects(t *testing.T) {
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("tags", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeString)),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	decoder := NewDecoder(strings.NewReader("id,tags\n1,\"[\"\"a\"\",\"\"b\"\"]\"\n2,\n3,\n"), provider)
+	var ids []int
+	err = decoder.Objects(func(item *gtly.Object) (bool, error) {
+		ids = append(ids, item.Value("id").(int))
+		if len(ids) == 1 {
+			assert.EqualValues(t, []string{"a", "b"}, item.Value("tags"))
+		}
+		return len(ids) < 2, nil
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []int{1, 2}, ids)
+	assert.Equal(t, 3, decoder.Row())
+}
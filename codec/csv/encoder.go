@@ -0,0 +1,167 @@
+package csv
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Encoder represents CSV encoder, header is written from visible field output names before the first row
+type Encoder struct {
+	writer io.Writer
+	config *config
+	fields []*gtly.Field
+	buffer []byte
+	row    int
+}
+
+//Encode writes every collection object as a separate row, header is written even for an empty collection
+func (e *Encoder) Encode(collection gtly.Collection) error {
+	if e.fields == nil {
+		if err := e.writeHeader(collection.Proto()); err != nil {
+			return err
+		}
+	}
+	return collection.Objects(func(item *gtly.Object) (bool, error) {
+		err := e.EncodeObject(item)
+		return err == nil, err
+	})
+}
+
+//EncodeObject writes an object as a single row
+func (e *Encoder) EncodeObject(object *gtly.Object) error {
+	if e.fields == nil {
+		if err := e.writeHeader(object.Proto()); err != nil {
+			return err
+		}
+	}
+	e.row++
+	e.buffer = e.buffer[:0]
+	for i, field := range e.fields {
+		value, ok := object.ValueAt(field.Index)
+		cell, isNull, err := formatCell(value, ok, field.TimeLayout())
+		if err != nil {
+			return errors.Wrapf(err, "failed to encode row %v, column %v (%v)", e.row, i+1, field.OutputName())
+		}
+		if isNull {
+			cell = e.config.null
+		}
+		e.appendCell(i, cell)
+	}
+	return e.writeLine()
+}
+
+//Rows returns number of encoded rows, header is row 1
+func (e *Encoder) Rows() int {
+	return e.row
+}
+
+func (e *Encoder) writeHeader(proto *gtly.Proto) error {
+	fields := proto.Fields()
+	e.fields = make([]*gtly.Field, 0, len(fields))
+	e.buffer = e.buffer[:0]
+	for i := range fields {
+		if fields[i].IsHidden() {
+			continue
+		}
+		e.appendCell(len(e.fields), fields[i].OutputName())
+		e.fields = append(e.fields, &fields[i])
+	}
+	e.row++
+	return e.writeLine()
+}
+
+func (e *Encoder) writeLine() error {
+	e.buffer = append(e.buffer, '\n')
+	if _, err := e.writer.Write(e.buffer); err != nil {
+		return errors.Wrapf(err, "failed to write row %v", e.row)
+	}
+	return nil
+}
+
+func (e *Encoder) appendCell(column int, cell string) {
+	if column > 0 {
+		e.buffer = append(e.buffer, string(e.config.delimiter)...)
+	}
+	if !e.needsQuotes(cell) {
+		e.buffer = append(e.buffer, cell...)
+		return
+	}
+	e.buffer = append(e.buffer, '"')
+	e.buffer = append(e.buffer, strings.Replace(cell, `"`, `""`, -1)...)
+	e.buffer = append(e.buffer, '"')
+}
+
+func (e *Encoder) needsQuotes(cell string) bool {
+	switch e.config.quoting {
+	case QuoteAll:
+		return true
+	case QuoteNone:
+		return false
+	}
+	if cell == "" {
+		return false
+	}
+	if cell[0] == ' ' || cell[0] == '\t' {
+		return true
+	}
+	return strings.ContainsRune(cell, e.config.delimiter) || strings.ContainsAny(cell, "\"\r\n")
+}
+
+//formatCell converts value to cell text, returns true if value is null
+func formatCell(value interface{}, ok bool, timeLayout string) (string, bool, error) {
+	if !ok {
+		return "", true, nil
+	}
+	switch actual := value.(type) {
+	case nil:
+		return "", true, nil
+	case string:
+		return actual, false, nil
+	case int:
+		return strconv.Itoa(actual), false, nil
+	case int64:
+		return strconv.FormatInt(actual, 10), false, nil
+	case float32:
+		return strconv.FormatFloat(float64(actual), 'f', -1, 32), false, nil
+	case float64:
+		return strconv.FormatFloat(actual, 'f', -1, 64), false, nil
+	case bool:
+		return strconv.FormatBool(actual), false, nil
+	case time.Time:
+		return actual.Format(timeLayout), false, nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(actual), false, nil
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if reflectValue.IsNil() {
+			return "", true, nil
+		}
+		return formatCell(reflectValue.Elem().Interface(), true, timeLayout)
+	case reflect.Int, reflect.Int32, reflect.Int16, reflect.Int8:
+		return strconv.FormatInt(reflectValue.Int(), 10), false, nil
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		return strconv.FormatUint(reflectValue.Uint(), 10), false, nil
+	case reflect.String:
+		return reflectValue.String(), false, nil
+	case reflect.Slice, reflect.Map:
+		if reflectValue.IsNil() {
+			return "", true, nil
+		}
+	}
+	data, err := json.Marshal(value)
+	return string(data), false, err
+}
+
+//NewEncoder creates CSV encoder
+func NewEncoder(writer io.Writer, options ...Option) *Encoder {
+	return &Encoder{writer: writer, config: newConfig(options)}
+}
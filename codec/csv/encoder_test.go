@@ -0,0 +1,117 @@
+package csv
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"github.com/viant/toolbox/format"
+	"testing"
+	"time"
+)
+
+func TestEncoder_Encode(t *testing.T) {
+	testCases := []struct {
+		description string
+		options     []Option
+		hide        string
+		outputCase  bool
+		expect      string
+	}{
+		{
+			description: "default options",
+			expect:      "id,name,score,created,tags\n1,\"Foo, Bar\",1.5,2021-03-04,\"[\"\"a\"\"]\"\n2,,,,\n",
+		},
+		{
+			description: "hidden field and output case format",
+			hide:        "score",
+			outputCase:  true,
+			expect:      "Id,Name,Created,Tags\n1,\"Foo, Bar\",2021-03-04,\"[\"\"a\"\"]\"\n2,,,\n",
+		},
+		{
+			description: "tsv with null representation",
+			options:     []Option{DelimiterOpt('\t'), NullOpt("NULL")},
+			expect:      "id\tname\tscore\tcreated\ttags\n1\tFoo, Bar\t1.5\t2021-03-04\t\"[\"\"a\"\"]\"\n2\tNULL\tNULL\tNULL\tNULL\n",
+		},
+		{
+			description: "quote all",
+			options:     []Option{QuotingOpt(QuoteAll)},
+			expect:      "\"id\",\"name\",\"score\",\"created\",\"tags\"\n\"1\",\"Foo, Bar\",\"1.5\",\"2021-03-04\",\"[\"\"a\"\"]\"\n\"2\",\"\",\"\",\"\",\"\"\n",
+		},
+		{
+			description: "quote none",
+			options:     []Option{QuotingOpt(QuoteNone), DelimiterOpt('|')},
+			expect:      "id|name|score|created|tags\n1|Foo, Bar|1.5|2021-03-04|[\"a\"]\n2||||\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		provider, err := gtly.NewProvider("foo",
+			gtly.NewField("id", gtly.FieldTypeInt),
+			gtly.NewField("name", gtly.FieldTypeString),
+			gtly.NewField("score", gtly.FieldTypeFloat64),
+			gtly.NewField("created", gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02")),
+			gtly.NewField("tags", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeString)),
+		)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		if testCase.hide != "" {
+			provider.Hide(testCase.hide)
+		}
+		if testCase.outputCase {
+			_ = provider.OutputCaseFormat(format.CaseLowerCamel, format.CaseUpperCamel)
+		}
+		array := provider.NewArray()
+		item := provider.NewObject()
+		item.SetValue("id", 1)
+		item.SetValue("name", "Foo, Bar")
+		item.SetValue("score", 1.5)
+		item.SetValue("created", time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC))
+		item.SetValue("tags", []string{"a"})
+		array.AddObject(item)
+		item = provider.NewObject()
+		item.SetValue("id", 2)
+		array.AddObject(item)
+
+		writer := new(bytes.Buffer)
+		encoder := NewEncoder(writer, testCase.options...)
+		if !assert.Nil(t, encoder.Encode(array), testCase.description) {
+			continue
+		}
+		assert.Equal(t, testCase.expect, writer.String(), testCase.description)
+		assert.Equal(t, 3, encoder.Rows(), testCase.description)
+	}
+}
+
+func TestEncoder_RoundTrip(t *testing.T) {
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt64),
+		gtly.NewField("name", gtly.FieldTypeString),
+		gtly.NewField("data", gtly.FieldTypeBytes),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	array := provider.NewArray()
+	item := provider.NewObject()
+	item.SetValue("id", int64(10))
+	item.SetValue("name", " multi\nline \"text\"")
+	item.SetValue("data", []byte("abc"))
+	array.AddObject(item)
+
+	writer := new(bytes.Buffer)
+	if !assert.Nil(t, NewEncoder(writer, DelimiterOpt('\t')).Encode(array)) {
+		return
+	}
+	decoded := provider.NewArray()
+	if !assert.Nil(t, NewDecoder(writer, provider, DelimiterOpt('\t')).Decode(decoded)) {
+		return
+	}
+	assert.Equal(t, 1, decoded.Size())
+	_ = decoded.Objects(func(item *gtly.Object) (bool, error) {
+		assert.EqualValues(t, int64(10), item.Value("id"))
+		assert.EqualValues(t, " multi\nline \"text\"", item.Value("name"))
+		assert.EqualValues(t, []byte("abc"), item.Value("data"))
+		return true, nil
+	})
+}
@@ -0,0 +1,63 @@
+package csv
+
+import "github.com/viant/toolbox/format"
+
+//Quoting represents writer quoting mode
+type Quoting int
+
+const (
+	//QuoteMinimal quotes only cells containing delimiter, quote or line break
+	QuoteMinimal = Quoting(iota)
+	//QuoteAll quotes every cell
+	QuoteAll
+	//QuoteNone never quotes, reader accepts bare quotes
+	QuoteNone
+)
+
+//Option represents CSV codec option
+type Option func(c *config)
+
+type config struct {
+	delimiter    rune
+	quoting      Quoting
+	null         string
+	sourceFormat format.Case
+	headerFormat format.Case
+}
+
+func newConfig(options []Option) *config {
+	result := &config{delimiter: ',', quoting: QuoteMinimal}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+//DelimiterOpt returns cell delimiter option, use '\t' for TSV
+func DelimiterOpt(delimiter rune) Option {
+	return func(c *config) {
+		c.delimiter = delimiter
+	}
+}
+
+//QuotingOpt returns quoting mode option
+func QuotingOpt(quoting Quoting) Option {
+	return func(c *config) {
+		c.quoting = quoting
+	}
+}
+
+//NullOpt returns null representation option, empty cell represents null by default
+func NullOpt(null string) Option {
+	return func(c *config) {
+		c.null = null
+	}
+}
+
+//CaseFormatOpt returns header case format option, field names in source case are formatted to header case when matching header
+func CaseFormatOpt(source, header format.Case) Option {
+	return func(c *config) {
+		c.sourceFormat = source
+		c.headerFormat = header
+	}
+}
@@ -8,6 +8,7 @@ import (
 //Collection JSON collection wrapper
 type Collection struct {
 	Collection gtly.Collection
+	provider   *gtly.Provider
 }
 
 //IsNil return true if collection is empty
@@ -24,7 +25,23 @@ func (c Collection) MarshalJSONArray(enc *gojay.Encoder) {
 		return
 	}
 	c.Collection.Objects(func(item *gtly.Object) (b bool, err error) {
-		enc.AddObject(&Object{item})
+		enc.AddObject(item)
 		return true, nil
 	})
 }
+
+//UnmarshalJSONArray decodes JSON array item into a new collection object, null items are skipped
+func (c *Collection) UnmarshalJSONArray(dec *gojay.Decoder) error {
+	if c.provider == nil {
+		c.provider = &gtly.Provider{Proto: c.Collection.Proto()}
+	}
+	item := c.provider.NewObject()
+	if err := dec.Object(item); err != nil {
+		return err
+	}
+	if item.IsNil() {
+		return nil
+	}
+	c.Collection.AddObject(item)
+	return nil
+}
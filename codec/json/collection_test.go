@@ -0,0 +1,56 @@
+package json
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"testing"
+)
+
+func TestCollection_UnmarshalJSONArray(t *testing.T) {
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("city", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	data := []byte(`[{"id":1,"city":"Warsaw"},{"id":2,"city":"Cracow"},null,{"id":3,"city":"Warsaw"}]`)
+
+	testCases := []struct {
+		description string
+		collection  gtly.Collection
+		expectSize  int
+	}{
+		{
+			description: "array",
+			collection:  provider.NewArray(),
+			expectSize:  3,
+		},
+		{
+			description: "map",
+			collection:  provider.NewMap(gtly.NewKeyProvider("id")),
+			expectSize:  3,
+		},
+		{
+			description: "multimap",
+			collection:  provider.NewMultimap(gtly.NewKeyProvider("city")),
+			expectSize:  2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := Unmarshal(data, testCase.collection)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.Equal(t, testCase.expectSize, testCase.collection.Size(), testCase.description)
+		switch actual := testCase.collection.(type) {
+		case *gtly.Map:
+			assert.Equal(t, "Cracow", actual.Object(2).Value("city"), testCase.description)
+		case *gtly.Multimap:
+			assert.Equal(t, 2, actual.Slice("Warsaw").Size(), testCase.description)
+		case *gtly.Array:
+			assert.Equal(t, 1, actual.First().Value("id"), testCase.description)
+		}
+	}
+}
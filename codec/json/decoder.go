@@ -0,0 +1,30 @@
+package json
+
+import (
+	"github.com/francoispqt/gojay"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"io"
+)
+
+//Decoder represents JSON decoder reading from a stream
+type Decoder struct {
+	decoder *gojay.Decoder
+}
+
+//Decode decodes next JSON value into supplied destination
+func (d *Decoder) Decode(v interface{}) error {
+	switch raw := v.(type) {
+	case *gtly.Object:
+		return d.decoder.DecodeObject(raw)
+	case gtly.Collection:
+		return d.decoder.DecodeArray(&Collection{Collection: raw})
+	default:
+		return errors.Errorf("unsupported type: %T", v)
+	}
+}
+
+//NewDecoder creates a decoder for supplied reader
+func NewDecoder(reader io.Reader) *Decoder {
+	return &Decoder{decoder: gojay.NewDecoder(reader)}
+}
@@ -0,0 +1,32 @@
+package json
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"strings"
+	"testing"
+)
+
+func TestDecoder_Decode(t *testing.T) {
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	decoder := NewDecoder(strings.NewReader(`{"id":1,"name":"Foo"} {"id":2,"name":"Bar"}`))
+	var ids []int
+	var names []string
+	for i := 0; i < 2; i++ {
+		anObject := provider.NewObject()
+		if !assert.Nil(t, decoder.Decode(anObject)) {
+			return
+		}
+		ids = append(ids, anObject.Value("id").(int))
+		names = append(names, anObject.Value("name").(string))
+	}
+	assert.EqualValues(t, []int{1, 2}, ids)
+	assert.EqualValues(t, []string{"Foo", "Bar"}, names)
+	assert.NotNil(t, decoder.Decode(provider.Proto))
+}
@@ -11,13 +11,13 @@ import (
 func Marshal(v interface{}) ([]byte, error) {
 	switch raw := v.(type) {
 	case *gtly.Object:
-		return gojay.Marshal(&Object{raw})
+		return raw.MarshalJSON()
 	case *gtly.Map:
-		return gojay.Marshal(&Collection{Collection: raw})
+		return raw.MarshalJSON()
 	case *gtly.Multimap:
-		return gojay.Marshal(&Collection{Collection: raw})
+		return raw.MarshalJSON()
 	case *gtly.Array:
-		return gojay.Marshal(&Collection{Collection: raw})
+		return raw.MarshalJSON()
 	case []interface{}:
 		return gojay.Marshal(&Slice{raw})
 	default:
@@ -27,3 +27,15 @@ func Marshal(v interface{}) ([]byte, error) {
 		return nil, errors.Errorf("unsupported type: %T", v)
 	}
 }
+
+//Unmarshal decodes JSON into supplied destination, JSON array items are added to a collection
+func Unmarshal(data []byte, v interface{}) error {
+	switch raw := v.(type) {
+	case *gtly.Object:
+		return raw.UnmarshalJSON(data)
+	case gtly.Collection:
+		return gojay.UnmarshalJSONArray(data, &Collection{Collection: raw})
+	default:
+		return errors.Errorf("unsupported type: %T", v)
+	}
+}
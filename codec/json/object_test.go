@@ -9,6 +9,7 @@ import (
 	"github.com/viant/toolbox/format"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestObject_MarshalJSONObject(t *testing.T) {
@@ -80,6 +81,164 @@ func TestObject_MarshalJSONObject(t *testing.T) {
 	}
 }
 
+func TestObject_UnmarshalJSONObject(t *testing.T) {
+	nested, _ := gtly.NewProvider("nested",
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("zip", gtly.FieldTypeInt),
+	)
+	testCases := []struct {
+		description  string
+		fields       []*gtly.Field
+		inputFormat  format.Case
+		sourceFormat format.Case
+		json         string
+		expect       map[string]interface{}
+		expectMap    map[string]interface{}
+		expectUnset  []string
+		expectError  bool
+	}{
+		{
+			description: "decode base types",
+			fields: []*gtly.Field{
+				gtly.NewField("id", gtly.FieldTypeInt),
+				gtly.NewField("count", gtly.FieldTypeInt64),
+				gtly.NewField("name", gtly.FieldTypeString),
+				gtly.NewField("price", gtly.FieldTypeFloat64),
+				gtly.NewField("ratio", gtly.FieldTypeFloat32),
+				gtly.NewField("active", gtly.FieldTypeBool),
+				gtly.NewField("data", gtly.FieldTypeBytes),
+			},
+			json: `{"id":1,"count":20,"name":"Foo","price":10.5,"ratio":0.5,"active":true,"data":"AQI=","unknown":{"a":[1,2]}}`,
+			expect: map[string]interface{}{
+				"id":     1,
+				"count":  int64(20),
+				"name":   "Foo",
+				"price":  10.5,
+				"ratio":  float32(0.5),
+				"active": true,
+				"data":   []byte{1, 2},
+			},
+		},
+		{
+			description: "null and missing values stay unset",
+			fields: []*gtly.Field{
+				gtly.NewField("id", gtly.FieldTypeInt),
+				gtly.NewField("name", gtly.FieldTypeString),
+				gtly.NewField("price", gtly.FieldTypeFloat64),
+			},
+			json:        `{"id":3,"name":null}`,
+			expect:      map[string]interface{}{"id": 3},
+			expectUnset: []string{"name", "price"},
+		},
+		{
+			description: "input name and input case format",
+			fields: []*gtly.Field{
+				{Name: "Id", DataType: gtly.FieldTypeInt, InputName: "ID"},
+				{Name: "FirstName", DataType: gtly.FieldTypeString},
+			},
+			sourceFormat: format.CaseUpperCamel,
+			inputFormat:  format.CaseLowerUnderscore,
+			json:         `{"ID":7,"first_name":"Bob"}`,
+			expect: map[string]interface{}{
+				"Id":        7,
+				"FirstName": "Bob",
+			},
+			expectMap: map[string]interface{}{
+				"Id":        7,
+				"FirstName": "Bob",
+			},
+		},
+		{
+			description: "time with date layout",
+			fields: []*gtly.Field{
+				gtly.NewField("updated", gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02")),
+			},
+			json: `{"updated":"2021-11-01"}`,
+			expect: map[string]interface{}{
+				"updated": time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			description: "time with invalid layout",
+			fields: []*gtly.Field{
+				gtly.NewField("updated", gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02")),
+			},
+			json:        `{"updated":"01/11/2021"}`,
+			expectError: true,
+		},
+		{
+			description: "arrays and nested object",
+			fields: []*gtly.Field{
+				gtly.NewField("numbers", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeInt)),
+				gtly.NewField("tags", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeString)),
+				gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(nested)),
+			},
+			json: `{"numbers":[1,2,3],"tags":["a","b"],"address":{"city":"Warsaw","zip":123}}`,
+			expect: map[string]interface{}{
+				"numbers": []int{1, 2, 3},
+				"tags":    []string{"a", "b"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		provider, err := gtly.NewProvider(testCase.description, testCase.fields...)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		if testCase.inputFormat != testCase.sourceFormat {
+			_ = provider.InputCaseFormat(testCase.sourceFormat, testCase.inputFormat)
+		}
+		anObject := provider.NewObject()
+		err = Unmarshal([]byte(testCase.json), anObject)
+		if testCase.expectError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		for k, v := range testCase.expect {
+			assert.EqualValues(t, v, anObject.Value(k), testCase.description+" "+k)
+		}
+		for _, k := range testCase.expectUnset {
+			assert.False(t, anObject.SetAt(provider.Field(k).Index), testCase.description+" "+k)
+		}
+		if testCase.expectMap != nil {
+			assert.EqualValues(t, testCase.expectMap, anObject.AsMap(), testCase.description+" output names")
+		}
+	}
+}
+
+func TestObject_UnmarshalJSONObject_Nested(t *testing.T) {
+	nested, _ := gtly.NewProvider("nested",
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("zip", gtly.FieldTypeInt),
+	)
+	provider, _ := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(nested)),
+		gtly.NewField("addresses", gtly.FieldTypeArray, gtly.ProviderOpt(nested)),
+	)
+	anObject := provider.NewObject()
+	err := Unmarshal([]byte(`{"id":1,"address":{"city":"Warsaw","zip":123},"addresses":[{"city":"Cracow"},{"zip":3}]}`), anObject)
+	if !assert.Nil(t, err) {
+		return
+	}
+	address, ok := anObject.Value("address").(*gtly.Object)
+	if assert.True(t, ok) {
+		assert.EqualValues(t, map[string]interface{}{"city": "Warsaw", "zip": 123}, address.AsMap())
+	}
+	addresses, ok := anObject.Value("addresses").(*gtly.Array)
+	if assert.True(t, ok) {
+		assert.Equal(t, 2, addresses.Size())
+		assert.False(t, addresses.First().SetAt(nested.Field("zip").Index))
+	}
+	data, err := Marshal(anObject)
+	assert.Nil(t, err)
+	assert.EqualValues(t, `{"id":1,"address":{"city":"Warsaw","zip":123},"addresses":[{"city":"Cracow","zip":null},{"city":null,"zip":3}]}`, string(data))
+}
+
 // Benchmarks
 var anObject *Object
 
@@ -107,3 +266,13 @@ func BenchmarkObject_MarshalJSONObject(b *testing.B) {
 		gojay.MarshalJSONObject(anObject)
 	}
 }
+
+var anObjectJSON = []byte(`{"Id":10,"Name":"some name","Price":100.5}`)
+
+func BenchmarkObject_UnmarshalJSONObject(b *testing.B) {
+	b.ReportAllocs()
+	provider := &gtly.Provider{Proto: anObject.Proto()}
+	for i := 0; i < b.N; i++ {
+		_ = Unmarshal(anObjectJSON, provider.NewObject())
+	}
+}
@@ -35,7 +35,7 @@ func (s Slice) MarshalJSONArray(enc *gojay.Encoder) {
 		}
 		provider, err := gtly.NewProvider("foo", fields...)
 		if object, err := provider.Object(item); err == nil {
-			item = &Object{object}
+			item = object
 		}
 		enc.AddInterface(item)
 		return true
@@ -0,0 +1,78 @@
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"github.com/viant/gtly/codec/json"
+	"io"
+)
+
+//Decoder represents NDJSON decoder, each line is decoded into a separate object
+type Decoder struct {
+	reader   *bufio.Reader
+	provider *gtly.Provider
+	buffer   []byte
+	line     int
+}
+
+//Objects calls handler with every decoded line object, blank lines are skipped
+func (d *Decoder) Objects(handler func(item *gtly.Object) (toContinue bool, err error)) error {
+	for {
+		line, err := d.readLine()
+		if len(bytes.TrimSpace(line)) > 0 {
+			object := d.provider.NewObject()
+			if decodeErr := json.Unmarshal(line, object); decodeErr != nil {
+				return errors.Wrapf(decodeErr, "failed to decode line %v", d.line)
+			}
+			toContinue, handlerErr := handler(object)
+			if handlerErr != nil {
+				return errors.Wrapf(handlerErr, "failed to handle line %v", d.line)
+			}
+			if !toContinue {
+				return nil
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read line %v", d.line)
+		}
+	}
+}
+
+//Decode adds every decoded line object to supplied collection
+func (d *Decoder) Decode(collection gtly.Collection) error {
+	return d.Objects(func(item *gtly.Object) (bool, error) {
+		collection.AddObject(item)
+		return true, nil
+	})
+}
+
+//Line returns last read line number
+func (d *Decoder) Line() int {
+	return d.line
+}
+
+func (d *Decoder) readLine() ([]byte, error) {
+	d.line++
+	d.buffer = d.buffer[:0]
+	for {
+		fragment, err := d.reader.ReadSlice('\n')
+		d.buffer = append(d.buffer, fragment...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		return d.buffer, err
+	}
+}
+
+//NewDecoder creates NDJSON decoder, provider is used to create line objects
+func NewDecoder(reader io.Reader, provider *gtly.Provider) *Decoder {
+	return &Decoder{
+		reader:   bufio.NewReader(reader),
+		provider: provider,
+	}
+}
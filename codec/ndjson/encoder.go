@@ -0,0 +1,52 @@
+package ndjson
+
+import (
+	"github.com/francoispqt/gojay"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"github.com/viant/gtly/codec/json"
+	"io"
+)
+
+//Encoder represents NDJSON encoder, each object is written as a single line
+type Encoder struct {
+	writer  io.Writer
+	encoder *gojay.Encoder
+	line    int
+}
+
+//Encode writes every collection object as a separate line
+func (e *Encoder) Encode(collection gtly.Collection) error {
+	return collection.Objects(func(item *gtly.Object) (bool, error) {
+		err := e.EncodeObject(item)
+		return err == nil, err
+	})
+}
+
+//EncodeObject writes an object as a single line
+func (e *Encoder) EncodeObject(object *gtly.Object) error {
+	e.line++
+	if object == nil {
+		return errors.Errorf("failed to encode line %v: object was nil", e.line)
+	}
+	if err := e.encoder.EncodeObject(&json.Object{Object: object}); err != nil {
+		return errors.Wrapf(err, "failed to encode line %v", e.line)
+	}
+	if _, err := e.writer.Write(newLine); err != nil {
+		return errors.Wrapf(err, "failed to encode line %v", e.line)
+	}
+	return nil
+}
+
+//Lines returns number of encoded lines
+func (e *Encoder) Lines() int {
+	return e.line
+}
+
+//NewEncoder creates NDJSON encoder
+func NewEncoder(writer io.Writer) *Encoder {
+	return &Encoder{
+		writer:  writer,
+		encoder: gojay.NewEncoder(writer),
+	}
+}
@@ -0,0 +1,4 @@
+//Package ndjson defines newline delimited JSON stream encoder and decoder for gtly objects
+package ndjson
+
+var newLine = []byte{'\n'}
@@ -0,0 +1,99 @@
+package ndjson
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"strings"
+	"testing"
+)
+
+func TestEncoder_Encode(t *testing.T) {
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	array := provider.NewArray()
+	for i, name := range []string{"Foo", "Bar"} {
+		item := provider.NewObject()
+		item.SetValue("id", i+1)
+		item.SetValue("name", name)
+		array.AddObject(item)
+	}
+	writer := new(bytes.Buffer)
+	encoder := NewEncoder(writer)
+	if !assert.Nil(t, encoder.Encode(array)) {
+		return
+	}
+	assert.Equal(t, "{\"id\":1,\"name\":\"Foo\"}\n{\"id\":2,\"name\":\"Bar\"}\n", writer.String())
+	assert.Equal(t, 2, encoder.Lines())
+
+	decoded := provider.NewArray()
+	err = NewDecoder(writer, provider).Decode(decoded)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, decoded.Size())
+
+	err = encoder.EncodeObject(nil)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "failed to encode line 3: object was nil", err.Error())
+	}
+}
+
+func TestDecoder_Objects(t *testing.T) {
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	testCases := []struct {
+		description string
+		input       string
+		limit       int
+		expectIDs   []int
+		expectError string
+	}{
+		{
+			description: "all lines",
+			input:       "{\"id\":1}\n\n{\"id\":2}\r\n{\"id\":3}",
+			expectIDs:   []int{1, 2, 3},
+		},
+		{
+			description: "stop on handler",
+			input:       "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n",
+			limit:       2,
+			expectIDs:   []int{1, 2},
+		},
+		{
+			description: "invalid line",
+			input:       "{\"id\":1}\n{\"id\":\"x\"}\n",
+			expectIDs:   []int{1},
+			expectError: "line 2",
+		},
+		{
+			description: "long line",
+			input:       "{\"id\":1,\"name\":\"" + strings.Repeat("x", 10000) + "\"}\n",
+			expectIDs:   []int{1},
+		},
+	}
+
+	for _, testCase := range testCases {
+		var ids []int
+		err := NewDecoder(strings.NewReader(testCase.input), provider).Objects(func(item *gtly.Object) (bool, error) {
+			ids = append(ids, item.Value("id").(int))
+			return testCase.limit == 0 || len(ids) < testCase.limit, nil
+		})
+		assert.EqualValues(t, testCase.expectIDs, ids, testCase.description)
+		if testCase.expectError != "" {
+			if assert.NotNil(t, err, testCase.description) {
+				assert.Contains(t, err.Error(), testCase.expectError, testCase.description)
+			}
+			continue
+		}
+		assert.Nil(t, err, testCase.description)
+	}
+}
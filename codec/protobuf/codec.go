@@ -0,0 +1,435 @@
+package protobuf
+
+import (
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"github.com/viant/gtly/internal/memory"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+//collectionItemsNumber represents collection wrapper message items field number
+const collectionItemsNumber = 1
+
+//Codec represents protobuf wire format codec for objects sharing the same proto
+type Codec struct {
+	provider *gtly.Provider
+	message  *message
+	registry *registry
+}
+
+//Marshal encodes an object, only fields that were set are encoded
+func (c *Codec) Marshal(object *gtly.Object) ([]byte, error) {
+	return c.appendObject(nil, object), nil
+}
+
+//Unmarshal decodes protobuf data into an object
+func (c *Codec) Unmarshal(data []byte, object *gtly.Object) error {
+	return decodeMessage(c.message, data, object.Addr(), object)
+}
+
+//MarshalCollection encodes collection as a message with repeated collection objects
+func (c *Codec) MarshalCollection(collection gtly.Collection) ([]byte, error) {
+	var result []byte
+	err := collection.Objects(func(item *gtly.Object) (bool, error) {
+		result = appendTag(result, collectionItemsNumber, wireBytes)
+		result = appendBytes(result, c.appendObject(nil, item))
+		return true, nil
+	})
+	return result, err
+}
+
+//UnmarshalCollection decodes repeated collection objects, decoded objects are added to the collection
+func (c *Codec) UnmarshalCollection(data []byte, collection gtly.Collection) error {
+	in := &reader{data: data}
+	for !in.done() {
+		number, wireType, err := in.tag()
+		if err != nil {
+			return err
+		}
+		if number != collectionItemsNumber || wireType != wireBytes {
+			if err = in.skip(wireType); err != nil {
+				return err
+			}
+			continue
+		}
+		itemData, err := in.bytes()
+		if err != nil {
+			return err
+		}
+		item := c.provider.NewObject()
+		if err = c.Unmarshal(itemData, item); err != nil {
+			return err
+		}
+		collection.AddObject(item)
+	}
+	return nil
+}
+
+func (c *Codec) appendObject(buf []byte, object *gtly.Object) []byte {
+	return appendObject(buf, c.message, object)
+}
+
+func appendObject(buf []byte, aMessage *message, object *gtly.Object) []byte {
+	addr := object.Addr()
+	for _, aField := range aMessage.fields {
+		if !object.SetAt(aField.index) {
+			continue
+		}
+		buf = appendField(buf, aField, addr, false)
+	}
+	return buf
+}
+
+func appendMessage(buf []byte, aMessage *message, addr unsafe.Pointer) []byte {
+	for _, aField := range aMessage.fields {
+		buf = appendField(buf, aField, addr, true)
+	}
+	return buf
+}
+
+func appendField(buf []byte, aField *field, addr unsafe.Pointer, skipZero bool) []byte {
+	ptr := aField.xField.Pointer(addr)
+	if aField.pointer {
+		if ptr = *(*unsafe.Pointer)(ptr); ptr == nil {
+			return buf
+		}
+	}
+	if aField.kind == kindObject {
+		return appendObjectField(buf, aField, ptr)
+	}
+	if !aField.repeated {
+		if skipZero && isZero(&aField.value, ptr) {
+			return buf
+		}
+		return appendValue(appendTag(buf, aField.number, aField.wireType()), &aField.value, ptr)
+	}
+	slice := memory.Slice(aField.rType, ptr)
+	length := slice.Len()
+	if length == 0 {
+		return buf
+	}
+	data := unsafe.Pointer(slice.Pointer())
+	itemSize := aField.rType.Size()
+	if aField.packable() {
+		var packed []byte
+		for i := 0; i < length; i++ {
+			packed = appendValue(packed, &aField.value, unsafe.Pointer(uintptr(data)+uintptr(i)*itemSize))
+		}
+		return appendBytes(appendTag(buf, aField.number, wireBytes), packed)
+	}
+	for i := 0; i < length; i++ {
+		buf = appendTag(buf, aField.number, wireBytes)
+		buf = appendValue(buf, &aField.value, unsafe.Pointer(uintptr(data)+uintptr(i)*itemSize))
+	}
+	return buf
+}
+
+//appendObjectField appends nested object or array objects as embedded messages
+func appendObjectField(buf []byte, aField *field, ptr unsafe.Pointer) []byte {
+	if !aField.repeated {
+		if object := *(**gtly.Object)(ptr); object != nil {
+			buf = appendBytes(appendTag(buf, aField.number, wireBytes), appendObject(nil, aField.message, object))
+		}
+		return buf
+	}
+	if array := *(**gtly.Array)(ptr); array != nil {
+		_ = array.Objects(func(item *gtly.Object) (bool, error) {
+			buf = appendBytes(appendTag(buf, aField.number, wireBytes), appendObject(nil, aField.message, item))
+			return true, nil
+		})
+	}
+	return buf
+}
+
+func appendValue(buf []byte, aValue *value, ptr unsafe.Pointer) []byte {
+	switch aValue.kind {
+	case kindInt:
+		return appendVarint(buf, uint64(memory.ReadInt(aValue.rType.Kind(), ptr)))
+	case kindUint:
+		return appendVarint(buf, readUint(aValue.rType.Kind(), ptr))
+	case kindFloat32:
+		return appendFloat32(buf, *(*float32)(ptr))
+	case kindFloat64:
+		return appendFloat64(buf, *(*float64)(ptr))
+	case kindBool:
+		if *(*bool)(ptr) {
+			return append(buf, 1)
+		}
+		return append(buf, 0)
+	case kindString:
+		return appendBytes(buf, []byte(*(*string)(ptr)))
+	case kindBytes:
+		return appendBytes(buf, *(*[]byte)(ptr))
+	case kindTime:
+		ts := (*time.Time)(ptr)
+		var timestamp []byte
+		if seconds := ts.Unix(); seconds != 0 {
+			timestamp = appendVarint(appendTag(timestamp, 1, wireVarint), uint64(seconds))
+		}
+		if nanos := ts.Nanosecond(); nanos != 0 {
+			timestamp = appendVarint(appendTag(timestamp, 2, wireVarint), uint64(nanos))
+		}
+		return appendBytes(buf, timestamp)
+	}
+	return appendBytes(buf, appendMessage(nil, aValue.message, ptr))
+}
+
+func isZero(aValue *value, ptr unsafe.Pointer) bool {
+	switch aValue.kind {
+	case kindInt:
+		return memory.ReadInt(aValue.rType.Kind(), ptr) == 0
+	case kindUint:
+		return readUint(aValue.rType.Kind(), ptr) == 0
+	case kindFloat32:
+		return *(*float32)(ptr) == 0
+	case kindFloat64:
+		return *(*float64)(ptr) == 0
+	case kindBool:
+		return !*(*bool)(ptr)
+	case kindString:
+		return len(*(*string)(ptr)) == 0
+	case kindBytes:
+		return len(*(*[]byte)(ptr)) == 0
+	case kindTime:
+		return (*time.Time)(ptr).IsZero()
+	}
+	return false
+}
+
+//decodeMessage decodes message into struct addr, if object is not nil decoded fields are marked as set
+func decodeMessage(aMessage *message, data []byte, addr unsafe.Pointer, object *gtly.Object) error {
+	in := &reader{data: data}
+	for !in.done() {
+		number, wireType, err := in.tag()
+		if err != nil {
+			return err
+		}
+		aField, ok := aMessage.byNumber[number]
+		if !ok {
+			if err = in.skip(wireType); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = decodeField(in, aField, wireType, addr); err != nil {
+			return errors.Wrapf(err, "failed to decode %v.%v", aMessage.name, aField.name)
+		}
+		if object != nil {
+			object.MarkSetAt(aField.index)
+		}
+	}
+	return nil
+}
+
+func decodeField(in *reader, aField *field, wireType int, addr unsafe.Pointer) error {
+	ptr := aField.xField.Pointer(addr)
+	if aField.pointer {
+		target := (*unsafe.Pointer)(ptr)
+		if *target == nil {
+			*target = unsafe.Pointer(reflect.New(aField.xField.Type.Elem()).Pointer())
+		}
+		ptr = *target
+	}
+	if aField.kind == kindObject {
+		return decodeObjectField(in, aField, wireType, ptr)
+	}
+	if !aField.repeated {
+		if wireType != aField.wireType() {
+			return errors.Errorf("invalid wire type: %v", wireType)
+		}
+		return decodeValue(in, &aField.value, ptr)
+	}
+	slice := memory.Slice(aField.rType, ptr)
+	if wireType == wireBytes && aField.packable() {
+		packed, err := in.bytes()
+		if err != nil {
+			return err
+		}
+		packedReader := &reader{data: packed}
+		for !packedReader.done() {
+			item := reflect.New(aField.rType)
+			if err = decodeValue(packedReader, &aField.value, unsafe.Pointer(item.Pointer())); err != nil {
+				return err
+			}
+			slice.Set(reflect.Append(slice, item.Elem()))
+		}
+		return nil
+	}
+	if wireType != aField.wireType() {
+		return errors.Errorf("invalid wire type: %v", wireType)
+	}
+	item := reflect.New(aField.rType)
+	if err := decodeValue(in, &aField.value, unsafe.Pointer(item.Pointer())); err != nil {
+		return err
+	}
+	slice.Set(reflect.Append(slice, item.Elem()))
+	return nil
+}
+
+//decodeObjectField decodes embedded message into a new nested object, repeated items are added to nested array
+func decodeObjectField(in *reader, aField *field, wireType int, ptr unsafe.Pointer) error {
+	if wireType != wireBytes {
+		return errors.Errorf("invalid wire type: %v", wireType)
+	}
+	data, err := in.bytes()
+	if err != nil {
+		return err
+	}
+	provider := aField.message.provider
+	object := provider.NewObject()
+	if err = decodeMessage(aField.message, data, object.Addr(), object); err != nil {
+		return err
+	}
+	if !aField.repeated {
+		*(**gtly.Object)(ptr) = object
+		return nil
+	}
+	array := (**gtly.Array)(ptr)
+	if *array == nil {
+		*array = provider.NewArray()
+	}
+	(*array).AddObject(object)
+	return nil
+}
+
+func decodeValue(in *reader, aValue *value, ptr unsafe.Pointer) error {
+	switch aValue.kind {
+	case kindInt, kindUint, kindBool:
+		raw, err := in.varint()
+		if err != nil {
+			return err
+		}
+		switch aValue.kind {
+		case kindInt:
+			memory.WriteInt(aValue.rType.Kind(), ptr, int64(raw))
+		case kindUint:
+			writeUint(aValue.rType.Kind(), ptr, raw)
+		default:
+			*(*bool)(ptr) = raw != 0
+		}
+		return nil
+	case kindFloat32:
+		raw, err := in.fixed32()
+		if err != nil {
+			return err
+		}
+		*(*uint32)(ptr) = raw
+		return nil
+	case kindFloat64:
+		raw, err := in.fixed64()
+		if err != nil {
+			return err
+		}
+		*(*uint64)(ptr) = raw
+		return nil
+	}
+	data, err := in.bytes()
+	if err != nil {
+		return err
+	}
+	switch aValue.kind {
+	case kindString:
+		*(*string)(ptr) = string(data)
+	case kindBytes:
+		*(*[]byte)(ptr) = append([]byte{}, data...)
+	case kindTime:
+		return decodeTimestamp(data, (*time.Time)(ptr))
+	default:
+		return decodeMessage(aValue.message, data, ptr, nil)
+	}
+	return nil
+}
+
+func decodeTimestamp(data []byte, ts *time.Time) error {
+	in := &reader{data: data}
+	var seconds, nanos int64
+	for !in.done() {
+		number, wireType, err := in.tag()
+		if err != nil {
+			return err
+		}
+		if wireType != wireVarint || (number != 1 && number != 2) {
+			if err = in.skip(wireType); err != nil {
+				return err
+			}
+			continue
+		}
+		raw, err := in.varint()
+		if err != nil {
+			return err
+		}
+		if number == 1 {
+			seconds = int64(raw)
+		} else {
+			nanos = int64(int32(raw))
+		}
+	}
+	*ts = time.Unix(seconds, nanos).UTC()
+	return nil
+}
+
+func readUint(kind reflect.Kind, ptr unsafe.Pointer) uint64 {
+	switch kind {
+	case reflect.Uint:
+		return uint64(*(*uint)(ptr))
+	case reflect.Uint32:
+		return uint64(*(*uint32)(ptr))
+	case reflect.Uint16:
+		return uint64(*(*uint16)(ptr))
+	case reflect.Uint8:
+		return uint64(*(*uint8)(ptr))
+	}
+	return *(*uint64)(ptr)
+}
+
+func writeUint(kind reflect.Kind, ptr unsafe.Pointer, value uint64) {
+	switch kind {
+	case reflect.Uint:
+		*(*uint)(ptr) = uint(value)
+	case reflect.Uint32:
+		*(*uint32)(ptr) = uint32(value)
+	case reflect.Uint16:
+		*(*uint16)(ptr) = uint16(value)
+	case reflect.Uint8:
+		*(*uint8)(ptr) = uint8(value)
+	default:
+		*(*uint64)(ptr) = value
+	}
+}
+
+//NewCodec creates a codec for supplied proto, field numbers default to Field.Index + 1
+func NewCodec(proto *gtly.Proto, options ...Option) (*Codec, error) {
+	config := &config{numbers: map[string]int{}}
+	for _, option := range options {
+		option(config)
+	}
+	result := &Codec{
+		provider: &gtly.Provider{Proto: proto},
+		registry: newRegistry(),
+	}
+	name := config.name
+	if name == "" {
+		name = proto.SimpleName()
+	}
+	result.message = newMessage(result.registry.uniqueName(messageName(name)))
+	result.registry.messages = append(result.registry.messages, result.message)
+	fields := proto.Fields()
+	for i := range fields {
+		protoField := &fields[i]
+		number, ok := config.numbers[protoField.Name]
+		if !ok {
+			number = protoField.Index + 1
+		}
+		aField, err := result.registry.newField(protoField.OutputName(), number, proto.AccessorAt(protoField.Index).Field, protoField.ItemProvider())
+		if err != nil {
+			return nil, err
+		}
+		aField.index = protoField.Index
+		if err = result.message.addField(aField); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,159 @@
+package protobuf
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"testing"
+	"time"
+)
+
+func TestCodec_Marshal(t *testing.T) {
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	codec, err := NewCodec(provider.Proto)
+	if !assert.Nil(t, err) {
+		return
+	}
+	object := provider.NewObject()
+	object.SetValue("id", 150)
+	data, err := codec.Marshal(object)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x08, 0x96, 0x01}, data)
+
+	object.SetValue("name", "testing")
+	data, err = codec.Marshal(object)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x08, 0x96, 0x01, 0x12, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g'}, data)
+}
+
+func TestCodec_Unmarshal(t *testing.T) {
+	address, _ := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("zip", gtly.FieldTypeInt),
+	)
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("count", gtly.FieldTypeInt64),
+		gtly.NewField("ratio", gtly.FieldTypeFloat32),
+		gtly.NewField("price", gtly.FieldTypeFloat64),
+		gtly.NewField("active", gtly.FieldTypeBool),
+		gtly.NewField("name", gtly.FieldTypeString),
+		gtly.NewField("data", gtly.FieldTypeBytes),
+		gtly.NewField("updated", gtly.FieldTypeTime),
+		gtly.NewField("numbers", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeInt)),
+		gtly.NewField("tags", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeString)),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(address)),
+		gtly.NewField("addresses", gtly.FieldTypeArray, gtly.ProviderOpt(address)),
+		gtly.NewField("unset", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	codec, err := NewCodec(provider.Proto)
+	if !assert.Nil(t, err) {
+		return
+	}
+	home := address.NewObject()
+	home.SetValue("city", "Warsaw")
+	home.SetValue("zip", 0)
+	work := address.NewObject()
+	work.SetValue("zip", 31)
+
+	values := map[string]interface{}{
+		"id":        -3,
+		"count":     int64(1) << 40,
+		"ratio":     float32(0.25),
+		"price":     10.5,
+		"active":    false,
+		"name":      "Foo",
+		"data":      []byte{1, 2, 3},
+		"updated":   time.Date(2021, 11, 1, 10, 0, 0, 500, time.UTC),
+		"numbers":   []int{1, -2, 300},
+		"tags":      []string{"a", "", "c"},
+		"address":   home,
+		"addresses": address.NewArray(home, work),
+	}
+	object := provider.NewObject()
+	for k, v := range values {
+		object.SetValue(k, v)
+	}
+	data, err := codec.Marshal(object)
+	if !assert.Nil(t, err) {
+		return
+	}
+	decoded := provider.NewObject()
+	if !assert.Nil(t, codec.Unmarshal(data, decoded)) {
+		return
+	}
+	assert.EqualValues(t, object.AsMap(), decoded.AsMap())
+	assert.False(t, decoded.SetAt(provider.Field("unset").Index))
+
+	assert.NotNil(t, codec.Unmarshal(data[:len(data)-1], provider.NewObject()))
+}
+
+func TestCodec_Collection(t *testing.T) {
+	provider, _ := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("city", gtly.FieldTypeString),
+	)
+	codec, err := NewCodec(provider.Proto)
+	if !assert.Nil(t, err) {
+		return
+	}
+	array := provider.NewArray()
+	for i, city := range []string{"Warsaw", "Cracow", "Warsaw"} {
+		item := provider.NewObject()
+		item.SetValue("id", i)
+		item.SetValue("city", city)
+		array.AddObject(item)
+	}
+	data, err := codec.MarshalCollection(array)
+	if !assert.Nil(t, err) {
+		return
+	}
+	multimap := provider.NewMultimap(gtly.NewKeyProvider("city"))
+	if !assert.Nil(t, codec.UnmarshalCollection(data, multimap)) {
+		return
+	}
+	assert.Equal(t, 2, multimap.Slice("Warsaw").Size())
+	assert.Equal(t, 1, multimap.Slice("Cracow").Size())
+}
+
+func TestNewCodec(t *testing.T) {
+	provider, _ := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+	)
+	testCases := []struct {
+		description string
+		options     []Option
+		expectError bool
+	}{
+		{
+			description: "default numbers",
+		},
+		{
+			description: "overridden number",
+			options:     []Option{FieldNumberOpt("name", 10)},
+		},
+		{
+			description: "duplicated number",
+			options:     []Option{FieldNumberOpt("name", 1)},
+			expectError: true,
+		},
+		{
+			description: "reserved number",
+			options:     []Option{FieldNumberOpt("name", 19001)},
+			expectError: true,
+		},
+	}
+	for _, testCase := range testCases {
+		_, err := NewCodec(provider.Proto, testCase.options...)
+		assert.Equal(t, testCase.expectError, err != nil, testCase.description)
+	}
+}
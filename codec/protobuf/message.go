@@ -0,0 +1,299 @@
+package protobuf
+
+import (
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"github.com/viant/xunsafe"
+	"reflect"
+	"strconv"
+	"time"
+	"unicode"
+)
+
+var (
+	typeTime   = reflect.TypeOf(time.Time{})
+	typeBytes  = reflect.TypeOf([]byte{})
+	typeObject = reflect.TypeOf(&gtly.Object{})
+	typeArray  = reflect.TypeOf(&gtly.Array{})
+)
+
+//valueKind represents protobuf value encoding kind
+type valueKind int
+
+const (
+	kindInt = valueKind(iota)
+	kindUint
+	kindFloat32
+	kindFloat64
+	kindBool
+	kindString
+	kindBytes
+	kindTime
+	kindMessage
+	kindObject
+)
+
+//value represents protobuf encoded value type
+type value struct {
+	kind    valueKind
+	rType   reflect.Type
+	message *message
+}
+
+func (v *value) wireType() int {
+	switch v.kind {
+	case kindInt, kindUint, kindBool:
+		return wireVarint
+	case kindFloat32:
+		return wireFixed32
+	case kindFloat64:
+		return wireFixed64
+	}
+	return wireBytes
+}
+
+func (v *value) packable() bool {
+	return v.wireType() != wireBytes
+}
+
+//protoType returns .proto type name
+func (v *value) protoType() string {
+	switch v.kind {
+	case kindInt:
+		if v.rType.Size() < 8 {
+			return "int32"
+		}
+		return "int64"
+	case kindUint:
+		if v.rType.Size() < 8 {
+			return "uint32"
+		}
+		return "uint64"
+	case kindFloat32:
+		return "float"
+	case kindFloat64:
+		return "double"
+	case kindBool:
+		return "bool"
+	case kindString:
+		return "string"
+	case kindBytes:
+		return "bytes"
+	case kindTime:
+		return "google.protobuf.Timestamp"
+	}
+	return v.message.name
+}
+
+//field represents protobuf message field
+type field struct {
+	number   int
+	name     string
+	index    int
+	pointer  bool
+	repeated bool
+	value
+	xField *xunsafe.Field
+}
+
+func (f *field) protoType() string {
+	if f.repeated {
+		return "repeated " + f.value.protoType()
+	}
+	return f.value.protoType()
+}
+
+//message represents protobuf message, provider is set for proto based messages
+type message struct {
+	name     string
+	fields   []*field
+	byNumber map[int]*field
+	provider *gtly.Provider
+}
+
+func (m *message) addField(aField *field) error {
+	if aField.number < minFieldNumber || aField.number > maxFieldNumber ||
+		(aField.number >= minReservedFieldNumber && aField.number <= maxReservedFieldNumber) {
+		return errors.Errorf("invalid field number %v for %v.%v", aField.number, m.name, aField.name)
+	}
+	if prev, ok := m.byNumber[aField.number]; ok {
+		return errors.Errorf("duplicate field number %v for %v.%v and %v.%v", aField.number, m.name, prev.name, m.name, aField.name)
+	}
+	m.fields = append(m.fields, aField)
+	m.byNumber[aField.number] = aField
+	return nil
+}
+
+func newMessage(name string) *message {
+	return &message{name: name, byNumber: map[int]*field{}}
+}
+
+//registry represents compiled messages registry
+type registry struct {
+	messages []*message
+	byType   map[reflect.Type]*message
+	names    map[string]bool
+}
+
+func (r *registry) uniqueName(name string) string {
+	candidate := name
+	for i := 2; r.names[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	r.names[candidate] = true
+	return candidate
+}
+
+//structMessage returns message for a nested struct type, field numbers follow struct field position
+func (r *registry) structMessage(name string, structType reflect.Type) (*message, error) {
+	if result, ok := r.byType[structType]; ok {
+		return result, nil
+	}
+	result := newMessage(r.uniqueName(messageName(name)))
+	r.byType[structType] = result
+	r.messages = append(r.messages, result)
+	for i := 0; i < structType.NumField(); i++ {
+		xField := xunsafe.FieldByIndex(structType, i)
+		aField, err := r.newField(xField.Name, i+1, xField, nil)
+		if err != nil {
+			return nil, err
+		}
+		aField.index = -1
+		if err = result.addField(aField); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+//protoMessage returns message for provider proto, field numbers follow field index
+func (r *registry) protoMessage(name string, provider *gtly.Provider) (*message, error) {
+	if result, ok := r.byType[provider.Type()]; ok {
+		return result, nil
+	}
+	result := newMessage(r.uniqueName(messageName(name)))
+	result.provider = provider
+	r.byType[provider.Type()] = result
+	r.messages = append(r.messages, result)
+	fields := provider.Fields()
+	for i := range fields {
+		protoField := &fields[i]
+		aField, err := r.newField(protoField.OutputName(), protoField.Index+1, provider.AccessorAt(protoField.Index).Field, protoField.ItemProvider())
+		if err != nil {
+			return nil, err
+		}
+		aField.index = protoField.Index
+		if err = result.addField(aField); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (r *registry) newField(name string, number int, xField *xunsafe.Field, itemProvider *gtly.Provider) (*field, error) {
+	result := &field{name: fieldName(name), number: number, xField: xField}
+	if itemProvider != nil && (xField.Type == typeObject || xField.Type == typeArray) {
+		messageName := itemProvider.SimpleName()
+		if messageName == "" {
+			messageName = name
+		}
+		nested, err := r.protoMessage(messageName, itemProvider)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unsupported field %v", name)
+		}
+		result.value = value{kind: kindObject, rType: xField.Type, message: nested}
+		result.repeated = xField.Type == typeArray
+		return result, nil
+	}
+	rType := xField.Type
+	if rType.Kind() == reflect.Ptr {
+		result.pointer = true
+		rType = rType.Elem()
+	}
+	if rType.Kind() == reflect.Slice && rType != typeBytes {
+		result.repeated = true
+		rType = rType.Elem()
+	}
+	aValue, err := r.newValue(name, rType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unsupported field %v", name)
+	}
+	result.value = *aValue
+	return result, nil
+}
+
+func (r *registry) newValue(name string, rType reflect.Type) (*value, error) {
+	result := &value{rType: rType}
+	switch rType.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		result.kind = kindInt
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		result.kind = kindUint
+	case reflect.Float32:
+		result.kind = kindFloat32
+	case reflect.Float64:
+		result.kind = kindFloat64
+	case reflect.Bool:
+		result.kind = kindBool
+	case reflect.String:
+		result.kind = kindString
+	case reflect.Slice:
+		if rType != typeBytes {
+			return nil, errors.Errorf("unsupported type: %v", rType)
+		}
+		result.kind = kindBytes
+	case reflect.Struct:
+		if rType == typeTime {
+			result.kind = kindTime
+			break
+		}
+		nested, err := r.structMessage(name, rType)
+		if err != nil {
+			return nil, err
+		}
+		result.kind = kindMessage
+		result.message = nested
+	default:
+		return nil, errors.Errorf("unsupported type: %v", rType)
+	}
+	return result, nil
+}
+
+func newRegistry() *registry {
+	return &registry{byType: map[reflect.Type]*message{}, names: map[string]bool{}}
+}
+
+//messageName returns upper camel message name
+func messageName(name string) string {
+	result := make([]rune, 0, len(name))
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		result = append(result, r)
+	}
+	if len(result) == 0 || unicode.IsDigit(result[0]) {
+		result = append([]rune("Message"), result...)
+	}
+	return string(result)
+}
+
+//fieldName returns a valid proto field name
+func fieldName(name string) string {
+	result := []rune(name)
+	for i, r := range result {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			result[i] = '_'
+		}
+	}
+	if len(result) == 0 || unicode.IsDigit(result[0]) {
+		result = append([]rune("f_"), result...)
+	}
+	return string(result)
+}
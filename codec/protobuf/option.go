@@ -0,0 +1,23 @@
+package protobuf
+
+//Option represents codec option
+type Option func(c *config)
+
+type config struct {
+	name    string
+	numbers map[string]int
+}
+
+//FieldNumberOpt returns an option overriding protobuf field number for supplied field name
+func FieldNumberOpt(fieldName string, number int) Option {
+	return func(c *config) {
+		c.numbers[fieldName] = number
+	}
+}
+
+//MessageNameOpt returns an option overriding top level message name, proto simple name is used by default
+func MessageNameOpt(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
@@ -0,0 +1,38 @@
+package protobuf
+
+import (
+	"strconv"
+	"strings"
+)
+
+//Schema returns .proto (proto3) file text describing codec messages, collection wrapper message uses List suffix
+func (c *Codec) Schema() string {
+	builder := &strings.Builder{}
+	builder.WriteString("syntax = \"proto3\";\n")
+	for _, aMessage := range c.registry.messages {
+		if aMessage.usesTime() {
+			builder.WriteString("\nimport \"google/protobuf/timestamp.proto\";\n")
+			break
+		}
+	}
+	for _, aMessage := range c.registry.messages {
+		builder.WriteString("\nmessage " + aMessage.name + " {\n")
+		for _, aField := range aMessage.fields {
+			builder.WriteString("  " + aField.protoType() + " " + aField.name + " = " + strconv.Itoa(aField.number) + ";\n")
+		}
+		builder.WriteString("}\n")
+	}
+	builder.WriteString("\nmessage " + c.message.name + "List {\n")
+	builder.WriteString("  repeated " + c.message.name + " items = " + strconv.Itoa(collectionItemsNumber) + ";\n")
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+func (m *message) usesTime() bool {
+	for _, aField := range m.fields {
+		if aField.kind == kindTime {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package protobuf
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"testing"
+)
+
+func TestCodec_Schema(t *testing.T) {
+	address, _ := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+	)
+	provider, _ := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("price", gtly.FieldTypeFloat32),
+		gtly.NewField("updated", gtly.FieldTypeTime),
+		gtly.NewField("numbers", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeInt)),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(address)),
+	)
+	codec, err := NewCodec(provider.Proto, FieldNumberOpt("address", 10))
+	if !assert.Nil(t, err) {
+		return
+	}
+	expect := `syntax = "proto3";
+
+import "google/protobuf/timestamp.proto";
+
+message Foo {
+  int64 id = 1;
+  float price = 2;
+  google.protobuf.Timestamp updated = 3;
+  repeated int64 numbers = 4;
+  Address address = 10;
+}
+
+message Address {
+  string city = 1;
+}
+
+message FooList {
+  repeated Foo items = 1;
+}
+`
+	assert.Equal(t, expect, codec.Schema())
+}
@@ -0,0 +1,140 @@
+package protobuf
+
+import (
+	"github.com/pkg/errors"
+	"math"
+)
+
+const ( //Wire types
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+const (
+	minFieldNumber         = 1
+	maxFieldNumber         = 1<<29 - 1
+	minReservedFieldNumber = 19000
+	maxReservedFieldNumber = 19999
+)
+
+var errTruncated = errors.New("unexpected end of protobuf data")
+
+func appendVarint(buf []byte, value uint64) []byte {
+	for value >= 0x80 {
+		buf = append(buf, byte(value)|0x80)
+		value >>= 7
+	}
+	return append(buf, byte(value))
+}
+
+func appendTag(buf []byte, number int, wireType int) []byte {
+	return appendVarint(buf, uint64(number)<<3|uint64(wireType))
+}
+
+func appendFixed32(buf []byte, value uint32) []byte {
+	return append(buf, byte(value), byte(value>>8), byte(value>>16), byte(value>>24))
+}
+
+func appendFixed64(buf []byte, value uint64) []byte {
+	return append(buf, byte(value), byte(value>>8), byte(value>>16), byte(value>>24),
+		byte(value>>32), byte(value>>40), byte(value>>48), byte(value>>56))
+}
+
+func appendBytes(buf []byte, value []byte) []byte {
+	buf = appendVarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+func appendFloat32(buf []byte, value float32) []byte {
+	return appendFixed32(buf, math.Float32bits(value))
+}
+
+func appendFloat64(buf []byte, value float64) []byte {
+	return appendFixed64(buf, math.Float64bits(value))
+}
+
+//reader represents protobuf wire data reader
+type reader struct {
+	data []byte
+	pos  int
+}
+
+func (r *reader) done() bool {
+	return r.pos >= len(r.data)
+}
+
+func (r *reader) varint() (uint64, error) {
+	var result uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		if r.pos >= len(r.data) {
+			return 0, errTruncated
+		}
+		b := r.data[r.pos]
+		r.pos++
+		result |= uint64(b&0x7F) << shift
+		if b < 0x80 {
+			return result, nil
+		}
+	}
+	return 0, errors.New("protobuf varint overflow")
+}
+
+func (r *reader) tag() (int, int, error) {
+	value, err := r.varint()
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(value >> 3), int(value & 0x7), nil
+}
+
+func (r *reader) fixed32() (uint32, error) {
+	if r.pos+4 > len(r.data) {
+		return 0, errTruncated
+	}
+	data := r.data[r.pos:]
+	r.pos += 4
+	return uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16 | uint32(data[3])<<24, nil
+}
+
+func (r *reader) fixed64() (uint64, error) {
+	if r.pos+8 > len(r.data) {
+		return 0, errTruncated
+	}
+	data := r.data[r.pos:]
+	r.pos += 8
+	return uint64(data[0]) | uint64(data[1])<<8 | uint64(data[2])<<16 | uint64(data[3])<<24 |
+		uint64(data[4])<<32 | uint64(data[5])<<40 | uint64(data[6])<<48 | uint64(data[7])<<56, nil
+}
+
+func (r *reader) bytes() ([]byte, error) {
+	size, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if size > uint64(len(r.data)-r.pos) {
+		return nil, errTruncated
+	}
+	result := r.data[r.pos : r.pos+int(size)]
+	r.pos += int(size)
+	return result, nil
+}
+
+//skip skips value of supplied wire type
+func (r *reader) skip(wireType int) error {
+	var err error
+	switch wireType {
+	case wireVarint:
+		_, err = r.varint()
+	case wireFixed64:
+		_, err = r.fixed64()
+	case wireBytes:
+		_, err = r.bytes()
+	case wireFixed32:
+		_, err = r.fixed32()
+	default:
+		err = errors.Errorf("unsupported wire type: %v", wireType)
+	}
+	return err
+}
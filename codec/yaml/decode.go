@@ -0,0 +1,159 @@
+package yaml
+
+import (
+	"encoding/base64"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"github.com/viant/toolbox"
+	"reflect"
+	"time"
+)
+
+var (
+	typeTime   = reflect.TypeOf(time.Time{})
+	typeBytes  = reflect.TypeOf([]byte{})
+	typeObject = reflect.TypeOf(&gtly.Object{})
+	typeArray  = reflect.TypeOf(&gtly.Array{})
+)
+
+func decodeCollection(collection gtly.Collection, source interface{}) error {
+	if source == nil {
+		return nil
+	}
+	items, ok := source.([]interface{})
+	if !ok {
+		return errors.Errorf("expected YAML sequence, but had: %T", source)
+	}
+	provider := &gtly.Provider{Proto: collection.Proto()}
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+		values, ok := item.(map[interface{}]interface{})
+		if !ok {
+			return errors.Errorf("expected YAML mapping at %v, but had: %T", i, item)
+		}
+		object := provider.NewObject()
+		if err := decodeObject(object, values); err != nil {
+			return errors.Wrapf(err, "failed to decode item %v", i)
+		}
+		collection.AddObject(object)
+	}
+	return nil
+}
+
+//decodeObject sets object fields from YAML mapping, unknown keys are skipped
+func decodeObject(object *gtly.Object, values map[interface{}]interface{}) error {
+	proto := object.Proto()
+	for key, value := range values {
+		field := proto.Lookup(toolbox.AsString(key))
+		if field == nil || value == nil {
+			continue
+		}
+		mutator := proto.MutatorAt(field.Index)
+		if provider := field.ItemProvider(); provider != nil && (mutator.Type == typeObject || mutator.Type == typeArray) {
+			nested, err := decodeNested(provider, mutator.Type, value)
+			if err != nil {
+				return errors.Wrapf(err, "failed to decode %v", field.Name)
+			}
+			mutator.SetValue(object, nested)
+			continue
+		}
+		converted, err := convert(mutator.Type, value, field.TimeLayout())
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode %v", field.Name)
+		}
+		mutator.SetValue(object, converted.Interface())
+	}
+	return nil
+}
+
+//decodeNested decodes YAML mapping or sequence into a nested object or array created by supplied provider
+func decodeNested(provider *gtly.Provider, target reflect.Type, value interface{}) (interface{}, error) {
+	if target == typeArray {
+		array := provider.NewArray()
+		return array, decodeCollection(array, value)
+	}
+	values, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected YAML mapping, but had: %T", value)
+	}
+	object := provider.NewObject()
+	return object, decodeObject(object, values)
+}
+
+//convert converts YAML value to supplied type
+func convert(target reflect.Type, value interface{}, timeLayout string) (reflect.Value, error) {
+	switch target.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		converted, err := toolbox.ToInt(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(converted).Convert(target), nil
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Float32, reflect.Float64:
+		converted, err := toolbox.ToFloat(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(converted).Convert(target), nil
+	case reflect.Bool:
+		converted, err := toolbox.ToBoolean(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(converted), nil
+	case reflect.String:
+		return reflect.ValueOf(toolbox.AsString(value)).Convert(target), nil
+	case reflect.Interface:
+		return reflect.ValueOf(&value).Elem(), nil
+	case reflect.Ptr:
+		converted, err := convert(target.Elem(), value, timeLayout)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result := reflect.New(target.Elem())
+		result.Elem().Set(converted)
+		return result, nil
+	case reflect.Slice:
+		if target == typeBytes {
+			decoded, err := base64.StdEncoding.DecodeString(toolbox.AsString(value))
+			return reflect.ValueOf(decoded), err
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			return reflect.Value{}, errors.Errorf("expected YAML sequence, but had: %T", value)
+		}
+		result := reflect.MakeSlice(target, 0, len(items))
+		for _, item := range items {
+			converted, err := convert(target.Elem(), item, timeLayout)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			result = reflect.Append(result, converted)
+		}
+		return result, nil
+	case reflect.Struct:
+		if target == typeTime {
+			return convertTime(value, timeLayout)
+		}
+		result := reflect.New(target)
+		converter := toolbox.NewConverter(timeLayout, "")
+		if err := converter.AssignConverted(result.Interface(), value); err != nil {
+			return reflect.Value{}, errors.Wrapf(err, "unable to convert %T to %v", value, target)
+		}
+		return result.Elem(), nil
+	}
+	return reflect.Value{}, errors.Errorf("unsupported type: %v", target)
+}
+
+func convertTime(value interface{}, timeLayout string) (reflect.Value, error) {
+	if ts, ok := value.(time.Time); ok {
+		return reflect.ValueOf(ts), nil
+	}
+	ts, err := time.Parse(timeLayout, toolbox.AsString(value))
+	if err != nil {
+		return reflect.Value{}, errors.Wrapf(err, "invalid time layout %v", timeLayout)
+	}
+	return reflect.ValueOf(ts), nil
+}
@@ -0,0 +1,109 @@
+package yaml
+
+import (
+	"encoding/base64"
+	"github.com/viant/gtly"
+	"gopkg.in/yaml.v2"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+func encodeCollection(collection gtly.Collection) []yaml.MapSlice {
+	var result = make([]yaml.MapSlice, 0, collection.Size())
+	_ = collection.Objects(func(item *gtly.Object) (bool, error) {
+		result = append(result, encodeObject(item))
+		return true, nil
+	})
+	return result
+}
+
+func encodeObject(object *gtly.Object) yaml.MapSlice {
+	fields := object.Proto().Fields()
+	var result = make(yaml.MapSlice, 0, len(fields))
+	for i := range fields {
+		field := &fields[i]
+		if field.IsHidden() {
+			continue
+		}
+		value, ok := object.ValueAt(field.Index)
+		omitEmpty := field.ShallOmitEmpty()
+		if omitEmpty && !ok {
+			continue
+		}
+		value = encodeValue(value, field.TimeLayout())
+		if omitEmpty && value == "" {
+			continue
+		}
+		result = append(result, yaml.MapItem{Key: field.OutputName(), Value: value})
+	}
+	return result
+}
+
+func encodeValue(value interface{}, timeLayout string) interface{} {
+	switch actual := gtly.Value(value).(type) {
+	case nil:
+		return nil
+	case time.Time:
+		return actual.Format(timeLayout)
+	case *time.Time:
+		if actual == nil {
+			return nil
+		}
+		return actual.Format(timeLayout)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(actual)
+	case *gtly.Object:
+		return encodeObject(actual)
+	case gtly.Collection:
+		return encodeCollection(actual)
+	case string, int, int64, float32, float64, bool:
+		return actual
+	}
+	addressable := reflect.New(reflect.TypeOf(value)).Elem()
+	addressable.Set(reflect.ValueOf(value))
+	return encodeReflectValue(addressable, timeLayout)
+}
+
+//encodeReflectValue encodes nested struct based values, value has to be addressable to access unexported struct fields
+func encodeReflectValue(value reflect.Value, timeLayout string) interface{} {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return encodeReflectValue(value.Elem(), timeLayout)
+	case reflect.Struct:
+		if value.Type() == typeTime {
+			return encodeValue(valueInterface(value), timeLayout)
+		}
+		result := make(yaml.MapSlice, 0, value.NumField())
+		for i := 0; i < value.NumField(); i++ {
+			result = append(result, yaml.MapItem{
+				Key:   value.Type().Field(i).Name,
+				Value: encodeReflectValue(value.Field(i), timeLayout),
+			})
+		}
+		return result
+	case reflect.Slice:
+		if value.IsNil() {
+			return nil
+		}
+		if value.Type() == typeBytes {
+			return encodeValue(valueInterface(value), timeLayout)
+		}
+		result := make([]interface{}, value.Len())
+		for i := range result {
+			result[i] = encodeReflectValue(value.Index(i), timeLayout)
+		}
+		return result
+	}
+	return valueInterface(value)
+}
+
+func valueInterface(value reflect.Value) interface{} {
+	if value.CanInterface() {
+		return value.Interface()
+	}
+	return reflect.NewAt(value.Type(), unsafe.Pointer(value.UnsafeAddr())).Elem().Interface()
+}
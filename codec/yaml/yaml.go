@@ -0,0 +1,39 @@
+package yaml
+
+import (
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"gopkg.in/yaml.v2"
+)
+
+//Marshal converts an object or collection to YAML
+func Marshal(v interface{}) ([]byte, error) {
+	switch raw := v.(type) {
+	case *gtly.Object:
+		return yaml.Marshal(encodeObject(raw))
+	case gtly.Collection:
+		return yaml.Marshal(encodeCollection(raw))
+	default:
+		return nil, errors.Errorf("unsupported type: %T", v)
+	}
+}
+
+//Unmarshal decodes YAML into an object or collection, YAML sequence items are added to a collection
+func Unmarshal(data []byte, v interface{}) error {
+	var source interface{}
+	if err := yaml.Unmarshal(data, &source); err != nil {
+		return err
+	}
+	switch raw := v.(type) {
+	case *gtly.Object:
+		values, ok := source.(map[interface{}]interface{})
+		if !ok {
+			return errors.Errorf("expected YAML mapping, but had: %T", source)
+		}
+		return decodeObject(raw, values)
+	case gtly.Collection:
+		return decodeCollection(raw, source)
+	default:
+		return errors.Errorf("unsupported type: %T", v)
+	}
+}
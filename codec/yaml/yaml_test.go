@@ -0,0 +1,124 @@
+package yaml
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"github.com/viant/toolbox/format"
+	"testing"
+	"time"
+)
+
+func TestMarshal(t *testing.T) {
+	address, _ := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+	)
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("Id", gtly.FieldTypeInt),
+		gtly.NewField("FirstName", gtly.FieldTypeString),
+		gtly.NewField("Secret", gtly.FieldTypeString),
+		gtly.NewField("Description", gtly.FieldTypeString, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("Comment", gtly.FieldTypeString),
+		gtly.NewField("Updated", gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02")),
+		gtly.NewField("Numbers", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeInt)),
+		gtly.NewField("Address", gtly.FieldTypeObject, gtly.ProviderOpt(address)),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_ = provider.OutputCaseFormat(format.CaseUpperCamel, format.CaseLowerUnderscore)
+	provider.Hide("Secret")
+	home := address.NewObject()
+	home.SetValue("city", "Warsaw")
+
+	object := provider.NewObject()
+	object.SetValue("Id", 1)
+	object.SetValue("FirstName", "Adam")
+	object.SetValue("Secret", "xyz")
+	object.SetValue("Updated", time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC))
+	object.SetValue("Numbers", []int{1, 2})
+	object.SetValue("Address", home.Interface())
+
+	data, err := Marshal(object)
+	if !assert.Nil(t, err) {
+		return
+	}
+	expect := `id: 1
+first_name: Adam
+comment: null
+updated: "2021-11-01"
+numbers:
+- 1
+- 2
+address:
+  city: Warsaw
+`
+	assert.Equal(t, expect, string(data))
+
+	data, err = Marshal(provider.NewArray(object))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "- id: 1\n", string(data[:8]))
+	_, err = Marshal("abc")
+	assert.NotNil(t, err)
+}
+
+func TestUnmarshal(t *testing.T) {
+	address, _ := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("zip", gtly.FieldTypeInt),
+	)
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("count", gtly.FieldTypeInt64),
+		gtly.NewField("price", gtly.FieldTypeFloat64),
+		gtly.NewField("active", gtly.FieldTypeBool),
+		gtly.NewField("name", gtly.FieldTypeString),
+		gtly.NewField("data", gtly.FieldTypeBytes),
+		gtly.NewField("updated", gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02")),
+		gtly.NewField("tags", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeString)),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(address)),
+		gtly.NewField("addresses", gtly.FieldTypeArray, gtly.ProviderOpt(address)),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	object := provider.NewObject()
+	err = Unmarshal([]byte(`
+id: 3
+count: 40
+price: 1.5
+active: true
+name: Foo
+data: AQI=
+updated: "2021-11-01"
+tags: [a, b]
+address:
+  city: Warsaw
+  zip: 12
+addresses:
+  - city: Cracow
+unknown: x
+`), object)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, 3, object.Value("id"))
+	assert.EqualValues(t, int64(40), object.Value("count"))
+	assert.EqualValues(t, 1.5, object.Value("price"))
+	assert.EqualValues(t, true, object.Value("active"))
+	assert.EqualValues(t, "Foo", object.Value("name"))
+	assert.EqualValues(t, []byte{1, 2}, object.Value("data"))
+	assert.EqualValues(t, time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC), object.Value("updated"))
+	assert.EqualValues(t, []string{"a", "b"}, object.Value("tags"))
+	assert.EqualValues(t, map[string]interface{}{"city": "Warsaw", "zip": 12}, object.Value("address").(*gtly.Object).AsMap())
+	assert.EqualValues(t, 1, object.Value("addresses").(*gtly.Array).Size())
+
+	aMap := provider.NewMap(gtly.NewKeyProvider("id"))
+	err = Unmarshal([]byte("- id: 1\n  name: a\n- id: 2\n  name: b\n"), aMap)
+	assert.Nil(t, err)
+	assert.Equal(t, "b", aMap.Object(2).Value("name"))
+
+	assert.NotNil(t, Unmarshal([]byte("id: abc"), provider.NewObject()))
+	assert.NotNil(t, Unmarshal([]byte("- 1"), provider.NewObject()))
+}
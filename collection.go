@@ -17,3 +17,33 @@ type Collection interface {
 	//First returns first object
 	First() *Object
 }
+
+func find(collection Collection, predicate Predicate) (*Object, error) {
+	matcher, err := predicate(collection.Proto())
+	if err != nil {
+		return nil, err
+	}
+	var result *Object
+	err = collection.Objects(func(item *Object) (bool, error) {
+		if matcher(item) {
+			result = item
+		}
+		return result == nil, nil
+	})
+	return result, err
+}
+
+func findAll(collection Collection, predicate Predicate) ([]*Object, error) {
+	matcher, err := predicate(collection.Proto())
+	if err != nil {
+		return nil, err
+	}
+	var result = make([]*Object, 0)
+	err = collection.Objects(func(item *Object) (bool, error) {
+		if matcher(item) {
+			result = append(result, item)
+		}
+		return true, nil
+	})
+	return result, err
+}
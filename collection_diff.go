@@ -0,0 +1,292 @@
+package gtly
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"github.com/viant/toolbox"
+	"reflect"
+)
+
+//ChangeType represents collection diff change type
+type ChangeType string
+
+const (
+	//ChangeAdded represents object present only in the new collection
+	ChangeAdded = ChangeType("added")
+	//ChangeRemoved represents object present only in the old collection
+	ChangeRemoved = ChangeType("removed")
+	//ChangeModified represents object with at least one changed field
+	ChangeModified = ChangeType("modified")
+
+	defaultChangeColumn = "changeType"
+)
+
+//FieldChange represents changed field values, unset value is reported as nil
+type FieldChange struct {
+	Name     string
+	OldValue interface{}
+	NewValue interface{}
+}
+
+//ObjectChange represents keyed object change
+type ObjectChange struct {
+	Type   ChangeType
+	Key    interface{}
+	Old    *Object
+	New    *Object
+	Fields []*FieldChange
+}
+
+//CollectionDiff represents collections diff result, changes follow new collection order, removed objects come last
+type CollectionDiff struct {
+	Changes  []*ObjectChange
+	newProto *Proto
+}
+
+//Added returns added object changes
+func (d *CollectionDiff) Added() []*ObjectChange {
+	return d.changes(ChangeAdded)
+}
+
+//Removed returns removed object changes
+func (d *CollectionDiff) Removed() []*ObjectChange {
+	return d.changes(ChangeRemoved)
+}
+
+//Modified returns modified object changes
+func (d *CollectionDiff) Modified() []*ObjectChange {
+	return d.changes(ChangeModified)
+}
+
+func (d *CollectionDiff) changes(changeType ChangeType) []*ObjectChange {
+	var result []*ObjectChange
+	for _, change := range d.Changes {
+		if change.Type == changeType {
+			result = append(result, change)
+		}
+	}
+	return result
+}
+
+//Array returns changed objects with new collection fields followed by change type column, removed objects use old values.
+//Empty column name defaults to changeType
+func (d *CollectionDiff) Array(changeColumn string) (*Array, error) {
+	if changeColumn == "" {
+		changeColumn = defaultChangeColumn
+	}
+	protoFields := d.newProto.Fields()
+	fields := make([]*Field, 0, len(protoFields)+1)
+	for i := range protoFields {
+		source := &protoFields[i]
+		if source.Name == changeColumn {
+			return nil, errors.Errorf("change column %v collides with collection field, use a different column name", changeColumn)
+		}
+		fields = append(fields, &Field{
+			Name:          source.Name,
+			Type:          d.newProto.AccessorAt(source.Index).Type,
+			DataType:      source.DataType,
+			DataLayout:    source.DataLayout,
+			ComponentType: source.ComponentType,
+			itemProvider:  source.itemProvider,
+		})
+	}
+	fields = append(fields, NewField(changeColumn, FieldTypeString))
+	provider, err := NewProvider(d.newProto.SimpleName()+"_diff", fields...)
+	if err != nil {
+		return nil, err
+	}
+	changeMutator := provider.MutatorAt(len(protoFields))
+	result := provider.NewArray()
+	for _, change := range d.Changes {
+		target := provider.NewObject()
+		source := change.New
+		if change.Type == ChangeRemoved {
+			source = change.Old
+		}
+		sourceProto := source.Proto()
+		sourceFields := sourceProto.Fields()
+		for i := range sourceFields {
+			field := provider.Proto.Lookup(sourceFields[i].Name)
+			if field == nil || field.Index >= len(protoFields) {
+				continue
+			}
+			value, ok := source.ValueAt(sourceFields[i].Index)
+			if !ok {
+				continue
+			}
+			mutator := provider.MutatorAt(field.Index)
+			if sourceProto.AccessorAt(sourceFields[i].Index).Type != mutator.Type {
+				if value, err = convertValue(value, mutator.Type, field.TimeLayout()); err != nil {
+					return nil, errors.Wrapf(err, "failed to copy %v", field.Name)
+				}
+				if value == nil {
+					continue
+				}
+			}
+			mutator.SetValue(target, value)
+		}
+		changeMutator.String(target, string(change.Type))
+		result.AddObject(target)
+	}
+	return result, nil
+}
+
+//DiffCollections compares old and new collection objects matched by key, modified fields are compared by field data type.
+//Collections may use different protos, fields are matched by name, objects with nil or duplicated key are rejected
+func DiffCollections(before, after Collection, keyProvider KeyProvider) (*CollectionDiff, error) {
+	oldObjects, oldKeys, err := indexCollection(before, keyProvider)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid old collection")
+	}
+	newObjects, newKeys, err := indexCollection(after, keyProvider)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid new collection")
+	}
+	result := &CollectionDiff{newProto: after.Proto()}
+	matcher := newFieldMatcher(before.Proto(), after.Proto())
+	for _, key := range newKeys {
+		newObject := newObjects[key]
+		oldObject, ok := oldObjects[key]
+		if !ok {
+			result.Changes = append(result.Changes, &ObjectChange{Type: ChangeAdded, Key: key, New: newObject})
+			continue
+		}
+		objectMatcher := matcher
+		if oldObject.proto != matcher.oldProto || newObject.proto != matcher.newProto { //objects not migrated to collection proto version
+			objectMatcher = newFieldMatcher(oldObject.proto, newObject.proto)
+		}
+		if fields := objectMatcher.diff(oldObject, newObject); len(fields) > 0 {
+			result.Changes = append(result.Changes, &ObjectChange{Type: ChangeModified, Key: key, Old: oldObject, New: newObject, Fields: fields})
+		}
+	}
+	for _, key := range oldKeys {
+		if _, ok := newObjects[key]; !ok {
+			result.Changes = append(result.Changes, &ObjectChange{Type: ChangeRemoved, Key: key, Old: oldObjects[key]})
+		}
+	}
+	return result, nil
+}
+
+//indexCollection returns objects by key and keys in collection order
+func indexCollection(collection Collection, keyProvider KeyProvider) (map[interface{}]*Object, []interface{}, error) {
+	objects := map[interface{}]*Object{}
+	var keys []interface{}
+	err := collection.Objects(func(item *Object) (bool, error) {
+		key := Value(keyProvider(item))
+		if key == nil {
+			return false, errors.New("object key was nil")
+		}
+		if _, ok := objects[key]; ok {
+			return false, errors.Errorf("duplicate key: %v", key)
+		}
+		objects[key] = item
+		keys = append(keys, key)
+		return true, nil
+	})
+	return objects, keys, err
+}
+
+//fieldMatcher represents old and new proto fields matched by name
+type fieldMatcher struct {
+	oldProto   *Proto
+	newProto   *Proto
+	fields     []*Field
+	oldIndexes []int
+	newIndexes []int
+}
+
+func (m *fieldMatcher) diff(oldObject, newObject *Object) []*FieldChange {
+	var result []*FieldChange
+	for i, field := range m.fields {
+		oldValue := m.value(oldObject, m.oldIndexes[i])
+		newValue := m.value(newObject, m.newIndexes[i])
+		if !fieldEqual(field, oldValue, newValue) {
+			result = append(result, &FieldChange{Name: field.Name, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	return result
+}
+
+func (m *fieldMatcher) value(object *Object, index int) interface{} {
+	if index == -1 {
+		return nil
+	}
+	value, _ := object.ValueAt(index)
+	return Value(value)
+}
+
+//newFieldMatcher matches new proto fields followed by fields present only in the old proto
+func newFieldMatcher(oldProto, newProto *Proto) *fieldMatcher {
+	result := &fieldMatcher{oldProto: oldProto, newProto: newProto}
+	newFields := newProto.Fields()
+	for i := range newFields {
+		field := &newFields[i]
+		oldIndex := -1
+		if oldField := oldProto.Lookup(field.Name); oldField != nil {
+			oldIndex = oldField.Index
+		}
+		result.fields = append(result.fields, field)
+		result.oldIndexes = append(result.oldIndexes, oldIndex)
+		result.newIndexes = append(result.newIndexes, field.Index)
+	}
+	oldFields := oldProto.Fields()
+	for i := range oldFields {
+		field := &oldFields[i]
+		if newProto.Lookup(field.Name) != nil {
+			continue
+		}
+		result.fields = append(result.fields, field)
+		result.oldIndexes = append(result.oldIndexes, field.Index)
+		result.newIndexes = append(result.newIndexes, -1)
+	}
+	return result
+}
+
+//fieldEqual compares values converted to field data type, nil pointers are equal to nil
+func fieldEqual(field *Field, x, y interface{}) bool {
+	x, y = dereference(x), dereference(y)
+	if x == nil || y == nil {
+		return x == nil && y == nil
+	}
+	switch field.DataType {
+	case FieldTypeInt, FieldTypeInt64:
+		xInt, xErr := toolbox.ToInt(x)
+		yInt, yErr := toolbox.ToInt(y)
+		return xErr == nil && yErr == nil && xInt == yInt
+	case FieldTypeFloat32:
+		return float32(toolbox.AsFloat(x)) == float32(toolbox.AsFloat(y))
+	case FieldTypeFloat64:
+		return toolbox.AsFloat(x) == toolbox.AsFloat(y)
+	case FieldTypeBool:
+		return toolbox.AsBoolean(x) == toolbox.AsBoolean(y)
+	case FieldTypeString:
+		return toolbox.AsString(x) == toolbox.AsString(y)
+	case FieldTypeTime:
+		xTime, xErr := toolbox.ToTime(x, field.TimeLayout())
+		yTime, yErr := toolbox.ToTime(y, field.TimeLayout())
+		return xErr == nil && yErr == nil && xTime.Equal(*yTime)
+	case FieldTypeBytes:
+		xBytes, xOk := x.([]byte)
+		yBytes, yOk := y.([]byte)
+		if xOk && yOk {
+			return bytes.Equal(xBytes, yBytes)
+		}
+	}
+	return patchEqual(x, y)
+}
+
+//dereference returns pointer value, nil is returned for nil pointer, nested object and array pointers are preserved
+func dereference(value interface{}) interface{} {
+	switch value.(type) {
+	case nil, *Object, *Array:
+		return value
+	}
+	reflectValue := reflect.ValueOf(value)
+	for reflectValue.Kind() == reflect.Ptr {
+		if reflectValue.IsNil() {
+			return nil
+		}
+		reflectValue = reflectValue.Elem()
+	}
+	return reflectValue.Interface()
+}
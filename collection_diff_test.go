@@ -0,0 +1,90 @@
+package gtly_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"testing"
+	"time"
+)
+
+func TestDiffCollections(t *testing.T) {
+	oldProvider, err := gtly.NewProvider("product",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+		gtly.NewField("price", gtly.FieldTypeFloat64),
+		gtly.NewField("updated", gtly.FieldTypeTime),
+		gtly.NewField("discontinued", gtly.FieldTypeBool),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	newProvider, err := gtly.NewProvider("product",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+		gtly.NewField("price", gtly.FieldTypeFloat32),
+		gtly.NewField("updated", gtly.FieldTypeTime),
+		gtly.NewField("stock", gtly.FieldTypeInt64),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	updated := time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)
+	yesterday := oldProvider.NewArray()
+	for _, values := range []map[string]interface{}{
+		{"id": 1, "name": "pen", "price": 1.5, "updated": updated},
+		{"id": 2, "name": "ink", "price": 3.0, "updated": updated},
+		{"id": 3, "name": "pad", "price": 2.0, "discontinued": true},
+	} {
+		assert.Nil(t, yesterday.Add(values))
+	}
+	today := newProvider.NewArray()
+	for _, values := range []map[string]interface{}{
+		{"id": 4, "name": "cap", "stock": int64(5)},
+		{"id": 1, "name": "pen", "price": float32(1.5), "updated": updated.In(time.FixedZone("CET", 3600))},
+		{"id": 2, "name": "ink", "price": float32(3.5), "updated": updated},
+	} {
+		assert.Nil(t, today.Add(values))
+	}
+
+	diff, err := gtly.DiffCollections(yesterday, today, gtly.NewKeyProvider("id"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	var changes []gtly.ChangeType
+	var keys []interface{}
+	for _, change := range diff.Changes {
+		changes = append(changes, change.Type)
+		keys = append(keys, change.Key)
+	}
+	assert.EqualValues(t, []gtly.ChangeType{gtly.ChangeAdded, gtly.ChangeModified, gtly.ChangeRemoved}, changes)
+	assert.EqualValues(t, []interface{}{4, 2, 3}, keys)
+	assert.Len(t, diff.Added(), 1)
+	assert.Len(t, diff.Removed(), 1)
+	if modified := diff.Modified(); assert.Len(t, modified, 1) {
+		assert.EqualValues(t, []*gtly.FieldChange{{Name: "price", OldValue: 3.0, NewValue: float32(3.5)}}, modified[0].Fields)
+	}
+
+	array, err := diff.Array("")
+	if !assert.Nil(t, err) {
+		return
+	}
+	var actual []map[string]interface{}
+	_ = array.Objects(func(item *gtly.Object) (bool, error) {
+		actual = append(actual, item.AsMap())
+		return true, nil
+	})
+	assert.EqualValues(t, []map[string]interface{}{
+		{"id": 4, "name": "cap", "stock": int64(5), "changeType": "added"},
+		{"id": 2, "name": "ink", "price": float32(3.5), "updated": updated, "changeType": "modified"},
+		{"id": 3, "name": "pad", "price": float32(2.0), "changeType": "removed"},
+	}, actual)
+
+	_, err = diff.Array("stock")
+	assert.NotNil(t, err)
+
+	duplicated := newProvider.NewArray()
+	assert.Nil(t, duplicated.Add(map[string]interface{}{"id": 1}))
+	assert.Nil(t, duplicated.Add(map[string]interface{}{"id": 1}))
+	_, err = gtly.DiffCollections(yesterday, duplicated, gtly.NewKeyProvider("id"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,168 @@
+package ddl
+
+import (
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"github.com/viant/gtly/internal/naming"
+	"reflect"
+	"time"
+)
+
+//kindJSON represents JSON document column, used for fields with dynamic types
+const kindJSON = "json"
+
+var (
+	typeTime      = reflect.TypeOf(time.Time{})
+	typeInterface = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+//column represents table column or struct field
+type column struct {
+	name     string
+	kind     string
+	layout   string
+	required bool
+	item     *column
+	fields   []*column
+}
+
+func (c *column) isDate() bool {
+	return c.kind == gtly.FieldTypeTime && c.layout == dateLayout
+}
+
+//newColumns returns proto columns
+func newColumns(proto *gtly.Proto) ([]*column, error) {
+	fields := proto.Fields()
+	var result = make([]*column, len(fields))
+	for i := range fields {
+		field := &fields[i]
+		aColumn, err := newColumn(field)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert %v.%v", proto.Name, field.Name)
+		}
+		aColumn.name = field.OutputName()
+		aColumn.required = !field.ShallOmitEmpty()
+		result[i] = aColumn
+	}
+	return result, nil
+}
+
+func newColumn(field *gtly.Field) (*column, error) {
+	layout := field.TimeLayout()
+	switch field.DataType {
+	case gtly.FieldTypeObject:
+		if provider := field.ItemProvider(); provider != nil {
+			fields, err := newColumns(provider.Proto)
+			return &column{kind: gtly.FieldTypeObject, fields: fields}, err
+		}
+	case gtly.FieldTypeArray:
+		if provider := field.ItemProvider(); provider != nil {
+			fields, err := newColumns(provider.Proto)
+			return &column{kind: gtly.FieldTypeArray, item: &column{kind: gtly.FieldTypeObject, fields: fields}}, err
+		}
+		if field.ComponentType != "" && field.ComponentType != gtly.FieldTypeArray && field.ComponentType != gtly.FieldTypeObject {
+			return &column{kind: gtly.FieldTypeArray, item: &column{kind: field.ComponentType, layout: layout}}, nil
+		}
+	default:
+		if !gtly.IsComposite(field.Type) {
+			return &column{kind: field.DataType, layout: layout}, nil
+		}
+	}
+	return typeColumn(field.Type, layout)
+}
+
+func typeColumn(rType reflect.Type, layout string) (*column, error) {
+	switch rType.Kind() {
+	case reflect.Ptr:
+		return typeColumn(rType.Elem(), layout)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &column{kind: gtly.FieldTypeInt}, nil
+	case reflect.Int64, reflect.Uint64:
+		return &column{kind: gtly.FieldTypeInt64}, nil
+	case reflect.Float32:
+		return &column{kind: gtly.FieldTypeFloat32}, nil
+	case reflect.Float64:
+		return &column{kind: gtly.FieldTypeFloat64}, nil
+	case reflect.Bool:
+		return &column{kind: gtly.FieldTypeBool}, nil
+	case reflect.String:
+		return &column{kind: gtly.FieldTypeString}, nil
+	case reflect.Map, reflect.Interface:
+		return &column{kind: kindJSON}, nil
+	case reflect.Slice:
+		if rType.Elem().Kind() == reflect.Uint8 {
+			return &column{kind: gtly.FieldTypeBytes}, nil
+		}
+		item, err := typeColumn(rType.Elem(), layout)
+		if err != nil {
+			return nil, err
+		}
+		return &column{kind: gtly.FieldTypeArray, item: item}, nil
+	case reflect.Struct:
+		if rType == typeTime {
+			return &column{kind: gtly.FieldTypeTime, layout: layout}, nil
+		}
+		result := &column{kind: gtly.FieldTypeObject}
+		for i := 0; i < rType.NumField(); i++ {
+			structField := rType.Field(i)
+			field, err := typeColumn(structField.Type, layout)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to convert %v", structField.Name)
+			}
+			field.name = structField.Name
+			result.fields = append(result.fields, field)
+		}
+		return result, nil
+	}
+	return nil, errors.Errorf("unsupported type: %v", rType)
+}
+
+//newField creates a field for parsed column
+func (c *column) newField() (*gtly.Field, error) {
+	var field *gtly.Field
+	switch c.kind {
+	case kindJSON:
+		field = &gtly.Field{Name: c.name, Type: typeInterface}
+	case gtly.FieldTypeObject:
+		provider, err := newProvider(c.name, c.fields)
+		if err != nil {
+			return nil, err
+		}
+		field = gtly.NewField(c.name, gtly.FieldTypeObject, gtly.ProviderOpt(provider))
+	case gtly.FieldTypeArray:
+		switch c.item.kind {
+		case gtly.FieldTypeObject:
+			provider, err := newProvider(c.name, c.item.fields)
+			if err != nil {
+				return nil, err
+			}
+			field = gtly.NewField(c.name, gtly.FieldTypeArray, gtly.ProviderOpt(provider))
+		case gtly.FieldTypeArray, kindJSON:
+			field = &gtly.Field{Name: c.name, DataType: gtly.FieldTypeArray, Type: reflect.TypeOf([]interface{}{})}
+		default:
+			field = gtly.NewField(c.name, gtly.FieldTypeArray, gtly.ComponentTypeOpt(c.item.kind), gtly.DateLayoutOpt(c.item.layout))
+		}
+	default:
+		field = gtly.NewField(c.name, c.kind, gtly.DateLayoutOpt(c.layout))
+	}
+	field.OmitEmpty = &[]bool{!c.required}[0]
+	return field, nil
+}
+
+//newProvider creates provider for parsed columns, column names are converted to Go identifiers, source names are kept as input and output names
+func newProvider(name string, columns []*column) (*gtly.Provider, error) {
+	var fields = make([]*gtly.Field, len(columns))
+	var names = map[string]bool{}
+	for i, aColumn := range columns {
+		field, err := aColumn.newField()
+		if err != nil {
+			return nil, err
+		}
+		if field.Name = naming.FieldName(aColumn.name, names); field.Name != aColumn.name {
+			field.InputName = aColumn.name
+			gtly.OutputNameOpt(aColumn.name)(field)
+		}
+		fields[i] = field
+	}
+	return gtly.NewProvider(name, fields...)
+}
@@ -0,0 +1,71 @@
+//Package ddl provides SQL CREATE TABLE rendering and parsing for gtly proto
+package ddl
+
+import (
+	"github.com/pkg/errors"
+	"strings"
+)
+
+//Dialect represents SQL dialect
+type Dialect string
+
+const (
+	//PostgreSQL PostgreSQL dialect
+	PostgreSQL = Dialect("postgres")
+	//MySQL MySQL dialect
+	MySQL = Dialect("mysql")
+	//SQLite SQLite dialect
+	SQLite = Dialect("sqlite")
+	//BigQuery BigQuery standard SQL dialect
+	BigQuery = Dialect("bigquery")
+)
+
+const dateLayout = "2006-01-02"
+
+//Option represents CREATE TABLE option
+type Option func(c *config)
+
+type config struct {
+	table       string
+	primaryKey  []string
+	ifNotExists bool
+}
+
+//TableOpt returns table name option, proto name is used by default
+func TableOpt(table string) Option {
+	return func(c *config) {
+		c.table = table
+	}
+}
+
+//PrimaryKeyOpt returns primary key columns option
+func PrimaryKeyOpt(columns ...string) Option {
+	return func(c *config) {
+		c.primaryKey = columns
+	}
+}
+
+//IfNotExistsOpt returns IF NOT EXISTS option
+func IfNotExistsOpt() Option {
+	return func(c *config) {
+		c.ifNotExists = true
+	}
+}
+
+func (d Dialect) validate() error {
+	switch d {
+	case PostgreSQL, MySQL, SQLite, BigQuery:
+		return nil
+	}
+	return errors.Errorf("unsupported dialect: %v", d)
+}
+
+//Quote returns quoted identifier, embedded quote character is escaped by doubling it
+func (d Dialect) Quote(name string) string {
+	quote := `"`
+	switch d {
+	case MySQL, BigQuery:
+		quote = "`"
+	}
+	return quote + strings.Replace(name, quote, quote+quote, -1) + quote
+}
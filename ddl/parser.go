@@ -0,0 +1,444 @@
+package ddl
+
+import (
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"strings"
+	"unicode"
+)
+
+//token represents DDL token, quoted identifiers are never matched as keywords
+type token struct {
+	text   string
+	quoted bool
+	pos    int
+}
+
+//parser represents simple CREATE TABLE parser
+type parser struct {
+	tokens []*token
+	index  int
+	end    int
+}
+
+//ParseCreateTable parses simple CREATE TABLE statement into a provider, nullable columns use omit empty
+func ParseCreateTable(statement string) (*gtly.Provider, error) {
+	tokens, err := tokenize(statement)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, end: len(statement) + 1}
+	name, columns, err := p.createTable()
+	if err != nil {
+		return nil, err
+	}
+	return newProvider(name, columns)
+}
+
+func (p *parser) createTable() (string, []*column, error) {
+	if err := p.expect("CREATE"); err != nil {
+		return "", nil, err
+	}
+	p.accept("OR", "REPLACE")
+	if !p.accept("TEMPORARY") {
+		p.accept("TEMP")
+	}
+	if err := p.expect("TABLE"); err != nil {
+		return "", nil, err
+	}
+	p.accept("IF", "NOT", "EXISTS")
+	name, err := p.qualifiedName()
+	if err != nil {
+		return "", nil, err
+	}
+	if err = p.expect("("); err != nil {
+		return "", nil, err
+	}
+	var columns []*column
+	var primaryKey []string
+	for {
+		switch {
+		case p.accept("PRIMARY", "KEY"):
+			if primaryKey, err = p.nameList(); err != nil {
+				return "", nil, err
+			}
+			p.skipDefinition()
+		case p.isKeyword("CONSTRAINT", "UNIQUE", "FOREIGN", "CHECK", "KEY", "INDEX", "EXCLUDE"):
+			if p.accept("CONSTRAINT") {
+				if _, err = p.name(); err != nil {
+					return "", nil, err
+				}
+				continue
+			}
+			p.skipDefinition()
+		default:
+			aColumn, err := p.columnDefinition(",", ")")
+			if err != nil {
+				return "", nil, err
+			}
+			columns = append(columns, aColumn)
+		}
+		if p.accept(",") {
+			continue
+		}
+		if err = p.expect(")"); err != nil {
+			return "", nil, err
+		}
+		break
+	}
+	if len(columns) == 0 {
+		return "", nil, errors.Errorf("table %v columns were empty", name)
+	}
+	for _, key := range primaryKey {
+		for _, aColumn := range columns {
+			if aColumn.name == key {
+				aColumn.required = true
+			}
+		}
+	}
+	return name, columns, nil
+}
+
+//columnDefinition parses column name, type and NOT NULL or PRIMARY KEY constraints, other constraints are skipped
+func (p *parser) columnDefinition(terminators ...string) (*column, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.columnType()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid column %v", name)
+	}
+	result.name = name
+	for depth := 0; p.index < len(p.tokens); {
+		if depth == 0 && p.isKeyword(terminators...) {
+			break
+		}
+		switch {
+		case p.accept("NOT", "NULL"), p.accept("PRIMARY", "KEY"):
+			result.required = true
+		case p.accept("("):
+			depth++
+		case p.accept(")"):
+			depth--
+		default:
+			p.index++
+		}
+	}
+	return result, nil
+}
+
+func (p *parser) columnType() (*column, error) {
+	typeToken := p.next()
+	if typeToken == nil || typeToken.quoted {
+		return nil, p.unexpected("column type", typeToken)
+	}
+	typeName := strings.ToUpper(typeToken.text)
+	var result *column
+	switch {
+	case typeName == "ARRAY" && p.accept("<"):
+		item, err := p.columnType()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(">"); err != nil {
+			return nil, err
+		}
+		result = &column{kind: gtly.FieldTypeArray, item: item}
+	case (typeName == "STRUCT" || typeName == "RECORD") && p.accept("<"):
+		result = &column{kind: gtly.FieldTypeObject}
+		for {
+			field, err := p.columnDefinition(",", ">")
+			if err != nil {
+				return nil, err
+			}
+			result.fields = append(result.fields, field)
+			if p.accept(",") {
+				continue
+			}
+			if err = p.expect(">"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	default:
+		if p.accept("PRECISION") || p.accept("VARYING") {
+			typeName += " " + strings.ToUpper(p.tokens[p.index-1].text)
+		}
+		var arguments []string
+		if p.accept("(") {
+			for p.index < len(p.tokens) && !p.accept(")") {
+				if token := p.next(); token.text != "," {
+					arguments = append(arguments, token.text)
+				}
+			}
+		}
+		if !p.accept("WITH", "TIME", "ZONE") {
+			p.accept("WITHOUT", "TIME", "ZONE")
+		}
+		p.accept("UNSIGNED")
+		kind, ok := baseKinds[typeName]
+		if !ok {
+			return nil, errors.Errorf("unsupported column type: %v", typeToken.text)
+		}
+		if typeName == "TINYINT" && len(arguments) == 1 && arguments[0] == "1" {
+			kind = gtly.FieldTypeBool
+		}
+		result = &column{kind: kind}
+		if typeName == "DATE" {
+			result.layout = dateLayout
+		}
+	}
+	for p.accept("[") {
+		for p.index < len(p.tokens) && !p.accept("]") {
+			p.index++
+		}
+		result = &column{kind: gtly.FieldTypeArray, item: result}
+	}
+	return result, nil
+}
+
+//skipDefinition skips table constraint definition
+func (p *parser) skipDefinition() {
+	for depth := 0; p.index < len(p.tokens); p.index++ {
+		switch p.tokens[p.index].text {
+		case "(":
+			depth++
+		case ")":
+			if depth == 0 {
+				return
+			}
+			depth--
+		case ",":
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+func (p *parser) nameList() ([]string, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var result []string
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, name)
+		if p.accept(",") {
+			continue
+		}
+		return result, p.expect(")")
+	}
+}
+
+//qualifiedName returns the last part of dotted table name, including quoted BigQuery path
+func (p *parser) qualifiedName() (string, error) {
+	name, err := p.name()
+	for err == nil && p.accept(".") {
+		name, err = p.name()
+	}
+	if index := strings.LastIndex(name, "."); index != -1 {
+		name = name[index+1:]
+	}
+	return name, err
+}
+
+func (p *parser) name() (string, error) {
+	token := p.next()
+	if token == nil || (!token.quoted && !isIdentifier(token.text)) {
+		return "", p.unexpected("name", token)
+	}
+	return token.text, nil
+}
+
+func (p *parser) next() *token {
+	if p.index >= len(p.tokens) {
+		return nil
+	}
+	p.index++
+	return p.tokens[p.index-1]
+}
+
+func (p *parser) isKeyword(keywords ...string) bool {
+	if p.index >= len(p.tokens) || p.tokens[p.index].quoted {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(p.tokens[p.index].text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+//accept consumes keywords sequence if all keywords match
+func (p *parser) accept(keywords ...string) bool {
+	if p.index+len(keywords) > len(p.tokens) {
+		return false
+	}
+	for i, keyword := range keywords {
+		token := p.tokens[p.index+i]
+		if token.quoted || !strings.EqualFold(token.text, keyword) {
+			return false
+		}
+	}
+	p.index += len(keywords)
+	return true
+}
+
+func (p *parser) expect(keyword string) error {
+	if p.accept(keyword) {
+		return nil
+	}
+	var actual *token
+	if p.index < len(p.tokens) {
+		actual = p.tokens[p.index]
+	}
+	return p.unexpected(keyword, actual)
+}
+
+func (p *parser) unexpected(expected string, actual *token) error {
+	if actual == nil {
+		return errors.Errorf("invalid CREATE TABLE statement at position %v: expected %v, but had end of statement", p.end, expected)
+	}
+	return errors.Errorf("invalid CREATE TABLE statement at position %v: expected %v, but had %v", actual.pos+1, expected, actual.text)
+}
+
+func isIdentifier(text string) bool {
+	for i, r := range text {
+		if !(unicode.IsLetter(r) || r == '_' || (i > 0 && (unicode.IsDigit(r) || r == '$'))) {
+			return false
+		}
+	}
+	return text != ""
+}
+
+//tokenize splits statement into tokens, comments and string literals are skipped
+func tokenize(statement string) ([]*token, error) {
+	var result []*token
+	runes := []rune(statement)
+	offsets := make([]int, len(runes)+1)
+	offset := 0
+	for i, r := range runes {
+		offsets[i] = offset
+		offset += len(string(r))
+	}
+	offsets[len(runes)] = offset
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			start := i
+			for i += 2; i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/'); i++ {
+			}
+			if i+1 >= len(runes) {
+				return nil, errors.Errorf("invalid CREATE TABLE statement at position %v: unterminated comment", offsets[start]+1)
+			}
+			i += 2
+		case r == '"' || r == '`' || r == '\'' || (r == '[' && i+1 < len(runes) && (unicode.IsLetter(runes[i+1]) || runes[i+1] == '_')):
+			closing := r
+			if r == '[' {
+				closing = ']'
+			}
+			start := i
+			var text []rune
+			for i++; ; i++ {
+				if i >= len(runes) {
+					return nil, errors.Errorf("invalid CREATE TABLE statement at position %v: unterminated quote", offsets[start]+1)
+				}
+				if runes[i] == closing {
+					if i+1 < len(runes) && runes[i+1] == closing && closing != ']' {
+						text = append(text, closing)
+						i++
+						continue
+					}
+					i++
+					break
+				}
+				text = append(text, runes[i])
+			}
+			if r == '\'' {
+				result = append(result, &token{text: "'" + string(text) + "'", quoted: true, pos: offsets[start]})
+				continue
+			}
+			result = append(result, &token{text: string(text), quoted: true, pos: offsets[start]})
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+			result = append(result, &token{text: string(runes[start:i]), pos: offsets[start]})
+		default:
+			result = append(result, &token{text: string(r), pos: offsets[i]})
+			i++
+		}
+	}
+	return result, nil
+}
+
+var baseKinds = map[string]string{
+	"INT":               gtly.FieldTypeInt,
+	"INTEGER":           gtly.FieldTypeInt,
+	"SMALLINT":          gtly.FieldTypeInt,
+	"TINYINT":           gtly.FieldTypeInt,
+	"MEDIUMINT":         gtly.FieldTypeInt,
+	"INT2":              gtly.FieldTypeInt,
+	"INT4":              gtly.FieldTypeInt,
+	"SERIAL":            gtly.FieldTypeInt,
+	"SMALLSERIAL":       gtly.FieldTypeInt,
+	"BIGINT":            gtly.FieldTypeInt64,
+	"INT8":              gtly.FieldTypeInt64,
+	"INT64":             gtly.FieldTypeInt64,
+	"BIGSERIAL":         gtly.FieldTypeInt64,
+	"REAL":              gtly.FieldTypeFloat32,
+	"FLOAT4":            gtly.FieldTypeFloat32,
+	"FLOAT":             gtly.FieldTypeFloat64,
+	"FLOAT8":            gtly.FieldTypeFloat64,
+	"FLOAT64":           gtly.FieldTypeFloat64,
+	"DOUBLE":            gtly.FieldTypeFloat64,
+	"DOUBLE PRECISION":  gtly.FieldTypeFloat64,
+	"NUMERIC":           gtly.FieldTypeFloat64,
+	"DECIMAL":           gtly.FieldTypeFloat64,
+	"BIGNUMERIC":        gtly.FieldTypeFloat64,
+	"BOOL":              gtly.FieldTypeBool,
+	"BOOLEAN":           gtly.FieldTypeBool,
+	"BIT":               gtly.FieldTypeBool,
+	"TEXT":              gtly.FieldTypeString,
+	"VARCHAR":           gtly.FieldTypeString,
+	"CHAR":              gtly.FieldTypeString,
+	"CHARACTER":         gtly.FieldTypeString,
+	"CHARACTER VARYING": gtly.FieldTypeString,
+	"NVARCHAR":          gtly.FieldTypeString,
+	"NCHAR":             gtly.FieldTypeString,
+	"STRING":            gtly.FieldTypeString,
+	"CLOB":              gtly.FieldTypeString,
+	"UUID":              gtly.FieldTypeString,
+	"CITEXT":            gtly.FieldTypeString,
+	"TINYTEXT":          gtly.FieldTypeString,
+	"MEDIUMTEXT":        gtly.FieldTypeString,
+	"LONGTEXT":          gtly.FieldTypeString,
+	"TIMESTAMP":         gtly.FieldTypeTime,
+	"TIMESTAMPTZ":       gtly.FieldTypeTime,
+	"DATETIME":          gtly.FieldTypeTime,
+	"DATE":              gtly.FieldTypeTime,
+	"BYTEA":             gtly.FieldTypeBytes,
+	"BLOB":              gtly.FieldTypeBytes,
+	"TINYBLOB":          gtly.FieldTypeBytes,
+	"MEDIUMBLOB":        gtly.FieldTypeBytes,
+	"LONGBLOB":          gtly.FieldTypeBytes,
+	"BINARY":            gtly.FieldTypeBytes,
+	"VARBINARY":         gtly.FieldTypeBytes,
+	"BYTES":             gtly.FieldTypeBytes,
+	"JSON":              kindJSON,
+	"JSONB":             kindJSON,
+}
@@ -0,0 +1,137 @@
+package ddl
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseCreateTable(t *testing.T) {
+	testCases := []struct {
+		description    string
+		statement      string
+		expectName     string
+		expectFields   []string
+		expectRequired []string
+		expectOutput   map[string]string
+		expectError    string
+	}{
+		{
+			description: "postgres",
+			statement: `CREATE TABLE IF NOT EXISTS public."foo" (
+  id BIGSERIAL,
+  "name" CHARACTER VARYING(64) NOT NULL DEFAULT 'n/a', -- display name
+  price NUMERIC(10, 2) CHECK (price > 0),
+  ratio DOUBLE PRECISION,
+  created TIMESTAMP WITH TIME ZONE DEFAULT now(),
+  day DATE,
+  tags TEXT[],
+  payload JSONB,
+  /* binary */ avatar BYTEA,
+  CONSTRAINT foo_pk PRIMARY KEY (id)
+);`,
+			expectName:     "foo",
+			expectFields:   []string{"id:int64", "name:string", "price:float64", "ratio:float64", "created:time", "day:time", "tags:array", "payload:string", "avatar:bytes"},
+			expectRequired: []string{"id", "name"},
+		},
+		{
+			description: "mysql",
+			statement: "CREATE TABLE `foo` (\n" +
+				"  `id` INT UNSIGNED NOT NULL AUTO_INCREMENT,\n" +
+				"  `active` TINYINT(1),\n" +
+				"  `qty` TINYINT(4),\n" +
+				"  `updated` DATETIME,\n" +
+				"  `ratio` FLOAT,\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  KEY `idx_updated` (`updated`)\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+			expectName:     "foo",
+			expectFields:   []string{"id:int", "active:bool", "qty:int", "updated:time", "ratio:float64"},
+			expectRequired: []string{"id"},
+		},
+		{
+			description: "bigquery",
+			statement: "CREATE OR REPLACE TABLE `project.dataset.foo` (\n" +
+				"  id INT64 NOT NULL,\n" +
+				"  address STRUCT<city STRING NOT NULL, zip STRING>,\n" +
+				"  lines ARRAY<STRUCT<sku STRING, qty INT64>>,\n" +
+				"  tags ARRAY<STRING>\n" +
+				")",
+			expectName:     "foo",
+			expectFields:   []string{"id:int64", "address:object", "lines:array", "tags:array"},
+			expectRequired: []string{"id"},
+		},
+		{
+			description:    "sqlite",
+			statement:      `create temp table [foo] (id integer primary key, "name" text, score real)`,
+			expectName:     "foo",
+			expectFields:   []string{"id:int", "name:string", "score:float32"},
+			expectRequired: []string{"id"},
+		},
+		{
+			description:    "non identifier names",
+			statement:      `CREATE TABLE t ("first name" TEXT NOT NULL, "user-id" INT, address STRUCT<"zip code" STRING>)`,
+			expectName:     "t",
+			expectFields:   []string{"first_name:string", "user_id:int", "address:object"},
+			expectRequired: []string{"first_name"},
+			expectOutput:   map[string]string{"first_name": "first name", "user_id": "user-id", "address": "address"},
+		},
+		{
+			description: "unsupported type",
+			statement:   "CREATE TABLE foo (id GEOGRAPHY)",
+			expectError: "unsupported column type: GEOGRAPHY",
+		},
+		{
+			description: "missing table keyword",
+			statement:   "CREATE foo (id INT)",
+			expectError: "invalid CREATE TABLE statement at position 8: expected TABLE, but had foo",
+		},
+		{
+			description: "unterminated statement",
+			statement:   "CREATE TABLE foo (id INT",
+			expectError: "invalid CREATE TABLE statement at position 25: expected ), but had end of statement",
+		},
+	}
+
+	for _, testCase := range testCases {
+		provider, err := ParseCreateTable(testCase.statement)
+		if testCase.expectError != "" {
+			if assert.NotNil(t, err, testCase.description) {
+				assert.Contains(t, err.Error(), testCase.expectError, testCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expectName, provider.Name, testCase.description)
+		var actual, required []string
+		for _, field := range provider.Fields() {
+			actual = append(actual, field.Name+":"+field.DataType)
+			if !field.ShallOmitEmpty() {
+				required = append(required, field.Name)
+			}
+		}
+		assert.EqualValues(t, testCase.expectFields, actual, testCase.description)
+		assert.EqualValues(t, testCase.expectRequired, required, testCase.description)
+		for name, expect := range testCase.expectOutput {
+			assert.EqualValues(t, expect, provider.Field(name).OutputName(), testCase.description+" "+name)
+		}
+	}
+}
+
+func TestParseCreateTable_RoundTrip(t *testing.T) {
+	provider := newDDLProvider(t)
+	for _, dialect := range []Dialect{PostgreSQL, BigQuery} {
+		statement, err := CreateTable(provider.Proto, dialect)
+		if !assert.Nil(t, err, dialect) {
+			continue
+		}
+		parsed, err := ParseCreateTable(statement)
+		if !assert.Nil(t, err, dialect) {
+			continue
+		}
+		actual, err := CreateTable(parsed.Proto, dialect)
+		assert.Nil(t, err, dialect)
+		assert.EqualValues(t, statement, actual, dialect)
+	}
+}
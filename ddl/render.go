@@ -0,0 +1,173 @@
+package ddl
+
+import (
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"strings"
+)
+
+//CreateTable returns CREATE TABLE statement for supplied proto, fields with omit empty are nullable
+func CreateTable(proto *gtly.Proto, dialect Dialect, options ...Option) (string, error) {
+	if err := dialect.validate(); err != nil {
+		return "", err
+	}
+	config := &config{table: proto.Name}
+	for _, option := range options {
+		option(config)
+	}
+	columns, err := newColumns(proto)
+	if err != nil {
+		return "", err
+	}
+	primaryKey := map[string]bool{}
+	for _, name := range config.primaryKey {
+		primaryKey[name] = true
+	}
+	var definitions = make([]string, 0, len(columns)+1)
+	for _, aColumn := range columns {
+		if primaryKey[aColumn.name] {
+			aColumn.required = true
+		}
+		columnType, err := dialect.columnType(aColumn, primaryKey[aColumn.name])
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to render column %v", aColumn.name)
+		}
+		definitions = append(definitions, dialect.Quote(aColumn.name)+" "+columnType+dialect.nullability(aColumn))
+	}
+	if len(config.primaryKey) > 0 {
+		var keys = make([]string, len(config.primaryKey))
+		for i, name := range config.primaryKey {
+			keys[i] = dialect.Quote(name)
+		}
+		constraint := "PRIMARY KEY (" + strings.Join(keys, ", ") + ")"
+		if dialect == BigQuery {
+			constraint += " NOT ENFORCED"
+		}
+		definitions = append(definitions, constraint)
+	}
+	builder := new(strings.Builder)
+	builder.WriteString("CREATE TABLE ")
+	if config.ifNotExists {
+		builder.WriteString("IF NOT EXISTS ")
+	}
+	builder.WriteString(dialect.Quote(config.table))
+	builder.WriteString(" (\n  ")
+	builder.WriteString(strings.Join(definitions, ",\n  "))
+	builder.WriteString("\n)")
+	return builder.String(), nil
+}
+
+//nullability returns NOT NULL for required columns, BigQuery arrays can not be declared as NOT NULL
+func (d Dialect) nullability(aColumn *column) string {
+	if !aColumn.required || (d == BigQuery && aColumn.kind == gtly.FieldTypeArray) {
+		return ""
+	}
+	return " NOT NULL"
+}
+
+func (d Dialect) columnType(aColumn *column, primaryKey bool) (string, error) {
+	switch aColumn.kind {
+	case gtly.FieldTypeArray:
+		return d.arrayType(aColumn.item)
+	case gtly.FieldTypeObject:
+		if d == BigQuery {
+			return d.structType(aColumn.fields)
+		}
+		return d.jsonType(), nil
+	case kindJSON:
+		return d.jsonType(), nil
+	}
+	if aColumn.isDate() {
+		return "DATE", nil
+	}
+	if d == MySQL && primaryKey && aColumn.kind == gtly.FieldTypeString {
+		return "VARCHAR(255)", nil
+	}
+	if columnType, ok := baseTypes[d][aColumn.kind]; ok {
+		return columnType, nil
+	}
+	return "", errors.Errorf("unsupported data type: %v", aColumn.kind)
+}
+
+func (d Dialect) arrayType(item *column) (string, error) {
+	switch d {
+	case PostgreSQL:
+		if item.kind == gtly.FieldTypeArray || item.kind == gtly.FieldTypeObject || item.kind == kindJSON {
+			return d.jsonType(), nil
+		}
+		itemType, err := d.columnType(item, false)
+		return itemType + "[]", err
+	case BigQuery:
+		if item.kind == gtly.FieldTypeArray {
+			return d.jsonType(), nil
+		}
+		itemType, err := d.columnType(item, false)
+		return "ARRAY<" + itemType + ">", err
+	}
+	return d.jsonType(), nil
+}
+
+func (d Dialect) structType(fields []*column) (string, error) {
+	var definitions = make([]string, len(fields))
+	for i, field := range fields {
+		fieldType, err := d.columnType(field, false)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to render struct field %v", field.name)
+		}
+		definitions[i] = d.Quote(field.name) + " " + fieldType + d.nullability(field)
+	}
+	return "STRUCT<" + strings.Join(definitions, ", ") + ">", nil
+}
+
+func (d Dialect) jsonType() string {
+	switch d {
+	case PostgreSQL:
+		return "JSONB"
+	case SQLite:
+		return "TEXT"
+	}
+	return "JSON"
+}
+
+var baseTypes = map[Dialect]map[string]string{
+	PostgreSQL: {
+		gtly.FieldTypeInt:     "INTEGER",
+		gtly.FieldTypeInt64:   "BIGINT",
+		gtly.FieldTypeFloat32: "REAL",
+		gtly.FieldTypeFloat64: "DOUBLE PRECISION",
+		gtly.FieldTypeBool:    "BOOLEAN",
+		gtly.FieldTypeString:  "TEXT",
+		gtly.FieldTypeTime:    "TIMESTAMP",
+		gtly.FieldTypeBytes:   "BYTEA",
+	},
+	MySQL: {
+		gtly.FieldTypeInt:     "INT",
+		gtly.FieldTypeInt64:   "BIGINT",
+		gtly.FieldTypeFloat32: "FLOAT",
+		gtly.FieldTypeFloat64: "DOUBLE",
+		gtly.FieldTypeBool:    "BOOLEAN",
+		gtly.FieldTypeString:  "TEXT",
+		gtly.FieldTypeTime:    "DATETIME",
+		gtly.FieldTypeBytes:   "BLOB",
+	},
+	SQLite: {
+		gtly.FieldTypeInt:     "INTEGER",
+		gtly.FieldTypeInt64:   "BIGINT",
+		gtly.FieldTypeFloat32: "REAL",
+		gtly.FieldTypeFloat64: "DOUBLE",
+		gtly.FieldTypeBool:    "BOOLEAN",
+		gtly.FieldTypeString:  "TEXT",
+		gtly.FieldTypeTime:    "TIMESTAMP",
+		gtly.FieldTypeBytes:   "BLOB",
+	},
+	BigQuery: {
+		gtly.FieldTypeInt:     "INT64",
+		gtly.FieldTypeInt64:   "INT64",
+		gtly.FieldTypeFloat32: "FLOAT64",
+		gtly.FieldTypeFloat64: "FLOAT64",
+		gtly.FieldTypeBool:    "BOOL",
+		gtly.FieldTypeString:  "STRING",
+		gtly.FieldTypeTime:    "TIMESTAMP",
+		gtly.FieldTypeBytes:   "BYTES",
+	},
+}
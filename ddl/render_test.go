@@ -0,0 +1,171 @@
+package ddl
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"reflect"
+	"testing"
+)
+
+func newDDLProvider(t *testing.T) *gtly.Provider {
+	address, err := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("zip", gtly.FieldTypeString, gtly.OmitEmptyOpt(true)),
+	)
+	assert.Nil(t, err)
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt64),
+		gtly.NewField("name", gtly.FieldTypeString),
+		gtly.NewField("qty", gtly.FieldTypeInt, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("ratio", gtly.FieldTypeFloat32, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("price", gtly.FieldTypeFloat64, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("active", gtly.FieldTypeBool, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("updated", gtly.FieldTypeTime, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("day", gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02"), gtly.OmitEmptyOpt(true)),
+		gtly.NewField("avatar", gtly.FieldTypeBytes, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("tags", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeString), gtly.OmitEmptyOpt(true)),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(address), gtly.OmitEmptyOpt(true)),
+		gtly.NewField("addresses", gtly.FieldTypeArray, gtly.ProviderOpt(address), gtly.OmitEmptyOpt(true)),
+		&gtly.Field{Name: "attributes", Type: reflect.TypeOf(map[string]interface{}{}), OmitEmpty: &[]bool{true}[0]},
+	)
+	assert.Nil(t, err)
+	return provider
+}
+
+func TestCreateTable(t *testing.T) {
+	provider := newDDLProvider(t)
+	testCases := []struct {
+		description string
+		dialect     Dialect
+		options     []Option
+		expect      string
+		expectError bool
+	}{
+		{
+			description: "postgres",
+			dialect:     PostgreSQL,
+			options:     []Option{PrimaryKeyOpt("id")},
+			expect: `CREATE TABLE "foo" (
+  "id" BIGINT NOT NULL,
+  "name" TEXT NOT NULL,
+  "qty" INTEGER,
+  "ratio" REAL,
+  "price" DOUBLE PRECISION,
+  "active" BOOLEAN,
+  "updated" TIMESTAMP,
+  "day" DATE,
+  "avatar" BYTEA,
+  "tags" TEXT[],
+  "address" JSONB,
+  "addresses" JSONB,
+  "attributes" JSONB,
+  PRIMARY KEY ("id")
+)`,
+		},
+		{
+			description: "mysql",
+			dialect:     MySQL,
+			options:     []Option{TableOpt("foos"), PrimaryKeyOpt("name"), IfNotExistsOpt()},
+			expect: "CREATE TABLE IF NOT EXISTS `foos` (\n" +
+				"  `id` BIGINT NOT NULL,\n" +
+				"  `name` VARCHAR(255) NOT NULL,\n" +
+				"  `qty` INT,\n" +
+				"  `ratio` FLOAT,\n" +
+				"  `price` DOUBLE,\n" +
+				"  `active` BOOLEAN,\n" +
+				"  `updated` DATETIME,\n" +
+				"  `day` DATE,\n" +
+				"  `avatar` BLOB,\n" +
+				"  `tags` JSON,\n" +
+				"  `address` JSON,\n" +
+				"  `addresses` JSON,\n" +
+				"  `attributes` JSON,\n" +
+				"  PRIMARY KEY (`name`)\n" +
+				")",
+		},
+		{
+			description: "sqlite",
+			dialect:     SQLite,
+			expect: `CREATE TABLE "foo" (
+  "id" BIGINT NOT NULL,
+  "name" TEXT NOT NULL,
+  "qty" INTEGER,
+  "ratio" REAL,
+  "price" DOUBLE,
+  "active" BOOLEAN,
+  "updated" TIMESTAMP,
+  "day" DATE,
+  "avatar" BLOB,
+  "tags" TEXT,
+  "address" TEXT,
+  "addresses" TEXT,
+  "attributes" TEXT
+)`,
+		},
+		{
+			description: "bigquery",
+			dialect:     BigQuery,
+			options:     []Option{PrimaryKeyOpt("id")},
+			expect: "CREATE TABLE `foo` (\n" +
+				"  `id` INT64 NOT NULL,\n" +
+				"  `name` STRING NOT NULL,\n" +
+				"  `qty` INT64,\n" +
+				"  `ratio` FLOAT64,\n" +
+				"  `price` FLOAT64,\n" +
+				"  `active` BOOL,\n" +
+				"  `updated` TIMESTAMP,\n" +
+				"  `day` DATE,\n" +
+				"  `avatar` BYTES,\n" +
+				"  `tags` ARRAY<STRING>,\n" +
+				"  `address` STRUCT<`city` STRING NOT NULL, `zip` STRING>,\n" +
+				"  `addresses` ARRAY<STRUCT<`city` STRING NOT NULL, `zip` STRING>>,\n" +
+				"  `attributes` JSON,\n" +
+				"  PRIMARY KEY (`id`) NOT ENFORCED\n" +
+				")",
+		},
+		{
+			description: "unsupported dialect",
+			dialect:     Dialect("oracle"),
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := CreateTable(provider.Proto, testCase.dialect, testCase.options...)
+		if testCase.expectError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+}
+
+func TestDialect_Quote(t *testing.T) {
+	var testCases = []struct {
+		description string
+		dialect     Dialect
+		name        string
+		expect      string
+	}{
+		{description: "postgres", dialect: PostgreSQL, name: "id", expect: `"id"`},
+		{description: "postgres embedded quote", dialect: PostgreSQL, name: `a"b`, expect: `"a""b"`},
+		{description: "mysql embedded quote", dialect: MySQL, name: "a`b", expect: "`a``b`"},
+		{description: "bigquery double quote", dialect: BigQuery, name: `a"b`, expect: "`a\"b`"},
+	}
+
+	for _, testCase := range testCases {
+		assert.EqualValues(t, testCase.expect, testCase.dialect.Quote(testCase.name), testCase.description)
+	}
+
+	statement := "CREATE TABLE \"t\" (\n  \"a\"\"b\" TEXT\n)"
+	provider, err := ParseCreateTable(statement)
+	if !assert.Nil(t, err) {
+		return
+	}
+	actual, err := CreateTable(provider.Proto, PostgreSQL)
+	assert.Nil(t, err)
+	assert.EqualValues(t, statement, actual)
+}
@@ -0,0 +1,239 @@
+package gtly
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/viant/toolbox/format"
+	"gopkg.in/yaml.v2"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+)
+
+//Definition represents declarative provider definition
+type Definition struct {
+	Name             string             `json:",omitempty" yaml:"Name,omitempty"`
+	OmitEmpty        bool               `json:",omitempty" yaml:"OmitEmpty,omitempty"`
+	EmptyValues      []interface{}      `json:",omitempty" yaml:"EmptyValues,omitempty"`
+	TimeLayout       string             `json:",omitempty" yaml:"TimeLayout,omitempty"`
+	CaseFormat       string             `json:",omitempty" yaml:"CaseFormat,omitempty"`
+	OutputCaseFormat string             `json:",omitempty" yaml:"OutputCaseFormat,omitempty"`
+	InputCaseFormat  string             `json:",omitempty" yaml:"InputCaseFormat,omitempty"`
+	Fields           []*FieldDefinition `json:",omitempty" yaml:"Fields,omitempty"`
+}
+
+//FieldDefinition represents declarative field definition, Definition describes nested object or array item provider
+type FieldDefinition struct {
+	Name          string      `json:",omitempty" yaml:"Name,omitempty"`
+	DataType      string      `json:",omitempty" yaml:"DataType,omitempty"`
+	DataLayout    string      `json:",omitempty" yaml:"DataLayout,omitempty"`
+	InputName     string      `json:",omitempty" yaml:"InputName,omitempty"`
+	OutputName    string      `json:",omitempty" yaml:"OutputName,omitempty"`
+	ComponentType string      `json:",omitempty" yaml:"ComponentType,omitempty"`
+	OmitEmpty     *bool       `json:",omitempty" yaml:"OmitEmpty,omitempty"`
+	Hidden        bool        `json:",omitempty" yaml:"Hidden,omitempty"`
+	Definition    *Definition `json:",omitempty" yaml:"Definition,omitempty"`
+}
+
+//Definition returns provider definition
+func (p *Provider) Definition() (*Definition, error) {
+	result := &Definition{
+		Name:      p.Name,
+		OmitEmpty: p.OmitEmpty,
+	}
+	if p.timeLayout != defaultTimeLayout {
+		result.TimeLayout = p.timeLayout
+	}
+	if p.OmitEmpty && !reflect.DeepEqual(p.emptyValues, defaultEmptyValues) {
+		for value := range p.emptyValues {
+			result.EmptyValues = append(result.EmptyValues, value)
+		}
+		sort.Slice(result.EmptyValues, func(i, j int) bool {
+			return fmt.Sprint(result.EmptyValues[i]) < fmt.Sprint(result.EmptyValues[j])
+		})
+	}
+	if p.caseFormat != p.outputCaseFormat || p.caseFormat != p.inputCaseFormat {
+		result.CaseFormat = caseName(p.caseFormat)
+		if p.caseFormat != p.outputCaseFormat {
+			result.OutputCaseFormat = caseName(p.outputCaseFormat)
+		}
+		if p.caseFormat != p.inputCaseFormat {
+			result.InputCaseFormat = caseName(p.inputCaseFormat)
+		}
+	}
+	result.Fields = make([]*FieldDefinition, len(p.fields))
+	for i := range p.fields {
+		field, err := newFieldDefinition(&p.fields[i])
+		if err != nil {
+			return nil, err
+		}
+		if p.caseFormat == p.outputCaseFormat && p.fields[i].outputName != p.fields[i].Name {
+			field.OutputName = p.fields[i].outputName
+		}
+		result.Fields[i] = field
+	}
+	return result, nil
+}
+
+//MarshalSchema returns JSON provider definition
+func (p *Provider) MarshalSchema() ([]byte, error) {
+	definition, err := p.Definition()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(definition, "", "  ")
+}
+
+//NewProviderFromDefinition creates a provider from supplied definition
+func NewProviderFromDefinition(definition *Definition) (*Provider, error) {
+	if len(definition.Fields) == 0 {
+		return nil, errors.Errorf("provider %v fields were empty", definition.Name)
+	}
+	var fields = make([]*Field, len(definition.Fields))
+	for i, fieldDefinition := range definition.Fields {
+		field, err := fieldDefinition.newField()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid provider %v", definition.Name)
+		}
+		fields[i] = field
+	}
+	provider, err := NewProvider(definition.Name, fields...)
+	if err != nil {
+		return nil, err
+	}
+	if definition.OmitEmpty {
+		provider.SetOmitEmpty(true)
+		if len(definition.EmptyValues) > 0 {
+			provider.SetEmptyValues(definition.EmptyValues...)
+		}
+	}
+	if definition.TimeLayout != "" {
+		provider.SetTimeLayout(definition.TimeLayout)
+	}
+	if definition.OutputCaseFormat != "" || definition.InputCaseFormat != "" {
+		source, err := format.NewCase(definition.CaseFormat)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid provider %v case format", definition.Name)
+		}
+		if definition.OutputCaseFormat != "" {
+			output, err := format.NewCase(definition.OutputCaseFormat)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid provider %v output case format", definition.Name)
+			}
+			if err = provider.OutputCaseFormat(source, output); err != nil {
+				return nil, err
+			}
+		}
+		if definition.InputCaseFormat != "" {
+			input, err := format.NewCase(definition.InputCaseFormat)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid provider %v input case format", definition.Name)
+			}
+			if err = provider.InputCaseFormat(source, input); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, fieldDefinition := range definition.Fields {
+		if fieldDefinition.Hidden {
+			provider.Hide(fieldDefinition.Name)
+		}
+	}
+	return provider, nil
+}
+
+//LoadProvider loads a provider from JSON or YAML definition
+func LoadProvider(reader io.Reader) (*Provider, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	definition := &Definition{}
+	if err = yaml.Unmarshal(data, definition); err != nil {
+		return nil, errors.Wrap(err, "failed to decode provider definition")
+	}
+	return NewProviderFromDefinition(definition)
+}
+
+func newFieldDefinition(field *Field) (*FieldDefinition, error) {
+	result := &FieldDefinition{
+		Name:          field.Name,
+		DataType:      field.DataType,
+		DataLayout:    field.DataLayout,
+		InputName:     field.InputName,
+		ComponentType: field.ComponentType,
+		OmitEmpty:     field.OmitEmpty,
+		Hidden:        field.hidden,
+	}
+	if field.itemProvider != nil {
+		definition, err := field.itemProvider.Definition()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid field %v", field.Name)
+		}
+		result.Definition = definition
+		result.DataType = FieldTypeObject
+		if field.Type == typeArray {
+			result.DataType = FieldTypeArray
+		}
+		return result, nil
+	}
+	if result.DataType == FieldTypeArray && getArrayType(result.ComponentType) != nil {
+		return result, nil
+	}
+	if field.Type.Kind() == reflect.Slice && field.Type != typeBytes {
+		if componentType := typeNameForType(field.Type.Elem()); getBaseType(componentType) != nil {
+			result.DataType = FieldTypeArray
+			result.ComponentType = componentType
+			return result, nil
+		}
+	}
+	if getBaseType(result.DataType) == nil {
+		return nil, errors.Errorf("unsupported field %v type: %v", field.Name, field.Type)
+	}
+	return result, nil
+}
+
+func (d *FieldDefinition) newField() (*Field, error) {
+	if d.Name == "" {
+		return nil, errors.New("field name was empty")
+	}
+	var options = []Option{DateLayoutOpt(d.DataLayout), ComponentTypeOpt(d.ComponentType)}
+	if d.OutputName != "" {
+		options = append(options, OutputNameOpt(d.OutputName))
+	}
+	if d.Definition != nil {
+		provider, err := NewProviderFromDefinition(d.Definition)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid field %v", d.Name)
+		}
+		options = append(options, ProviderOpt(provider))
+	}
+	field := NewField(d.Name, d.DataType, options...)
+	field.InputName = d.InputName
+	field.OmitEmpty = d.OmitEmpty
+	if field.Type == nil {
+		return nil, errors.Errorf("unsupported field %v data type: %v", d.Name, d.DataType)
+	}
+	return field, nil
+}
+
+//caseName returns case format name accepted by format.NewCase
+func caseName(aCase format.Case) string {
+	switch aCase {
+	case format.CaseUpper:
+		return "upper"
+	case format.CaseLower:
+		return "lower"
+	case format.CaseUpperCamel:
+		return "upperCamel"
+	case format.CaseLowerCamel:
+		return "lowerCamel"
+	case format.CaseUpperUnderscore:
+		return "upperUnderscore"
+	case format.CaseLowerUnderscore:
+		return "lowerUnderscore"
+	}
+	return ""
+}
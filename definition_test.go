@@ -0,0 +1,163 @@
+package gtly_test
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"github.com/viant/toolbox/format"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newDefinitionProvider(t *testing.T) *gtly.Provider {
+	address, err := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("zipCode", gtly.FieldTypeInt, gtly.OutputNameOpt("zip")),
+	)
+	assert.Nil(t, err)
+	address.SetTimeLayout("2006-01-02")
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("firstName", gtly.FieldTypeString, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("secret", gtly.FieldTypeString),
+		gtly.NewField("updated", gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02 15:04")),
+		gtly.NewField("numbers", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeInt)),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(address)),
+		gtly.NewField("addresses", gtly.FieldTypeArray, gtly.ProviderOpt(address)),
+		&gtly.Field{Name: "ratios", Type: reflect.TypeOf([]float64{})},
+	)
+	assert.Nil(t, err)
+	provider.Hide("secret")
+	provider.SetOmitEmpty(true)
+	provider.SetEmptyValues("", 0)
+	assert.Nil(t, provider.OutputCaseFormat(format.CaseLowerCamel, format.CaseLowerUnderscore))
+	assert.Nil(t, provider.InputCaseFormat(format.CaseLowerCamel, format.CaseUpperUnderscore))
+	return provider
+}
+
+func TestProvider_MarshalSchema(t *testing.T) {
+	provider := newDefinitionProvider(t)
+	schema, err := provider.MarshalSchema()
+	if !assert.Nil(t, err) {
+		return
+	}
+	loaded, err := gtly.LoadProvider(bytes.NewReader(schema))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, provider.Type(), loaded.Type())
+	loadedSchema, err := loaded.MarshalSchema()
+	assert.Nil(t, err)
+	assert.EqualValues(t, string(schema), string(loadedSchema))
+
+	assert.True(t, loaded.Field("secret").IsHidden())
+	assert.EqualValues(t, "first_name", loaded.Field("firstName").OutputName())
+	assert.NotNil(t, loaded.Lookup("FIRST_NAME"))
+	assert.EqualValues(t, "2006-01-02 15:04", loaded.Field("updated").TimeLayout())
+	assert.EqualValues(t, "2006-01-02", loaded.Field("address").ItemProvider().TimeLayout())
+	assert.EqualValues(t, "zip", loaded.Field("address").ItemProvider().Field("zipCode").OutputName())
+
+	object := loaded.NewObject()
+	object.SetValue("id", 1)
+	object.SetValue("secret", "x")
+	object.SetValue("firstName", "Foo")
+	assert.EqualValues(t, map[string]interface{}{"id": 1, "first_name": "Foo"}, object.AsMap())
+}
+
+func TestLoadProvider(t *testing.T) {
+	testCases := []struct {
+		description  string
+		input        string
+		expectFields []string
+		expectError  bool
+	}{
+		{
+			description: "yaml definition",
+			input: `Name: foo
+OutputCaseFormat: upperUnderscore
+CaseFormat: lowerCamel
+Fields:
+  - Name: id
+    DataType: int
+  - Name: tags
+    DataType: array
+    ComponentType: string
+  - Name: address
+    DataType: object
+    Definition:
+      Name: address
+      Fields:
+        - Name: city
+          DataType: string
+`,
+			expectFields: []string{"ID:int", "TAGS:array", "ADDRESS:object"},
+		},
+		{
+			description:  "json definition",
+			input:        `{"Name":"foo","Fields":[{"Name":"id","DataType":"int64","OutputName":"ID"},{"Name":"created","DataType":"time","DataLayout":"2006-01-02"}]}`,
+			expectFields: []string{"ID:int64", "created:time"},
+		},
+		{
+			description: "unsupported data type",
+			input:       `{"Name":"foo","Fields":[{"Name":"id","DataType":"decimal"}]}`,
+			expectError: true,
+		},
+		{
+			description: "empty fields",
+			input:       `{"Name":"foo"}`,
+			expectError: true,
+		},
+		{
+			description: "invalid case format",
+			input:       `{"Name":"foo","OutputCaseFormat":"x","Fields":[{"Name":"id","DataType":"int"}]}`,
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		provider, err := gtly.LoadProvider(strings.NewReader(testCase.input))
+		if testCase.expectError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var actual []string
+		for _, field := range provider.Fields() {
+			actual = append(actual, field.OutputName()+":"+field.DataType)
+		}
+		assert.EqualValues(t, testCase.expectFields, actual, testCase.description)
+	}
+}
+
+func TestProvider_InputCaseFormat(t *testing.T) {
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("firstName", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, provider.InputCaseFormat(format.CaseLowerCamel, format.CaseLowerUnderscore))
+	assert.Nil(t, provider.AddField(gtly.NewField("lastName", gtly.FieldTypeString)))
+	assert.NotNil(t, provider.Lookup("last_name"), "input name shall be indexed for added field")
+
+	schema, err := provider.MarshalSchema()
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.False(t, strings.Contains(string(schema), "OutputCaseFormat"), string(schema))
+	loaded, err := gtly.LoadProvider(bytes.NewReader(schema))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, aProvider := range []*gtly.Provider{provider, loaded} {
+		object := aProvider.NewObject()
+		object.SetValue("id", 1)
+		object.SetValue("first_name", "Foo")
+		object.SetValue("lastName", "Bar")
+		assert.EqualValues(t, map[string]interface{}{"id": 1, "firstName": "Foo", "lastName": "Bar"}, object.AsMap())
+	}
+}
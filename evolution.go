@@ -0,0 +1,243 @@
+package gtly
+
+import (
+	"github.com/pkg/errors"
+	"github.com/viant/toolbox"
+	"reflect"
+	"time"
+)
+
+//AddField adds a field to provider, provider switches to a new proto version, use Migrate to upgrade existing objects
+func (p *Provider) AddField(field *Field) error {
+	if field.Name == "" {
+		return errors.New("field name was empty")
+	}
+	if p.Lookup(field.Name) != nil {
+		return errors.Errorf("field %v already exists", field.Name)
+	}
+	fields := p.fieldCopies()
+	return p.evolve(append(fields, field))
+}
+
+//DropField removes a field from provider, provider switches to a new proto version, use Migrate to upgrade existing objects
+func (p *Provider) DropField(name string) error {
+	field := p.Lookup(name)
+	if field == nil {
+		return errors.Errorf("unknown field: %v", name)
+	}
+	if len(p.fields) == 1 {
+		return errors.Errorf("unable to drop the last field: %v", name)
+	}
+	fields := p.fieldCopies()
+	return p.evolve(append(fields[:field.Index], fields[field.Index+1:]...))
+}
+
+//ChangeType changes field data type, provider switches to a new proto version, use Migrate to upgrade and convert existing objects values
+func (p *Provider) ChangeType(name string, dataType string, options ...Option) error {
+	field := p.Lookup(name)
+	if field == nil {
+		return errors.Errorf("unknown field: %v", name)
+	}
+	fields := p.fieldCopies()
+	changed := fields[field.Index]
+	changed.DataType = dataType
+	changed.Type = nil
+	for _, option := range options {
+		option(changed)
+	}
+	if changed.Type == nil && getBaseType(changed.DataType) == nil && (changed.DataType != FieldTypeArray || getArrayType(changed.ComponentType) == nil) {
+		return errors.Errorf("unsupported field %v data type: %v", name, dataType)
+	}
+	return p.evolve(fields)
+}
+
+//Migrate upgrades object in place to the provider proto version, values are copied by field name and converted to changed field types
+func (p *Provider) Migrate(object *Object) error {
+	previous := object.proto
+	if previous == p.Proto {
+		return nil
+	}
+	target := p.NewObject()
+	for i := range p.fields {
+		field := &p.fields[i]
+		source := previous.Lookup(field.Name)
+		if source == nil || source.Name != field.Name || !object.SetAt(source.Index) {
+			continue
+		}
+		value := previous.accessors[source.Index].Value(object)
+		if previous.accessors[source.Index].Type != p.accessors[i].Type {
+			converted, err := convertValue(value, p.accessors[i].Type, field.TimeLayout())
+			if err != nil {
+				return errors.Wrapf(err, "failed to migrate field %v", field.Name)
+			}
+			if converted == nil {
+				continue
+			}
+			value = converted
+		}
+		p.mutators[i].SetValue(target, value)
+	}
+	*object = *target
+	return nil
+}
+
+//MigrateCollection upgrades collection objects in place to the provider proto version, map and multimap objects are keyed again.
+//Objects shared with other collections are upgraded too, migrate these collections as well
+func (p *Provider) MigrateCollection(collection Collection) error {
+	switch actual := collection.(type) {
+	case *Map:
+		return p.migrateMap(actual)
+	case *Multimap:
+		return p.migrateMultimap(actual)
+	}
+	err := collection.Objects(func(item *Object) (bool, error) {
+		err := p.Migrate(item)
+		return err == nil, err
+	})
+	if array, ok := collection.(*Array); ok && err == nil {
+		array._provider = p.pinned()
+	}
+	return err
+}
+
+//migrateMap upgrades map objects, objects stored under key provider key are keyed again
+func (p *Provider) migrateMap(aMap *Map) error {
+	result := make(map[interface{}]*Object, len(aMap._map))
+	for key, item := range aMap._map {
+		reKey := aMap.keyProvider != nil && aMap.keyProvider(item) == key
+		if err := p.Migrate(item); err != nil {
+			return err
+		}
+		if reKey {
+			key = aMap.keyProvider(item)
+		}
+		result[key] = item
+	}
+	aMap._map = result
+	aMap._provider = p.pinned()
+	return nil
+}
+
+//migrateMultimap upgrades multimap objects, objects stored under key provider key are keyed again
+func (p *Provider) migrateMultimap(multimap *Multimap) error {
+	result := make(map[interface{}][]*Object, len(multimap._map))
+	for key, items := range multimap._map {
+		for _, item := range items {
+			itemKey := key
+			reKey := multimap.keyProvider != nil && multimap.keyProvider(item) == key
+			if err := p.Migrate(item); err != nil {
+				return err
+			}
+			if reKey {
+				itemKey = multimap.keyProvider(item)
+			}
+			result[itemKey] = append(result[itemKey], item)
+		}
+	}
+	multimap._map = result
+	multimap._provider = p.pinned()
+	return nil
+}
+
+//pinned returns a provider bound to the current proto version, collections keep it when the provider evolves
+func (p *Provider) pinned() *Provider {
+	return &Provider{Proto: p.Proto}
+}
+
+//fieldCopies returns copies of the current proto fields
+func (p *Provider) fieldCopies() []*Field {
+	var result = make([]*Field, len(p.fields))
+	for i := range p.fields {
+		field := p.fields[i]
+		result[i] = &field
+	}
+	return result
+}
+
+//evolve replaces provider proto with a new version built from supplied fields, proto settings are carried over
+func (p *Provider) evolve(fields []*Field) error {
+	previous := p.Proto
+	for i, field := range fields {
+		field.init(i, p)
+	}
+	proto := newProto(previous.Name, fields)
+	proto.OmitEmpty = previous.OmitEmpty
+	proto.emptyValues = previous.emptyValues
+	proto.timeLayout = previous.timeLayout
+	proto.version = previous.version + 1
+	if previous.caseFormat != previous.outputCaseFormat {
+		if err := proto.OutputCaseFormat(previous.caseFormat, previous.outputCaseFormat); err != nil {
+			return err
+		}
+	}
+	if previous.caseFormat != previous.inputCaseFormat {
+		if err := proto.InputCaseFormat(previous.caseFormat, previous.inputCaseFormat); err != nil {
+			return err
+		}
+	}
+	p.Proto = proto
+	return nil
+}
+
+//convertValue converts value to target type, returns nil for nil value
+func convertValue(value interface{}, target reflect.Type, timeLayout string) (interface{}, error) {
+	if value = Value(value); value == nil {
+		return nil, nil
+	}
+	source := reflect.ValueOf(value)
+	if source.Type() == target {
+		return value, nil
+	}
+	if source.Kind() == reflect.Ptr {
+		if source.IsNil() {
+			return nil, nil
+		}
+		return convertValue(source.Elem().Interface(), target, timeLayout)
+	}
+	if target.Kind() == reflect.Ptr {
+		converted, err := convertValue(value, target.Elem(), timeLayout)
+		if err != nil || converted == nil {
+			return nil, err
+		}
+		result := reflect.New(target.Elem())
+		result.Elem().Set(reflect.ValueOf(converted))
+		return result.Interface(), nil
+	}
+	switch target {
+	case typeTime:
+		converted, err := toolbox.ToTime(value, timeLayout)
+		if err != nil {
+			return nil, err
+		}
+		return *converted, nil
+	case typeString:
+		switch actual := value.(type) {
+		case time.Time:
+			return actual.Format(timeLayout), nil
+		case []byte:
+			return string(actual), nil
+		}
+		return toolbox.AsString(value), nil
+	}
+	switch target.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8,
+		reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		converted, err := toolbox.ToInt(value)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ValueOf(converted).Convert(target).Interface(), nil
+	case reflect.Float32, reflect.Float64:
+		converted, err := toolbox.ToFloat(value)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ValueOf(converted).Convert(target).Interface(), nil
+	case reflect.Bool:
+		return toolbox.ToBoolean(value)
+	}
+	if source.Type().ConvertibleTo(target) {
+		return source.Convert(target).Interface(), nil
+	}
+	return nil, errors.Errorf("unable to convert %T to %v", value, target)
+}
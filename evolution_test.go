@@ -0,0 +1,218 @@
+package gtly_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"github.com/viant/toolbox/format"
+	"testing"
+	"time"
+)
+
+func newEvolutionProvider(t *testing.T) *gtly.Provider {
+	provider, err := gtly.NewProvider("event",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+		gtly.NewField("score", gtly.FieldTypeString),
+		gtly.NewField("created", gtly.FieldTypeString),
+	)
+	assert.Nil(t, err)
+	return provider
+}
+
+func TestProvider_Evolution(t *testing.T) {
+	testCases := []struct {
+		description string
+		evolve      func(provider *gtly.Provider) error
+		values      map[string]interface{}
+		expect      map[string]interface{}
+		expectError string
+	}{
+		{
+			description: "add field",
+			evolve: func(provider *gtly.Provider) error {
+				return provider.AddField(gtly.NewField("city", gtly.FieldTypeString))
+			},
+			values: map[string]interface{}{"id": 1, "name": "Foo"},
+			expect: map[string]interface{}{"id": 1, "name": "Foo", "city": nil},
+		},
+		{
+			description: "drop field",
+			evolve: func(provider *gtly.Provider) error {
+				return provider.DropField("name")
+			},
+			values: map[string]interface{}{"id": 1, "name": "Foo", "score": "x"},
+			expect: map[string]interface{}{"id": 1, "score": "x", "name": nil},
+		},
+		{
+			description: "change type",
+			evolve: func(provider *gtly.Provider) error {
+				if err := provider.ChangeType("score", gtly.FieldTypeFloat64); err != nil {
+					return err
+				}
+				return provider.ChangeType("created", gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02"))
+			},
+			values: map[string]interface{}{"id": 1, "score": "1.5", "created": "2021-02-03"},
+			expect: map[string]interface{}{"id": 1, "score": 1.5, "created": time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			description: "change type to string",
+			evolve: func(provider *gtly.Provider) error {
+				return provider.ChangeType("id", gtly.FieldTypeString)
+			},
+			values: map[string]interface{}{"id": 12},
+			expect: map[string]interface{}{"id": "12"},
+		},
+		{
+			description: "failed conversion",
+			evolve: func(provider *gtly.Provider) error {
+				return provider.ChangeType("name", gtly.FieldTypeInt)
+			},
+			values:      map[string]interface{}{"id": 1, "name": "Foo"},
+			expectError: "failed to migrate field name",
+		},
+		{
+			description: "add existing field",
+			evolve: func(provider *gtly.Provider) error {
+				return provider.AddField(gtly.NewField("id", gtly.FieldTypeInt))
+			},
+			expectError: "field id already exists",
+		},
+		{
+			description: "drop unknown field",
+			evolve: func(provider *gtly.Provider) error {
+				return provider.DropField("city")
+			},
+			expectError: "unknown field: city",
+		},
+		{
+			description: "change to unsupported type",
+			evolve: func(provider *gtly.Provider) error {
+				return provider.ChangeType("id", "decimal")
+			},
+			expectError: "unsupported field id data type: decimal",
+		},
+	}
+
+	for _, testCase := range testCases {
+		provider := newEvolutionProvider(t)
+		object := provider.NewObject()
+		assert.Nil(t, object.Set(testCase.values), testCase.description)
+		err := testCase.evolve(provider)
+		if err == nil {
+			err = provider.Migrate(object)
+		}
+		if testCase.expectError != "" {
+			if assert.NotNil(t, err, testCase.description) {
+				assert.Contains(t, err.Error(), testCase.expectError, testCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.Equal(t, provider.Proto, object.Proto(), testCase.description)
+		assert.True(t, provider.Version() > 0, testCase.description)
+		for name, expect := range testCase.expect {
+			assert.EqualValues(t, expect, object.Value(name), testCase.description+" "+name)
+		}
+	}
+}
+
+func TestProvider_MigrateCollection(t *testing.T) {
+	provider := newEvolutionProvider(t)
+	_ = provider.OutputCaseFormat(format.CaseLowerCamel, format.CaseUpperCamel)
+	aMap := provider.NewMap(gtly.NewKeyProvider("id"))
+	array := provider.NewArray()
+	for i := 1; i <= 3; i++ {
+		item := provider.NewObject()
+		item.SetValue("id", i)
+		item.SetValue("name", "n")
+		aMap.AddObject(item)
+		array.AddObject(item)
+	}
+	assert.Nil(t, provider.DropField("name"))
+	assert.Nil(t, provider.AddField(gtly.NewField("city", gtly.FieldTypeString)))
+	assert.Equal(t, 2, provider.Version())
+	assert.Equal(t, 0, array.Proto().Version(), "collection shall keep proto until migrated")
+	assert.Nil(t, provider.MigrateCollection(aMap))
+	assert.Nil(t, provider.MigrateCollection(array))
+	assert.Equal(t, provider.Proto, aMap.Proto())
+	assert.Equal(t, provider.Proto, array.Proto())
+	assert.Equal(t, provider.Proto, array.First().Proto())
+	assert.Equal(t, "City", provider.Field("city").OutputName())
+
+	item := provider.NewObject()
+	item.SetValue("id", 4)
+	item.SetValue("city", "Warsaw")
+	aMap.AddObject(item)
+	assert.Equal(t, "Warsaw", aMap.Object(4).Value("city"), "key provider shall use new proto key index")
+	assert.EqualValues(t, map[string]interface{}{"Id": 1}, aMap.Object(1).AsMap())
+}
+
+func TestProvider_EvolutionLiveCollection(t *testing.T) {
+	provider := newEvolutionProvider(t)
+	array := provider.NewArray()
+	aMap := provider.NewMap(gtly.NewKeyProvider("id"))
+	multimap := provider.NewMultimap(gtly.NewKeyProvider("id"))
+	for i := 1; i <= 3; i++ {
+		values := map[string]interface{}{"id": i, "name": "n"}
+		assert.Nil(t, array.Add(values))
+		assert.Nil(t, aMap.Add(values))
+		assert.Nil(t, multimap.Add(values))
+	}
+	assert.Nil(t, provider.DropField("id"))
+	filtered, err := array.Filter(gtly.Eq("name", "n"))
+	if assert.Nil(t, err, "collection shall use its own proto version") {
+		assert.Equal(t, 3, filtered.Size())
+	}
+
+	assert.Nil(t, provider.AddField(gtly.NewField("id", gtly.FieldTypeInt)))
+	assert.Nil(t, provider.ChangeType("id", gtly.FieldTypeString))
+	assert.Nil(t, provider.MigrateCollection(array))
+	assert.Nil(t, provider.MigrateCollection(aMap))
+	assert.Nil(t, provider.MigrateCollection(multimap))
+	assert.Equal(t, provider.Proto, array.Proto())
+	filtered, err = array.Filter(gtly.Eq("name", "n"))
+	if assert.Nil(t, err) {
+		assert.Equal(t, 3, filtered.Size())
+	}
+	assert.NotNil(t, aMap.Object("1"))
+	assert.Equal(t, 1, multimap.Slice("1").Size())
+}
+
+func TestProvider_MigrateCollection_KeyType(t *testing.T) {
+	provider := newEvolutionProvider(t)
+	aMap := provider.NewMap(gtly.NewKeyProvider("id"))
+	multimap := provider.NewMultimap(gtly.NewKeyProvider("id"))
+	for i := 1; i <= 3; i++ {
+		values := map[string]interface{}{"id": i, "name": "n"}
+		assert.Nil(t, aMap.Add(values))
+		assert.Nil(t, multimap.Add(values))
+	}
+	aMap.PutObject("custom", aMap.Object(1))
+	assert.Nil(t, provider.ChangeType("id", gtly.FieldTypeString))
+	assert.Nil(t, provider.MigrateCollection(aMap))
+	assert.Nil(t, provider.MigrateCollection(multimap))
+	assert.Nil(t, aMap.Object(2))
+	if item := aMap.Object("2"); assert.NotNil(t, item) {
+		assert.Equal(t, "2", item.Value("id"))
+	}
+	assert.NotNil(t, aMap.Object("custom"), "put key shall be kept")
+	assert.Equal(t, 1, multimap.Slice("3").Size())
+}
+
+func TestObject_UnknownField(t *testing.T) {
+	provider := newEvolutionProvider(t)
+	object := provider.NewObject()
+	object.SetValue("id", 1)
+	object.SetValue("unknown", 2)
+	assert.Equal(t, 1, object.Value("id"))
+	assert.Nil(t, object.Value("unknown"))
+	assert.Nil(t, provider.Mutator("unknown"))
+	assert.Nil(t, provider.Accessor("unknown"))
+	assert.Nil(t, object.Set(map[string]interface{}{"unknown": 3}), "unknown key shall be skipped")
+	assert.Nil(t, object.Set(map[string]interface{}{"id": 5, "name": "n", "unknown": 3}))
+	assert.Equal(t, 5, object.Value("id"))
+	assert.Equal(t, "n", object.Value("name"))
+	assert.NotNil(t, object.Set(make([]interface{}, len(provider.Fields())+1)))
+}
@@ -0,0 +1,18 @@
+package expr
+
+import "fmt"
+
+//Error represents expression compile error, position is 1 based character offset
+type Error struct {
+	Position int
+	Message  string
+}
+
+//Error returns error message
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v at position %v", e.Message, e.Position)
+}
+
+func newError(pos int, format string, args ...interface{}) *Error {
+	return &Error{Position: pos + 1, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,98 @@
+//Package expr provides expression language compiled against gtly proto, i.e.
+//
+//	income > 1000 && city in ("Warsaw", "Cracow") && updated > now() - 24h
+//
+//identifiers are resolved to field accessors at compile time, so that evaluation does not use field lookup nor reflection.
+package expr
+
+import (
+	"github.com/viant/gtly"
+	"reflect"
+	"time"
+)
+
+//Expression represents compiled expression
+type Expression struct {
+	source string
+	root   *node
+}
+
+//Type returns expression result type: int64, float64, bool, string, time.Time, time.Duration or nil for null literal
+func (e *Expression) Type() reflect.Type {
+	return kindTypes[e.root.kind]
+}
+
+//Value evaluates expression, returns nil if result is null
+func (e *Expression) Value(object *gtly.Object) interface{} {
+	return e.root.value(object)
+}
+
+//Bool evaluates bool expression, returns false for null or non bool expression
+func (e *Expression) Bool(object *gtly.Object) bool {
+	if e.root.kind != kindBool {
+		return false
+	}
+	value, ok := e.root.boolFn(object)
+	return ok && value
+}
+
+//Int64 evaluates int or duration expression, returns false if result is null
+func (e *Expression) Int64(object *gtly.Object) (int64, bool) {
+	if e.root.kind != kindInt && e.root.kind != kindDuration {
+		return 0, false
+	}
+	return e.root.intFn(object)
+}
+
+//Float64 evaluates numeric expression, returns false if result is null
+func (e *Expression) Float64(object *gtly.Object) (float64, bool) {
+	if !isNumeric(e.root) {
+		return 0, false
+	}
+	return e.root.asFloat()(object)
+}
+
+//String evaluates string expression, returns false if result is null
+func (e *Expression) String(object *gtly.Object) (string, bool) {
+	if e.root.kind != kindString {
+		return "", false
+	}
+	return e.root.stringFn(object)
+}
+
+//Time evaluates time expression, returns false if result is null
+func (e *Expression) Time(object *gtly.Object) (time.Time, bool) {
+	if e.root.kind != kindTime {
+		return time.Time{}, false
+	}
+	return e.root.timeFn(object)
+}
+
+//Source returns expression source
+func (e *Expression) Source() string {
+	return e.source
+}
+
+//Compile compiles expression for supplied proto
+func Compile(source string, proto *gtly.Proto) (*Expression, error) {
+	aParser := &parser{lexer: &lexer{source: source}, proto: proto}
+	root, err := aParser.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+//Predicate returns collection filter predicate for bool expression
+func Predicate(source string) gtly.Predicate {
+	return func(proto *gtly.Proto) (gtly.Matcher, error) {
+		expression, err := Compile(source, proto)
+		if err != nil {
+			return nil, err
+		}
+		if expression.root.kind != kindBool {
+			return nil, newError(expression.root.pos, "expected bool expression, but had %v", expression.root.kind)
+		}
+		return expression.Bool, nil
+	}
+}
@@ -0,0 +1,147 @@
+package expr
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestProvider(t *testing.T) *gtly.Provider {
+	provider, err := gtly.NewProvider("person",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("income", gtly.FieldTypeFloat64),
+		gtly.NewField("active", gtly.FieldTypeBool),
+		gtly.NewField("updated", gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02")),
+		gtly.NewField("score", gtly.FieldTypeFloat32),
+		gtly.NewField("nick", "", func(field *gtly.Field) {
+			var nick *string
+			field.Type = reflect.TypeOf(nick)
+		}),
+		gtly.NewField("tags", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeString)),
+	)
+	assert.Nil(t, err)
+	return provider
+}
+
+func TestCompile(t *testing.T) {
+	provider := newTestProvider(t)
+	now := time.Now()
+	object := provider.NewObject()
+	object.SetValue("id", 7)
+	object.SetValue("city", "Warsaw")
+	object.SetValue("income", 1500.5)
+	object.SetValue("active", true)
+	object.SetValue("updated", now.Add(-time.Hour))
+	nick := "bob"
+	object.SetValue("nick", &nick)
+
+	testCases := []struct {
+		description string
+		source      string
+		expect      interface{}
+		expectError string
+	}{
+		{description: "int arithmetic", source: "id * 2 + 1", expect: int64(15)},
+		{description: "precedence with parenthesis", source: "(id + 3) % 4", expect: int64(2)},
+		{description: "int division by zero is null", source: "id / 0", expect: nil},
+		{description: "float promotion", source: "income - id", expect: 1493.5},
+		{description: "unary minus", source: "-id + 10", expect: int64(3)},
+		{description: "string concatenation", source: `city + "/" + lower("PL")`, expect: "Warsaw/pl"},
+		{description: "len function", source: "len(city)", expect: int64(6)},
+		{description: "pointer field", source: `upper(nick)`, expect: "BOB"},
+		{description: "comparison", source: "income > 1000", expect: true},
+		{description: "and or", source: "income > 1000 && city == 'Cracow' || active", expect: true},
+		{description: "keyword operators", source: "NOT active OR id >= 8", expect: false},
+		{description: "in", source: `city in ("Warsaw", "Cracow")`, expect: true},
+		{description: "not in", source: `id not in (1, 2, 3)`, expect: true},
+		{description: "in with float", source: `income in (1500.5)`, expect: true},
+		{description: "like", source: `city like "W%w"`, expect: true},
+		{description: "not like", source: `city not like "_arsaw"`, expect: false},
+		{description: "time with duration", source: "updated > now() - 24h", expect: true},
+		{description: "time with literal", source: `updated >= "2000-01-02"`, expect: true},
+		{description: "time difference", source: "now() - updated > 30m", expect: true},
+		{description: "unset field is null", source: "score > 0", expect: false},
+		{description: "negated unset field comparison", source: "!(score > 0)", expect: true},
+		{description: "is null", source: "score is null && nick is not null", expect: true},
+		{description: "eq null", source: "score == null && city != null", expect: true},
+		{description: "null arithmetic", source: "score + 1", expect: nil},
+		{description: "escaped string", source: `"a\"b"`, expect: `a"b`},
+		{description: "unknown field", source: "id > 1 && country == 'PL'", expectError: "unknown field country at position 11"},
+		{description: "type mismatch", source: "city > 10", expectError: "operator > is not supported for string and int at position 6"},
+		{description: "logical type mismatch", source: "id && active", expectError: "operator && is not supported for int and bool at position 4"},
+		{description: "invalid time literal", source: `updated > "yesterday"`, expectError: `invalid time "yesterday", expected layout 2006-01-02 at position 11`},
+		{description: "unsupported field type", source: "tags == 1", expectError: "unsupported field tags type: []string at position 1"},
+		{description: "unexpected token", source: "id > ", expectError: "unexpected end of expression at position 6"},
+		{description: "unbalanced parenthesis", source: "(id > 1", expectError: "unexpected end of expression at position 8"},
+		{description: "trailing token", source: "id > 1 2", expectError: "unexpected 2 at position 8"},
+		{description: "unterminated string", source: `city == "abc`, expectError: "unterminated string at position 9"},
+		{description: "invalid duration", source: "updated > now() - 2x", expectError: "invalid duration 2x at position 19"},
+		{description: "unknown function", source: "foo(id)", expectError: "unknown function foo at position 1"},
+		{description: "function argument", source: "len(id)", expectError: "function len expects string argument, but had int at position 5"},
+		{description: "unexpected character", source: "id # 1", expectError: "unexpected character '#' at position 4"},
+	}
+
+	for _, testCase := range testCases {
+		expression, err := Compile(testCase.source, provider.Proto)
+		if testCase.expectError != "" {
+			if assert.NotNil(t, err, testCase.description) {
+				assert.Equal(t, testCase.expectError, err.Error(), testCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, expression.Value(object), testCase.description)
+	}
+}
+
+func TestExpression_Type(t *testing.T) {
+	provider := newTestProvider(t)
+	object := provider.NewObject()
+	object.SetValue("income", 10.0)
+	object.SetValue("updated", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	expression, err := Compile("income * 2", provider.Proto)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "float64", expression.Type().String())
+	value, ok := expression.Float64(object)
+	assert.True(t, ok)
+	assert.Equal(t, 20.0, value)
+	_, ok = expression.Int64(object)
+	assert.False(t, ok)
+
+	expression, err = Compile("updated + 48h", provider.Proto)
+	if !assert.Nil(t, err) {
+		return
+	}
+	ts, ok := expression.Time(object)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC), ts)
+	assert.Equal(t, "updated + 48h", expression.Source())
+}
+
+func TestPredicate(t *testing.T) {
+	provider := newTestProvider(t)
+	array := provider.NewArray()
+	for i, city := range []string{"Warsaw", "Cracow", "Gdansk", "Warsaw"} {
+		_ = array.Add(map[string]interface{}{"id": i + 1, "city": city, "income": float64(i * 1000)})
+	}
+	filtered, err := array.Filter(Predicate(`income >= 1000 && city in ("Warsaw", "Cracow")`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 2, filtered.Size())
+	found, err := array.Find(gtly.And(Predicate("city == 'Gdansk'"), gtly.Gt("id", 1)))
+	if assert.Nil(t, err) && assert.NotNil(t, found) {
+		assert.Equal(t, 3, found.Value("id"))
+	}
+	_, err = array.Filter(Predicate("income + 1"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "expected bool expression, but had float at position 8", err.Error())
+	}
+}
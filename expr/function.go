@@ -0,0 +1,55 @@
+package expr
+
+import (
+	"github.com/viant/gtly"
+	"strings"
+	"time"
+)
+
+//function creates function call node, supported functions: now(), lower(s), upper(s), len(s)
+func function(name string, args []*node, pos int) (*node, error) {
+	switch strings.ToLower(name) {
+	case "now":
+		if len(args) != 0 {
+			return nil, newError(pos, "function %v expects no arguments", name)
+		}
+		return &node{kind: kindTime, pos: pos, timeFn: func(object *gtly.Object) (time.Time, bool) {
+			return time.Now(), true
+		}}, nil
+	case "lower", "upper":
+		if err := expectArgs(name, args, pos, kindString); err != nil {
+			return nil, err
+		}
+		convert := strings.ToLower
+		if strings.ToLower(name) == "upper" {
+			convert = strings.ToUpper
+		}
+		argFn := args[0].stringFn
+		return &node{kind: kindString, pos: pos, stringFn: func(object *gtly.Object) (string, bool) {
+			value, ok := argFn(object)
+			return convert(value), ok
+		}}, nil
+	case "len":
+		if err := expectArgs(name, args, pos, kindString); err != nil {
+			return nil, err
+		}
+		argFn := args[0].stringFn
+		return &node{kind: kindInt, pos: pos, intFn: func(object *gtly.Object) (int64, bool) {
+			value, ok := argFn(object)
+			return int64(len(value)), ok
+		}}, nil
+	}
+	return nil, newError(pos, "unknown function %v", name)
+}
+
+func expectArgs(name string, args []*node, pos int, kinds ...kind) error {
+	if len(args) != len(kinds) {
+		return newError(pos, "function %v expects %v argument(s), but had %v", name, len(kinds), len(args))
+	}
+	for i, arg := range args {
+		if arg.kind != kinds[i] {
+			return newError(arg.pos, "function %v expects %v argument, but had %v", name, kinds[i], arg.kind)
+		}
+	}
+	return nil
+}
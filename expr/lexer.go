@@ -0,0 +1,154 @@
+package expr
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF = tokenKind(iota)
+	tokenIdent
+	tokenInt
+	tokenFloat
+	tokenDuration
+	tokenString
+	tokenOperator
+	tokenKeyword
+)
+
+var keywords = map[string]bool{
+	"and": true, "or": true, "not": true, "in": true, "like": true,
+	"is": true, "null": true, "true": true, "false": true,
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "%", "(", ")", ","}
+
+type token struct {
+	kind  tokenKind
+	text  string
+	pos   int
+	value interface{}
+}
+
+type lexer struct {
+	source string
+	pos    int
+}
+
+//next returns next token, keywords are case insensitive
+func (l *lexer) next() (*token, error) {
+	for l.pos < len(l.source) {
+		r, size := utf8.DecodeRuneInString(l.source[l.pos:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		l.pos += size
+	}
+	start := l.pos
+	if l.pos >= len(l.source) {
+		return &token{kind: tokenEOF, pos: start}, nil
+	}
+	r, _ := utf8.DecodeRuneInString(l.source[l.pos:])
+	switch {
+	case r == '"' || r == '\'':
+		return l.text(r)
+	case unicode.IsDigit(r) || (r == '.' && l.pos+1 < len(l.source) && isDigit(l.source[l.pos+1])):
+		return l.number()
+	case r == '_' || unicode.IsLetter(r):
+		text := l.scan(func(r rune) bool {
+			return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+		})
+		if lower := strings.ToLower(text); keywords[lower] {
+			return &token{kind: tokenKeyword, text: lower, pos: start}, nil
+		}
+		return &token{kind: tokenIdent, text: text, pos: start}, nil
+	}
+	for _, operator := range operators {
+		if strings.HasPrefix(l.source[l.pos:], operator) {
+			l.pos += len(operator)
+			return &token{kind: tokenOperator, text: operator, pos: start}, nil
+		}
+	}
+	return nil, newError(start, "unexpected character %q", r)
+}
+
+func (l *lexer) scan(accept func(r rune) bool) string {
+	start := l.pos
+	for l.pos < len(l.source) {
+		r, size := utf8.DecodeRuneInString(l.source[l.pos:])
+		if !accept(r) {
+			break
+		}
+		l.pos += size
+	}
+	return l.source[start:l.pos]
+}
+
+//number scans int, float or duration literal, duration is a number followed by a unit, i.e. 24h, 1h30m, 500ms
+func (l *lexer) number() (*token, error) {
+	start := l.pos
+	text := l.scan(func(r rune) bool {
+		return unicode.IsDigit(r) || r == '.'
+	})
+	if l.pos < len(l.source) {
+		if r, _ := utf8.DecodeRuneInString(l.source[l.pos:]); unicode.IsLetter(r) {
+			text += l.scan(func(r rune) bool {
+				return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.'
+			})
+			duration, err := time.ParseDuration(text)
+			if err != nil {
+				return nil, newError(start, "invalid duration %v", text)
+			}
+			return &token{kind: tokenDuration, text: text, pos: start, value: duration}, nil
+		}
+	}
+	if strings.Contains(text, ".") {
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, newError(start, "invalid number %v", text)
+		}
+		return &token{kind: tokenFloat, text: text, pos: start, value: value}, nil
+	}
+	value, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return nil, newError(start, "invalid number %v", text)
+	}
+	return &token{kind: tokenInt, text: text, pos: start, value: value}, nil
+}
+
+//text scans quoted string literal, quote is escaped with backslash
+func (l *lexer) text(quote rune) (*token, error) {
+	start := l.pos
+	l.pos++
+	builder := strings.Builder{}
+	for l.pos < len(l.source) {
+		r, size := utf8.DecodeRuneInString(l.source[l.pos:])
+		l.pos += size
+		switch r {
+		case quote:
+			return &token{kind: tokenString, text: l.source[start:l.pos], pos: start, value: builder.String()}, nil
+		case '\\':
+			if l.pos < len(l.source) {
+				r, size = utf8.DecodeRuneInString(l.source[l.pos:])
+				l.pos += size
+				switch r {
+				case 'n':
+					r = '\n'
+				case 't':
+					r = '\t'
+				}
+			}
+		}
+		builder.WriteRune(r)
+	}
+	return nil, newError(start, "unterminated string")
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
@@ -0,0 +1,48 @@
+package expr
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLexer_Next(t *testing.T) {
+	testCases := []struct {
+		description string
+		source      string
+		expect      []interface{}
+	}{
+		{
+			description: "numbers and durations",
+			source:      "1 2.5 .5 1h30m 500ms",
+			expect:      []interface{}{int64(1), 2.5, 0.5, 90 * time.Minute, 500 * time.Millisecond},
+		},
+		{
+			description: "operators",
+			source:      "a<=b&&!c",
+			expect:      []interface{}{"a", "<=", "b", "&&", "!", "c"},
+		},
+		{
+			description: "keywords and strings",
+			source:      `x IS Not null OR y in ('a\'b', "c")`,
+			expect:      []interface{}{"x", "is", "not", "null", "or", "y", "in", "(", "a'b", ",", "c", ")"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		aLexer := &lexer{source: testCase.source}
+		var actual []interface{}
+		for {
+			token, err := aLexer.next()
+			if !assert.Nil(t, err, testCase.description) || token.kind == tokenEOF {
+				break
+			}
+			if token.value != nil {
+				actual = append(actual, token.value)
+				continue
+			}
+			actual = append(actual, token.text)
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+}
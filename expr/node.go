@@ -0,0 +1,224 @@
+package expr
+
+import (
+	"github.com/viant/gtly"
+	"github.com/viant/gtly/internal/memory"
+	"github.com/viant/xunsafe"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+type kind int
+
+const (
+	kindNull = kind(iota)
+	kindInt
+	kindFloat
+	kindBool
+	kindString
+	kindTime
+	kindDuration
+)
+
+var kindNames = []string{"null", "int", "float", "bool", "string", "time", "duration"}
+
+var kindTypes = []reflect.Type{
+	nil,
+	reflect.TypeOf(int64(0)),
+	reflect.TypeOf(float64(0)),
+	reflect.TypeOf(true),
+	reflect.TypeOf(""),
+	reflect.TypeOf(time.Time{}),
+	reflect.TypeOf(time.Duration(0)),
+}
+
+func (k kind) String() string {
+	return kindNames[k]
+}
+
+//node represents compiled expression node, typed function matching node kind returns value and false if value is null, duration uses intFn
+type node struct {
+	kind     kind
+	pos      int
+	constant interface{}
+	layout   string
+	intFn    func(object *gtly.Object) (int64, bool)
+	floatFn  func(object *gtly.Object) (float64, bool)
+	boolFn   func(object *gtly.Object) (bool, bool)
+	stringFn func(object *gtly.Object) (string, bool)
+	timeFn   func(object *gtly.Object) (time.Time, bool)
+}
+
+//value returns node value or nil if value is null
+func (n *node) value(object *gtly.Object) interface{} {
+	var result interface{}
+	var ok bool
+	switch n.kind {
+	case kindInt:
+		result, ok = n.intFn(object)
+	case kindDuration:
+		var value int64
+		value, ok = n.intFn(object)
+		result = time.Duration(value)
+	case kindFloat:
+		result, ok = n.floatFn(object)
+	case kindBool:
+		result, ok = n.boolFn(object)
+	case kindString:
+		result, ok = n.stringFn(object)
+	case kindTime:
+		result, ok = n.timeFn(object)
+	}
+	if !ok {
+		return nil
+	}
+	return result
+}
+
+//isNull returns true if node value is null
+func (n *node) isNull(object *gtly.Object) bool {
+	var ok bool
+	switch n.kind {
+	case kindInt, kindDuration:
+		_, ok = n.intFn(object)
+	case kindFloat:
+		_, ok = n.floatFn(object)
+	case kindBool:
+		_, ok = n.boolFn(object)
+	case kindString:
+		_, ok = n.stringFn(object)
+	case kindTime:
+		_, ok = n.timeFn(object)
+	}
+	return !ok
+}
+
+//asFloat returns float function, int values are converted
+func (n *node) asFloat() func(object *gtly.Object) (float64, bool) {
+	if n.kind == kindFloat {
+		return n.floatFn
+	}
+	intFn := n.intFn
+	return func(object *gtly.Object) (float64, bool) {
+		value, ok := intFn(object)
+		return float64(value), ok
+	}
+}
+
+func newLiteral(value interface{}, pos int) *node {
+	result := &node{pos: pos, constant: value}
+	switch actual := value.(type) {
+	case int64:
+		result.kind = kindInt
+		result.intFn = func(object *gtly.Object) (int64, bool) { return actual, true }
+	case time.Duration:
+		result.kind = kindDuration
+		result.intFn = func(object *gtly.Object) (int64, bool) { return int64(actual), true }
+	case float64:
+		result.kind = kindFloat
+		result.floatFn = func(object *gtly.Object) (float64, bool) { return actual, true }
+	case bool:
+		result.kind = kindBool
+		result.boolFn = func(object *gtly.Object) (bool, bool) { return actual, true }
+	case string:
+		result.kind = kindString
+		result.stringFn = func(object *gtly.Object) (string, bool) { return actual, true }
+	case time.Time:
+		result.kind = kindTime
+		result.timeFn = func(object *gtly.Object) (time.Time, bool) { return actual, true }
+	}
+	return result
+}
+
+//newFieldNode creates node reading field memory with precompiled accessor, field is null when unset or nil pointer
+func newFieldNode(proto *gtly.Proto, field *gtly.Field, pos int) (*node, error) {
+	index := field.Index
+	xField := proto.AccessorAt(index).Field
+	fieldType := xField.Type
+	isPtr := fieldType.Kind() == reflect.Ptr
+	if isPtr {
+		fieldType = fieldType.Elem()
+	}
+	pointer := fieldPointer(index, xField, isPtr)
+	result := &node{pos: pos, layout: field.TimeLayout()}
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8,
+		reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		intKind := fieldType.Kind()
+		result.kind = kindInt
+		if fieldType == kindTypes[kindDuration] {
+			result.kind = kindDuration
+		}
+		result.intFn = func(object *gtly.Object) (int64, bool) {
+			if ptr := pointer(object); ptr != nil {
+				return memory.ReadInt(intKind, ptr), true
+			}
+			return 0, false
+		}
+	case reflect.Float32:
+		result.kind = kindFloat
+		result.floatFn = func(object *gtly.Object) (float64, bool) {
+			if ptr := pointer(object); ptr != nil {
+				return float64(*(*float32)(ptr)), true
+			}
+			return 0, false
+		}
+	case reflect.Float64:
+		result.kind = kindFloat
+		result.floatFn = func(object *gtly.Object) (float64, bool) {
+			if ptr := pointer(object); ptr != nil {
+				return *(*float64)(ptr), true
+			}
+			return 0, false
+		}
+	case reflect.Bool:
+		result.kind = kindBool
+		result.boolFn = func(object *gtly.Object) (bool, bool) {
+			if ptr := pointer(object); ptr != nil {
+				return *(*bool)(ptr), true
+			}
+			return false, false
+		}
+	case reflect.String:
+		result.kind = kindString
+		result.stringFn = func(object *gtly.Object) (string, bool) {
+			if ptr := pointer(object); ptr != nil {
+				return *(*string)(ptr), true
+			}
+			return "", false
+		}
+	case reflect.Struct:
+		if fieldType != kindTypes[kindTime] {
+			return nil, newError(pos, "unsupported field %v type: %v", field.Name, xField.Type)
+		}
+		result.kind = kindTime
+		result.timeFn = func(object *gtly.Object) (time.Time, bool) {
+			if ptr := pointer(object); ptr != nil {
+				return *(*time.Time)(ptr), true
+			}
+			return time.Time{}, false
+		}
+	default:
+		return nil, newError(pos, "unsupported field %v type: %v", field.Name, xField.Type)
+	}
+	return result, nil
+}
+
+//fieldPointer returns function returning field value pointer or nil if field value is null
+func fieldPointer(index int, xField *xunsafe.Field, isPtr bool) func(object *gtly.Object) unsafe.Pointer {
+	if isPtr {
+		return func(object *gtly.Object) unsafe.Pointer {
+			if !object.SetAt(index) {
+				return nil
+			}
+			return *(*unsafe.Pointer)(xField.Pointer(object.Addr()))
+		}
+	}
+	return func(object *gtly.Object) unsafe.Pointer {
+		if !object.SetAt(index) {
+			return nil
+		}
+		return xField.Pointer(object.Addr())
+	}
+}
@@ -0,0 +1,326 @@
+package expr
+
+import (
+	"github.com/viant/gtly"
+	"github.com/viant/gtly/internal/compare"
+	"strings"
+	"time"
+)
+
+func isNumeric(n *node) bool {
+	return n.kind == kindInt || n.kind == kindFloat
+}
+
+//coerce converts string literal compared with time to time using time node layout
+func coerce(left, right *node) (*node, *node, error) {
+	if left.kind == kindTime && right.kind == kindString && right.constant != nil {
+		converted, err := timeLiteral(right, left.layout)
+		return left, converted, err
+	}
+	if right.kind == kindTime && left.kind == kindString && left.constant != nil {
+		converted, err := timeLiteral(left, right.layout)
+		return converted, right, err
+	}
+	return left, right, nil
+}
+
+func timeLiteral(literal *node, layout string) (*node, error) {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	value, err := time.Parse(layout, literal.constant.(string))
+	if err != nil {
+		return nil, newError(literal.pos, "invalid time %q, expected layout %v", literal.constant, layout)
+	}
+	return newLiteral(value, literal.pos), nil
+}
+
+//comparison creates comparison node, comparison with null operand is false, == null and != null check nullability
+func comparison(operator string, left, right *node, pos int) (*node, error) {
+	left, right, err := coerce(left, right)
+	if err != nil {
+		return nil, err
+	}
+	if left.kind == kindNull || right.kind == kindNull {
+		if operator != "==" && operator != "!=" {
+			return nil, newError(pos, "operator %v is not supported for null", operator)
+		}
+		operand := left
+		if left.kind == kindNull {
+			operand = right
+		}
+		return isNull(operand, operator == "!="), nil
+	}
+	var compared func(object *gtly.Object) (int, bool)
+	switch {
+	case left.kind == kindInt && right.kind == kindInt, left.kind == kindDuration && right.kind == kindDuration:
+		leftFn, rightFn := left.intFn, right.intFn
+		compared = func(object *gtly.Object) (int, bool) {
+			x, ok := leftFn(object)
+			y, ok2 := rightFn(object)
+			return compare.Int(x, y), ok && ok2
+		}
+	case isNumeric(left) && isNumeric(right):
+		leftFn, rightFn := left.asFloat(), right.asFloat()
+		compared = func(object *gtly.Object) (int, bool) {
+			x, ok := leftFn(object)
+			y, ok2 := rightFn(object)
+			return compare.Float(x, y), ok && ok2
+		}
+	case left.kind == kindString && right.kind == kindString:
+		leftFn, rightFn := left.stringFn, right.stringFn
+		compared = func(object *gtly.Object) (int, bool) {
+			x, ok := leftFn(object)
+			y, ok2 := rightFn(object)
+			return strings.Compare(x, y), ok && ok2
+		}
+	case left.kind == kindTime && right.kind == kindTime:
+		leftFn, rightFn := left.timeFn, right.timeFn
+		compared = func(object *gtly.Object) (int, bool) {
+			x, ok := leftFn(object)
+			y, ok2 := rightFn(object)
+			return compare.Time(x, y), ok && ok2
+		}
+	case left.kind == kindBool && right.kind == kindBool && (operator == "==" || operator == "!="):
+		leftFn, rightFn := left.boolFn, right.boolFn
+		compared = func(object *gtly.Object) (int, bool) {
+			x, ok := leftFn(object)
+			y, ok2 := rightFn(object)
+			if x == y {
+				return 0, ok && ok2
+			}
+			return 1, ok && ok2
+		}
+	default:
+		return nil, newError(pos, "operator %v is not supported for %v and %v", operator, left.kind, right.kind)
+	}
+	accept := acceptor(operator)
+	return &node{kind: kindBool, pos: left.pos, boolFn: func(object *gtly.Object) (bool, bool) {
+		result, ok := compared(object)
+		return ok && accept(result), true
+	}}, nil
+}
+
+func acceptor(operator string) func(result int) bool {
+	switch operator {
+	case "==":
+		return func(result int) bool { return result == 0 }
+	case "!=":
+		return func(result int) bool { return result != 0 }
+	case "<":
+		return func(result int) bool { return result < 0 }
+	case "<=":
+		return func(result int) bool { return result <= 0 }
+	case ">":
+		return func(result int) bool { return result > 0 }
+	}
+	return func(result int) bool { return result >= 0 }
+}
+
+func isNull(operand *node, negated bool) *node {
+	return &node{kind: kindBool, pos: operand.pos, boolFn: func(object *gtly.Object) (bool, bool) {
+		return operand.isNull(object) != negated, true
+	}}
+}
+
+//in creates node matching any of listed values
+func in(left *node, items []*node, negated bool, pos int) (*node, error) {
+	matchers := make([]func(object *gtly.Object) (bool, bool), len(items))
+	for i, item := range items {
+		if item.kind == kindNull {
+			return nil, newError(item.pos, "null is not supported in list, use is null")
+		}
+		matcher, err := comparison("==", left, item, item.pos)
+		if err != nil {
+			return nil, err
+		}
+		matchers[i] = matcher.boolFn
+	}
+	return &node{kind: kindBool, pos: pos, boolFn: func(object *gtly.Object) (bool, bool) {
+		if left.isNull(object) {
+			return false, true
+		}
+		for _, matcher := range matchers {
+			if matched, _ := matcher(object); matched {
+				return !negated, true
+			}
+		}
+		return negated, true
+	}}, nil
+}
+
+//like creates SQL like pattern matching node, % matches any sequence, _ matches a single character
+func like(left, pattern *node, negated bool, pos int) (*node, error) {
+	if left.kind != kindString {
+		return nil, newError(pos, "operator like is not supported for %v", left.kind)
+	}
+	text, ok := pattern.constant.(string)
+	if !ok {
+		return nil, newError(pattern.pos, "like pattern has to be a string literal")
+	}
+	compiled, err := compare.Like(text)
+	if err != nil {
+		return nil, newError(pattern.pos, "invalid like pattern: %v", err)
+	}
+	leftFn := left.stringFn
+	return &node{kind: kindBool, pos: pos, boolFn: func(object *gtly.Object) (bool, bool) {
+		value, ok := leftFn(object)
+		return ok && compiled.MatchString(value) != negated, true
+	}}, nil
+}
+
+//logical creates && or || node, null operand is false
+func logical(operator string, left, right *node, pos int) (*node, error) {
+	if left.kind != kindBool || right.kind != kindBool {
+		return nil, newError(pos, "operator %v is not supported for %v and %v", operator, left.kind, right.kind)
+	}
+	leftFn, rightFn := left.boolFn, right.boolFn
+	if operator == "&&" {
+		return &node{kind: kindBool, pos: left.pos, boolFn: func(object *gtly.Object) (bool, bool) {
+			if x, ok := leftFn(object); !ok || !x {
+				return false, true
+			}
+			y, ok := rightFn(object)
+			return ok && y, true
+		}}, nil
+	}
+	return &node{kind: kindBool, pos: left.pos, boolFn: func(object *gtly.Object) (bool, bool) {
+		if x, ok := leftFn(object); ok && x {
+			return true, true
+		}
+		y, ok := rightFn(object)
+		return ok && y, true
+	}}, nil
+}
+
+//not creates negation node, null operand is false
+func not(operand *node, pos int) (*node, error) {
+	if operand.kind != kindBool {
+		return nil, newError(pos, "operator ! is not supported for %v", operand.kind)
+	}
+	operandFn := operand.boolFn
+	return &node{kind: kindBool, pos: pos, boolFn: func(object *gtly.Object) (bool, bool) {
+		value, ok := operandFn(object)
+		return !(ok && value), true
+	}}, nil
+}
+
+//negate creates unary minus node
+func negate(operand *node, pos int) (*node, error) {
+	switch operand.kind {
+	case kindInt, kindDuration:
+		operandFn := operand.intFn
+		return &node{kind: operand.kind, pos: pos, intFn: func(object *gtly.Object) (int64, bool) {
+			value, ok := operandFn(object)
+			return -value, ok
+		}}, nil
+	case kindFloat:
+		operandFn := operand.floatFn
+		return &node{kind: kindFloat, pos: pos, floatFn: func(object *gtly.Object) (float64, bool) {
+			value, ok := operandFn(object)
+			return -value, ok
+		}}, nil
+	}
+	return nil, newError(pos, "operator - is not supported for %v", operand.kind)
+}
+
+//arithmetic creates arithmetic node, division by zero is null
+func arithmetic(operator string, left, right *node, pos int) (*node, error) {
+	switch {
+	case left.kind == kindInt && right.kind == kindInt,
+		left.kind == kindDuration && right.kind == kindDuration && (operator == "+" || operator == "-"):
+		op := intOperator(operator)
+		leftFn, rightFn := left.intFn, right.intFn
+		return &node{kind: left.kind, pos: pos, intFn: func(object *gtly.Object) (int64, bool) {
+			x, ok := leftFn(object)
+			y, ok2 := rightFn(object)
+			if !ok || !ok2 {
+				return 0, false
+			}
+			return op(x, y)
+		}}, nil
+	case isNumeric(left) && isNumeric(right) && operator != "%":
+		op := floatOperator(operator)
+		leftFn, rightFn := left.asFloat(), right.asFloat()
+		return &node{kind: kindFloat, pos: pos, floatFn: func(object *gtly.Object) (float64, bool) {
+			x, ok := leftFn(object)
+			y, ok2 := rightFn(object)
+			if !ok || !ok2 {
+				return 0, false
+			}
+			return op(x, y)
+		}}, nil
+	case left.kind == kindString && right.kind == kindString && operator == "+":
+		leftFn, rightFn := left.stringFn, right.stringFn
+		return &node{kind: kindString, pos: pos, stringFn: func(object *gtly.Object) (string, bool) {
+			x, ok := leftFn(object)
+			y, ok2 := rightFn(object)
+			return x + y, ok && ok2
+		}}, nil
+	case left.kind == kindTime && right.kind == kindDuration && (operator == "+" || operator == "-"),
+		left.kind == kindDuration && right.kind == kindTime && operator == "+":
+		timeNode, durationNode := left, right
+		sign := time.Duration(1)
+		if left.kind == kindDuration {
+			timeNode, durationNode = right, left
+		}
+		if operator == "-" {
+			sign = -1
+		}
+		timeFn, durationFn := timeNode.timeFn, durationNode.intFn
+		return &node{kind: kindTime, pos: pos, layout: timeNode.layout, timeFn: func(object *gtly.Object) (time.Time, bool) {
+			x, ok := timeFn(object)
+			y, ok2 := durationFn(object)
+			return x.Add(sign * time.Duration(y)), ok && ok2
+		}}, nil
+	case left.kind == kindTime && right.kind == kindTime && operator == "-":
+		leftFn, rightFn := left.timeFn, right.timeFn
+		return &node{kind: kindDuration, pos: pos, intFn: func(object *gtly.Object) (int64, bool) {
+			x, ok := leftFn(object)
+			y, ok2 := rightFn(object)
+			return int64(x.Sub(y)), ok && ok2
+		}}, nil
+	}
+	return nil, newError(pos, "operator %v is not supported for %v and %v", operator, left.kind, right.kind)
+}
+
+func intOperator(operator string) func(x, y int64) (int64, bool) {
+	switch operator {
+	case "+":
+		return func(x, y int64) (int64, bool) { return x + y, true }
+	case "-":
+		return func(x, y int64) (int64, bool) { return x - y, true }
+	case "*":
+		return func(x, y int64) (int64, bool) { return x * y, true }
+	case "/":
+		return func(x, y int64) (int64, bool) {
+			if y == 0 {
+				return 0, false
+			}
+			return x / y, true
+		}
+	}
+	return func(x, y int64) (int64, bool) {
+		if y == 0 {
+			return 0, false
+		}
+		return x % y, true
+	}
+}
+
+func floatOperator(operator string) func(x, y float64) (float64, bool) {
+	switch operator {
+	case "+":
+		return func(x, y float64) (float64, bool) { return x + y, true }
+	case "-":
+		return func(x, y float64) (float64, bool) { return x - y, true }
+	case "*":
+		return func(x, y float64) (float64, bool) { return x * y, true }
+	}
+	return func(x, y float64) (float64, bool) {
+		if y == 0 {
+			return 0, false
+		}
+		return x / y, true
+	}
+}
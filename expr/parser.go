@@ -0,0 +1,282 @@
+package expr
+
+import (
+	"github.com/viant/gtly"
+)
+
+//parser compiles tokens straight into nodes with recursive descent, precedence from the lowest: ||, &&, !, comparison, + -, * / %, unary -
+type parser struct {
+	lexer *lexer
+	proto *gtly.Proto
+	token *token
+}
+
+func (p *parser) advance() error {
+	token, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.token = token
+	return nil
+}
+
+func (p *parser) is(texts ...string) bool {
+	if p.token.kind != tokenOperator && p.token.kind != tokenKeyword {
+		return false
+	}
+	for _, text := range texts {
+		if p.token.text == text {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.is(text) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+func (p *parser) unexpected() error {
+	if p.token.kind == tokenEOF {
+		return newError(p.token.pos, "unexpected end of expression")
+	}
+	return newError(p.token.pos, "unexpected %v", p.token.text)
+}
+
+func (p *parser) parse() (*node, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	result, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.token.kind != tokenEOF {
+		return nil, p.unexpected()
+	}
+	return result, nil
+}
+
+func (p *parser) parseOr() (*node, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.is("||", "or") {
+		pos := p.token.pos
+		var right *node
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+		if right, err = p.parseAnd(); err == nil {
+			left, err = logical("||", left, right, pos)
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseAnd() (*node, error) {
+	left, err := p.parseNot()
+	for err == nil && p.is("&&", "and") {
+		pos := p.token.pos
+		var right *node
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+		if right, err = p.parseNot(); err == nil {
+			left, err = logical("&&", left, right, pos)
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseNot() (*node, error) {
+	if !p.is("!", "not") {
+		return p.parseComparison()
+	}
+	pos := p.token.pos
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	operand, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	return not(operand, pos)
+}
+
+func (p *parser) parseComparison() (*node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	pos := p.token.pos
+	switch {
+	case p.is("==", "!=", "<", "<=", ">", ">="):
+		operator := p.token.text
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return comparison(operator, left, right, pos)
+	case p.is("is"):
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+		negated := p.is("not")
+		if negated {
+			if err = p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if err = p.expect("null"); err != nil {
+			return nil, err
+		}
+		return isNull(left, negated), nil
+	case p.is("not", "in", "like"):
+		negated := p.is("not")
+		if negated {
+			if err = p.advance(); err != nil {
+				return nil, err
+			}
+			if !p.is("in", "like") {
+				return nil, p.unexpected()
+			}
+		}
+		if p.is("like") {
+			if err = p.advance(); err != nil {
+				return nil, err
+			}
+			pattern, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			return like(left, pattern, negated, pos)
+		}
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+		items, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return in(left, items, negated, pos)
+	}
+	return left, nil
+}
+
+//parseList parses parenthesized comma separated expressions
+func (p *parser) parseList() ([]*node, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var result []*node
+	if p.is(")") {
+		return result, p.advance()
+	}
+	for {
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+		if !p.is(",") {
+			break
+		}
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return result, p.expect(")")
+}
+
+func (p *parser) parseAdditive() (*node, error) {
+	left, err := p.parseMultiplicative()
+	for err == nil && p.is("+", "-") {
+		operator, pos := p.token.text, p.token.pos
+		var right *node
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+		if right, err = p.parseMultiplicative(); err == nil {
+			left, err = arithmetic(operator, left, right, pos)
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseMultiplicative() (*node, error) {
+	left, err := p.parseUnary()
+	for err == nil && p.is("*", "/", "%") {
+		operator, pos := p.token.text, p.token.pos
+		var right *node
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+		if right, err = p.parseUnary(); err == nil {
+			left, err = arithmetic(operator, left, right, pos)
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseUnary() (*node, error) {
+	if !p.is("-") {
+		return p.parsePrimary()
+	}
+	pos := p.token.pos
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return negate(operand, pos)
+}
+
+func (p *parser) parsePrimary() (*node, error) {
+	token := p.token
+	switch token.kind {
+	case tokenInt, tokenFloat, tokenDuration, tokenString:
+		return newLiteral(token.value, token.pos), p.advance()
+	case tokenKeyword:
+		switch token.text {
+		case "true", "false":
+			return newLiteral(token.text == "true", token.pos), p.advance()
+		case "null":
+			return &node{kind: kindNull, pos: token.pos}, p.advance()
+		}
+	case tokenIdent:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.is("(") {
+			args, err := p.parseList()
+			if err != nil {
+				return nil, err
+			}
+			return function(token.text, args, token.pos)
+		}
+		field := p.proto.Lookup(token.text)
+		if field == nil {
+			return nil, newError(token.pos, "unknown field %v", token.text)
+		}
+		return newFieldNode(p.proto, field, token.pos)
+	case tokenOperator:
+		if token.text == "(" {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			result, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return result, p.expect(")")
+		}
+	}
+	return nil, p.unexpected()
+}
@@ -20,6 +20,7 @@ type Field struct {
 	ComponentType string       `json:",omitempty"`
 	Type          reflect.Type `json:"-"`
 	provider      *Provider
+	itemProvider  *Provider
 	outputName    string
 	hidden        bool
 	kind          reflect.Kind
@@ -47,6 +48,9 @@ func (f *Field) init(index int, provider *Provider) {
 	if f.Type == nil && f.DataType != "" {
 		f.Type = getBaseType(f.DataType)
 	}
+	if f.Type == nil && f.DataType == FieldTypeArray {
+		f.Type = getArrayType(f.ComponentType)
+	}
 	if f.DataType == "" && f.Type != nil {
 		f.DataType = typeNameForType(f.Type)
 	}
@@ -60,6 +64,16 @@ func (f *Field) OutputName() string {
 	return f.outputName
 }
 
+//IsHidden returns true if Field is hidden from output
+func (f *Field) IsHidden() bool {
+	return f.hidden
+}
+
+//ItemProvider returns nested object or array item provider, or nil if Field is not provider based
+func (f *Field) ItemProvider() *Provider {
+	return f.itemProvider
+}
+
 //Get returns Field value
 func (f *Field) Get(values []interface{}) interface{} {
 	if f.Index < len(values) {
@@ -85,13 +99,17 @@ func NewField(name, dataType string, options ...Option) *Field {
 	if field.Type == nil {
 		field.Type = getBaseType(field.DataType)
 	}
+	if field.Type == nil && field.DataType == FieldTypeArray {
+		field.Type = getArrayType(field.ComponentType)
+	}
 	if field.provider != nil {
+		field.itemProvider = field.provider
 		if field.Type == nil {
 			switch field.DataType {
 			case FieldTypeArray:
-				field.Type = reflect.SliceOf(field.provider.Type())
+				field.Type = typeArray
 			case FieldTypeObject:
-				field.Type = field.provider.Type()
+				field.Type = typeObject
 			}
 		}
 	}
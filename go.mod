@@ -4,9 +4,12 @@ go 1.15
 
 require (
 	github.com/francoispqt/gojay v1.2.13
+	github.com/golang/snappy v0.0.4
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.7.0
 	github.com/viant/assertly v0.9.0
 	github.com/viant/toolbox v0.34.5
-	github.com/viant/xunsafe v0.8.1-0.20220921220858-82f5aba1919f // indirect
+	github.com/viant/xunsafe v0.8.1-0.20220921220858-82f5aba1919f
+	gopkg.in/yaml.v2 v2.2.2
 )
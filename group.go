@@ -0,0 +1,412 @@
+package gtly
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"reflect"
+	"strings"
+)
+
+const (
+	//AggregateCount counts group objects
+	AggregateCount = "count"
+	//AggregateSum sums numeric field values
+	AggregateSum = "sum"
+	//AggregateAvg averages numeric field values
+	AggregateAvg = "avg"
+	//AggregateMin returns min field value
+	AggregateMin = "min"
+	//AggregateMax returns max field value
+	AggregateMax = "max"
+	//AggregateCountDistinct counts distinct field values
+	AggregateCountDistinct = "countDistinct"
+	//AggregateFirst returns the first group object field value
+	AggregateFirst = "first"
+	//AggregateLast returns the last group object field value
+	AggregateLast = "last"
+	//AggregateCollect collects field values into an array
+	AggregateCollect = "collect"
+)
+
+//Aggregate represents group aggregate, null values are skipped, an aggregate without values is null
+type Aggregate struct {
+	Function string
+	Field    string
+	Alias    string
+}
+
+//As returns aggregate with supplied output field name
+func (a Aggregate) As(alias string) Aggregate {
+	a.Alias = alias
+	return a
+}
+
+//OutputName returns aggregate output field name, function name followed by field name unless alias was set
+func (a Aggregate) OutputName() string {
+	if a.Alias != "" {
+		return a.Alias
+	}
+	if a.Field == "" {
+		return a.Function
+	}
+	return a.Function + strings.ToUpper(a.Field[:1]) + a.Field[1:]
+}
+
+//Count returns group object count aggregate, count with Field set counts non null field values
+func Count() Aggregate {
+	return Aggregate{Function: AggregateCount}
+}
+
+//Sum returns sum aggregate
+func Sum(field string) Aggregate {
+	return Aggregate{Function: AggregateSum, Field: field}
+}
+
+//Avg returns average aggregate
+func Avg(field string) Aggregate {
+	return Aggregate{Function: AggregateAvg, Field: field}
+}
+
+//Min returns min aggregate
+func Min(field string) Aggregate {
+	return Aggregate{Function: AggregateMin, Field: field}
+}
+
+//Max returns max aggregate
+func Max(field string) Aggregate {
+	return Aggregate{Function: AggregateMax, Field: field}
+}
+
+//CountDistinct returns distinct value count aggregate
+func CountDistinct(field string) Aggregate {
+	return Aggregate{Function: AggregateCountDistinct, Field: field}
+}
+
+//First returns the first value aggregate
+func First(field string) Aggregate {
+	return Aggregate{Function: AggregateFirst, Field: field}
+}
+
+//Last returns the last value aggregate
+func Last(field string) Aggregate {
+	return Aggregate{Function: AggregateLast, Field: field}
+}
+
+//Collect returns aggregate collecting values into an array
+func Collect(field string) Aggregate {
+	return Aggregate{Function: AggregateCollect, Field: field}
+}
+
+//accumulator accumulates group values
+type accumulator interface {
+	add(object *Object)
+	set(target *Object)
+}
+
+//aggregator creates group accumulators for an aggregate
+type aggregator struct {
+	field *Field
+	new   func(mutator *Mutator) accumulator
+}
+
+//GroupBy groups collection objects by supplied keys, result array uses a new provider with key and aggregate fields, groups keep the first occurrence order
+func GroupBy(collection Collection, keys []string, aggregates ...Aggregate) (*Array, error) {
+	proto := collection.Proto()
+	index, err := newObjectIndex(proto, keys)
+	if err != nil {
+		return nil, err
+	}
+	var fields = make([]*Field, 0, len(keys)+len(aggregates))
+	var keyAccessors = make([]*Accessor, len(keys))
+	for i, key := range keys {
+		field, accessor, _ := lookupAccessor(proto, key)
+		keyAccessors[i] = accessor
+		fields = append(fields, &Field{Name: field.Name, Type: accessor.Type, DataLayout: field.DataLayout, ComponentType: field.ComponentType, itemProvider: field.itemProvider})
+	}
+	aggregators := make([]*aggregator, len(aggregates))
+	for i, aggregate := range aggregates {
+		if aggregators[i], err = newAggregator(proto, aggregate); err != nil {
+			return nil, err
+		}
+		fields = append(fields, aggregators[i].field)
+	}
+	names := map[string]bool{}
+	for _, field := range fields {
+		if names[field.Name] {
+			return nil, errors.Errorf("duplicate group field: %v", field.Name)
+		}
+		names[field.Name] = true
+	}
+	provider, err := NewProvider(proto.Name+"Group", fields...)
+	if err != nil {
+		return nil, err
+	}
+	mutators := make([]*Mutator, len(fields))
+	for i := range fields {
+		mutators[i] = provider.MutatorAt(i)
+	}
+	type group struct {
+		first        *Object
+		accumulators []accumulator
+	}
+	var groups = make([]*group, 0)
+	var groupByKey = make(map[interface{}]*group)
+	err = collection.Objects(func(item *Object) (bool, error) {
+		key := index(item)
+		aGroup, ok := groupByKey[key]
+		if !ok {
+			aGroup = &group{first: item, accumulators: make([]accumulator, len(aggregators))}
+			for i, aggregator := range aggregators {
+				aGroup.accumulators[i] = aggregator.new(mutators[len(keys)+i])
+			}
+			groupByKey[key] = aGroup
+			groups = append(groups, aGroup)
+		}
+		for _, accumulator := range aGroup.accumulators {
+			accumulator.add(item)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := provider.NewArray()
+	for _, aGroup := range groups {
+		target := provider.NewObject()
+		for i, accessor := range keyAccessors {
+			if aGroup.first.SetAt(accessor.index) {
+				mutators[i].SetValue(target, accessor.Value(aGroup.first))
+			}
+		}
+		for _, accumulator := range aGroup.accumulators {
+			accumulator.set(target)
+		}
+		result.AddObject(target)
+	}
+	return result, nil
+}
+
+func newAggregator(proto *Proto, aggregate Aggregate) (*aggregator, error) {
+	name := aggregate.OutputName()
+	if aggregate.Function == AggregateCount && aggregate.Field == "" {
+		return &aggregator{
+			field: &Field{Name: name, DataType: FieldTypeInt},
+			new: func(mutator *Mutator) accumulator {
+				return &countAccumulator{mutator: mutator, isNull: func(object *Object) bool { return false }}
+			},
+		}, nil
+	}
+	source, accessor, err := lookupAccessor(proto, aggregate.Field)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %v aggregate", aggregate.Function)
+	}
+	index := source.Index
+	isNull := func(object *Object) bool {
+		return !object.SetAt(index)
+	}
+	if kind := accessor.Type.Kind(); kind == reflect.Ptr || kind == reflect.Interface {
+		isNull = func(object *Object) bool {
+			return fieldValue(object, index) == nil
+		}
+	}
+	sameType := func() *Field {
+		return &Field{Name: name, Type: accessor.Type, DataLayout: source.DataLayout, ComponentType: source.ComponentType, itemProvider: source.itemProvider}
+	}
+	switch aggregate.Function {
+	case AggregateCount:
+		return &aggregator{
+			field: &Field{Name: name, DataType: FieldTypeInt},
+			new: func(mutator *Mutator) accumulator {
+				return &countAccumulator{mutator: mutator, isNull: isNull}
+			},
+		}, nil
+	case AggregateCountDistinct:
+		return &aggregator{
+			field: &Field{Name: name, DataType: FieldTypeInt},
+			new: func(mutator *Mutator) accumulator {
+				return &distinctAccumulator{mutator: mutator, index: index, values: map[interface{}]bool{}}
+			},
+		}, nil
+	case AggregateSum, AggregateAvg:
+		read, readInt := numberReader(accessor)
+		if read == nil {
+			return nil, errors.Errorf("unsupported %v aggregate field %v type: %v", aggregate.Function, source.Name, accessor.Type)
+		}
+		dataType := FieldTypeFloat64
+		isInt := readInt != nil
+		if isInt && aggregate.Function == AggregateSum {
+			dataType = FieldTypeInt64
+		}
+		average := aggregate.Function == AggregateAvg
+		return &aggregator{
+			field: &Field{Name: name, DataType: dataType},
+			new: func(mutator *Mutator) accumulator {
+				return &sumAccumulator{mutator: mutator, isNull: isNull, read: read, readInt: readInt, isInt: isInt && !average, average: average}
+			},
+		}, nil
+	case AggregateMin, AggregateMax:
+		comparator := keyComparator(SortKey{Name: source.Name, Descending: aggregate.Function == AggregateMax}, source, accessor)
+		return &aggregator{
+			field: sameType(),
+			new: func(mutator *Mutator) accumulator {
+				return &selectAccumulator{mutator: mutator, accessor: accessor, isNull: isNull, accept: func(candidate, selected *Object) bool {
+					return comparator(candidate, selected) < 0
+				}}
+			},
+		}, nil
+	case AggregateFirst, AggregateLast:
+		last := aggregate.Function == AggregateLast
+		return &aggregator{
+			field: sameType(),
+			new: func(mutator *Mutator) accumulator {
+				return &selectAccumulator{mutator: mutator, accessor: accessor, isNull: isNull, accept: func(candidate, selected *Object) bool {
+					return last
+				}}
+			},
+		}, nil
+	case AggregateCollect:
+		componentType := source.DataType
+		if source.DataType == FieldTypeArray {
+			componentType = ""
+		}
+		sliceType := reflect.SliceOf(accessor.Type)
+		return &aggregator{
+			field: &Field{Name: name, DataType: FieldTypeArray, ComponentType: componentType, Type: sliceType},
+			new: func(mutator *Mutator) accumulator {
+				return &collectAccumulator{mutator: mutator, accessor: accessor, isNull: isNull, values: reflect.MakeSlice(sliceType, 0, 1)}
+			},
+		}, nil
+	}
+	return nil, errors.Errorf("unsupported aggregate function: %v", aggregate.Function)
+}
+
+//numberReader returns typed numeric field readers, int reader is nil for non integer field
+func numberReader(accessor *Accessor) (func(object *Object) float64, func(object *Object) int64) {
+	switch accessor.Type {
+	case typeInt:
+		return func(object *Object) float64 { return float64(accessor.Int(object)) },
+			func(object *Object) int64 { return int64(accessor.Int(object)) }
+	case typeInt64:
+		return func(object *Object) float64 { return float64(accessor.Int64(object)) },
+			func(object *Object) int64 { return accessor.Int64(object) }
+	case typeFloat:
+		return func(object *Object) float64 { return float64(accessor.Float32(object)) }, nil
+	case typeFloat64:
+		return func(object *Object) float64 { return accessor.Float64(object) }, nil
+	}
+	return nil, nil
+}
+
+type countAccumulator struct {
+	mutator *Mutator
+	isNull  func(object *Object) bool
+	count   int
+}
+
+func (a *countAccumulator) add(object *Object) {
+	if !a.isNull(object) {
+		a.count++
+	}
+}
+
+func (a *countAccumulator) set(target *Object) {
+	a.mutator.Int(target, a.count)
+}
+
+type distinctAccumulator struct {
+	mutator *Mutator
+	index   int
+	values  map[interface{}]bool
+}
+
+func (a *distinctAccumulator) add(object *Object) {
+	value := fieldValue(object, a.index)
+	if value == nil {
+		return
+	}
+	if !reflect.TypeOf(value).Comparable() {
+		value = fmt.Sprintf("%v", value)
+	}
+	a.values[value] = true
+}
+
+func (a *distinctAccumulator) set(target *Object) {
+	a.mutator.Int(target, len(a.values))
+}
+
+type sumAccumulator struct {
+	mutator *Mutator
+	isNull  func(object *Object) bool
+	read    func(object *Object) float64
+	readInt func(object *Object) int64
+	isInt   bool
+	average bool
+	sum     float64
+	intSum  int64
+	count   int
+}
+
+func (a *sumAccumulator) add(object *Object) {
+	if a.isNull(object) {
+		return
+	}
+	a.count++
+	if a.isInt {
+		a.intSum += a.readInt(object)
+		return
+	}
+	a.sum += a.read(object)
+}
+
+func (a *sumAccumulator) set(target *Object) {
+	switch {
+	case a.count == 0:
+	case a.isInt:
+		a.mutator.Int64(target, a.intSum)
+	case a.average:
+		a.mutator.Float64(target, a.sum/float64(a.count))
+	default:
+		a.mutator.Float64(target, a.sum)
+	}
+}
+
+//selectAccumulator selects a single group object, accept returns true if candidate replaces selected object
+type selectAccumulator struct {
+	mutator  *Mutator
+	accessor *Accessor
+	isNull   func(object *Object) bool
+	accept   func(candidate, selected *Object) bool
+	selected *Object
+}
+
+func (a *selectAccumulator) add(object *Object) {
+	if a.isNull(object) {
+		return
+	}
+	if a.selected == nil || a.accept(object, a.selected) {
+		a.selected = object
+	}
+}
+
+func (a *selectAccumulator) set(target *Object) {
+	if a.selected != nil {
+		a.mutator.SetValue(target, a.accessor.Value(a.selected))
+	}
+}
+
+type collectAccumulator struct {
+	mutator  *Mutator
+	accessor *Accessor
+	isNull   func(object *Object) bool
+	values   reflect.Value
+}
+
+func (a *collectAccumulator) add(object *Object) {
+	if !a.isNull(object) {
+		a.values = reflect.Append(a.values, reflect.ValueOf(a.accessor.Value(object)))
+	}
+}
+
+func (a *collectAccumulator) set(target *Object) {
+	a.mutator.SetValue(target, a.values.Interface())
+}
@@ -0,0 +1,155 @@
+package gtly_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"testing"
+	"time"
+)
+
+func TestGroupBy(t *testing.T) {
+	provider, err := gtly.NewProvider("sale",
+		gtly.NewField("region", gtly.FieldTypeString),
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("qty", gtly.FieldTypeInt),
+		gtly.NewField("amount", gtly.FieldTypeFloat64),
+		gtly.NewField("sold", gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02")),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	day := func(d int) time.Time {
+		return time.Date(2021, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	array := provider.NewArray()
+	for _, values := range []map[string]interface{}{
+		{"region": "north", "city": "Gdansk", "qty": 2, "amount": 10.0, "sold": day(3)},
+		{"region": "south", "city": "Cracow", "qty": 1, "amount": 5.5, "sold": day(1)},
+		{"region": "north", "city": "Gdansk", "qty": 3, "sold": day(2)},
+		{"region": "north", "city": "Olsztyn", "qty": 4, "amount": 2.0, "sold": day(5)},
+	} {
+		assert.Nil(t, array.Add(values))
+	}
+
+	testCases := []struct {
+		description string
+		keys        []string
+		aggregates  []gtly.Aggregate
+		expectTypes map[string]string
+		expect      []map[string]interface{}
+		expectError string
+	}{
+		{
+			description: "single key",
+			keys:        []string{"region"},
+			aggregates: []gtly.Aggregate{
+				gtly.Count(), gtly.Sum("qty"), gtly.Sum("amount").As("total"), gtly.Avg("amount"),
+				gtly.CountDistinct("city"), gtly.Min("sold"), gtly.Max("sold"), {Function: gtly.AggregateCount, Field: "amount", Alias: "amounts"},
+			},
+			expectTypes: map[string]string{
+				"region": gtly.FieldTypeString, "count": gtly.FieldTypeInt, "sumQty": gtly.FieldTypeInt64, "total": gtly.FieldTypeFloat64,
+				"avgAmount": gtly.FieldTypeFloat64, "countDistinctCity": gtly.FieldTypeInt, "minSold": gtly.FieldTypeTime,
+			},
+			expect: []map[string]interface{}{
+				{"region": "north", "count": 3, "sumQty": int64(9), "total": 12.0, "avgAmount": 6.0, "countDistinctCity": 2, "minSold": day(2), "maxSold": day(5), "amounts": 2},
+				{"region": "south", "count": 1, "sumQty": int64(1), "total": 5.5, "avgAmount": 5.5, "countDistinctCity": 1, "minSold": day(1), "maxSold": day(1), "amounts": 1},
+			},
+		},
+		{
+			description: "composite key",
+			keys:        []string{"region", "city"},
+			aggregates:  []gtly.Aggregate{gtly.First("qty"), gtly.Last("qty"), gtly.Collect("amount")},
+			expectTypes: map[string]string{"firstQty": gtly.FieldTypeInt, "collectAmount": gtly.FieldTypeArray},
+			expect: []map[string]interface{}{
+				{"region": "north", "city": "Gdansk", "firstQty": 2, "lastQty": 3, "collectAmount": []float64{10.0}},
+				{"region": "south", "city": "Cracow", "firstQty": 1, "lastQty": 1, "collectAmount": []float64{5.5}},
+				{"region": "north", "city": "Olsztyn", "firstQty": 4, "lastQty": 4, "collectAmount": []float64{2.0}},
+			},
+		},
+		{
+			description: "unknown key",
+			keys:        []string{"country"},
+			expectError: "unknown field: country",
+		},
+		{
+			description: "unknown aggregate field",
+			keys:        []string{"region"},
+			aggregates:  []gtly.Aggregate{gtly.Sum("price")},
+			expectError: "invalid sum aggregate: unknown field: price",
+		},
+		{
+			description: "non numeric sum",
+			keys:        []string{"region"},
+			aggregates:  []gtly.Aggregate{gtly.Avg("city")},
+			expectError: "unsupported avg aggregate field city type: string",
+		},
+		{
+			description: "duplicate output",
+			keys:        []string{"region"},
+			aggregates:  []gtly.Aggregate{gtly.First("city").As("region")},
+			expectError: "duplicate group field: region",
+		},
+	}
+
+	for _, testCase := range testCases {
+		result, err := gtly.GroupBy(array, testCase.keys, testCase.aggregates...)
+		if testCase.expectError != "" {
+			if assert.NotNil(t, err, testCase.description) {
+				assert.Equal(t, testCase.expectError, err.Error(), testCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		for name, dataType := range testCase.expectTypes {
+			if field := result.Proto().Lookup(name); assert.NotNil(t, field, testCase.description+" "+name) {
+				assert.Equal(t, dataType, field.DataType, testCase.description+" "+name)
+			}
+		}
+		if !assert.Equal(t, len(testCase.expect), result.Size(), testCase.description) {
+			continue
+		}
+		i := 0
+		_ = result.Objects(func(item *gtly.Object) (bool, error) {
+			assert.EqualValues(t, testCase.expect[i], item.AsMap(), testCase.description)
+			i++
+			return true, nil
+		})
+	}
+}
+
+func TestGroupBy_CompositeKey(t *testing.T) {
+	provider, err := gtly.NewProvider("path",
+		gtly.NewField("dir", gtly.FieldTypeString),
+		gtly.NewField("name", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	array := provider.NewArray()
+	for _, values := range []map[string]interface{}{
+		{"dir": "x/y", "name": "z"},
+		{"dir": "x", "name": "y/z"},
+		{"dir": "x/y", "name": "z"},
+		{"name": "a"},
+		{"dir": "", "name": "a"},
+	} {
+		assert.Nil(t, array.Add(values))
+	}
+	result, err := gtly.GroupBy(array, []string{"dir", "name"}, gtly.Count())
+	if !assert.Nil(t, err) {
+		return
+	}
+	var actual []map[string]interface{}
+	_ = result.Objects(func(item *gtly.Object) (bool, error) {
+		actual = append(actual, item.AsMap())
+		return true, nil
+	})
+	assert.EqualValues(t, []map[string]interface{}{
+		{"dir": "x/y", "name": "z", "count": 2},
+		{"dir": "x", "name": "y/z", "count": 1},
+		{"name": "a", "count": 1},
+		{"dir": "", "name": "a", "count": 1},
+	}, actual)
+}
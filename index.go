@@ -2,6 +2,7 @@ package gtly
 
 import (
 	"github.com/viant/toolbox"
+	"reflect"
 	"strings"
 )
 
@@ -55,3 +56,52 @@ func indexFromMap(keys []string, values map[string]interface{}) []string {
 	}
 	return result
 }
+
+//objectIndex returns object key usable as a map key
+type objectIndex func(object *Object) interface{}
+
+//keyValue represents an object key element, null value is distinguished from an empty text
+type keyValue struct {
+	text string
+	null bool
+}
+
+//newObjectIndex returns an object index for supplied keys, key values are read with precompiled accessors.
+//Composite key is a fixed size array of key values, so key values are never joined with a separator
+func newObjectIndex(proto *Proto, keys []string) (objectIndex, error) {
+	readers := make([]func(object *Object) keyValue, len(keys))
+	for i, key := range keys {
+		field, accessor, err := lookupAccessor(proto, key)
+		if err != nil {
+			return nil, err
+		}
+		index := field.Index
+		if accessor.Type == typeString {
+			readers[i] = func(object *Object) keyValue {
+				return keyValue{text: accessor.String(object), null: !object.SetAt(index)}
+			}
+			continue
+		}
+		readers[i] = func(object *Object) keyValue {
+			value := fieldValue(object, index)
+			if value == nil {
+				return keyValue{null: true}
+			}
+			return keyValue{text: toolbox.AsString(value)}
+		}
+	}
+	if len(readers) == 1 {
+		reader := readers[0]
+		return func(object *Object) interface{} {
+			return reader(object)
+		}, nil
+	}
+	keyType := reflect.ArrayOf(len(readers), reflect.TypeOf(keyValue{}))
+	return func(object *Object) interface{} {
+		result := reflect.New(keyType).Elem()
+		for i, reader := range readers {
+			result.Index(i).Set(reflect.ValueOf(reader(object)))
+		}
+		return result.Interface()
+	}, nil
+}
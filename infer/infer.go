@@ -0,0 +1,215 @@
+//Package infer provides schema inference from sample records
+package infer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"github.com/viant/gtly/internal/naming"
+	"io"
+	"reflect"
+	"strings"
+)
+
+const byteOrderMark = "\ufeff"
+
+var typeInterfaces = reflect.TypeOf([]interface{}{})
+
+//Inferrer represents schema inferrer, it widens field types across sample records
+type Inferrer struct {
+	name    string
+	config  *config
+	fields  *fields
+	samples int
+}
+
+//Samples returns number of inspected samples
+func (i *Inferrer) Samples() int {
+	return i.samples
+}
+
+func (i *Inferrer) isFull() bool {
+	return i.config.sampleLimit > 0 && i.samples >= i.config.sampleLimit
+}
+
+func (i *Inferrer) add(values *record, text bool) {
+	if i.isFull() {
+		return
+	}
+	i.samples++
+	i.fields.observeObject(values, i.config, text)
+}
+
+//AddMap adds map sample record
+func (i *Inferrer) AddMap(values map[string]interface{}) {
+	i.add(newRecord(values), false)
+}
+
+//AddJSON adds JSON object or JSON array of objects samples
+func (i *Inferrer) AddJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeJSON(decoder)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode JSON sample")
+	}
+	switch actual := value.(type) {
+	case *record:
+		i.add(actual, false)
+	case []interface{}:
+		for j, item := range actual {
+			values, ok := item.(*record)
+			if !ok {
+				return errors.Errorf("expected JSON object at %v, but had: %T", j, item)
+			}
+			i.add(values, false)
+		}
+	default:
+		return errors.Errorf("expected JSON object or array, but had: %T", value)
+	}
+	return nil
+}
+
+//AddJSONLines adds new line delimited JSON object samples
+func (i *Inferrer) AddJSONLines(reader io.Reader) error {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	for line := 1; !i.isFull(); line++ {
+		value, err := decodeJSON(decoder)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode JSON sample %v", line)
+		}
+		values, ok := value.(*record)
+		if !ok {
+			return errors.Errorf("expected JSON object at sample %v, but had: %T", line, value)
+		}
+		i.add(values, false)
+	}
+	return nil
+}
+
+//AddCSV adds CSV rows samples, the first row is used as header, cells are detected as bool, number, time or text
+func (i *Inferrer) AddCSV(reader io.Reader) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = i.config.delimiter
+	csvReader.FieldsPerRecord = -1
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to read CSV header")
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], byteOrderMark)
+	}
+	for row := 2; !i.isFull(); row++ {
+		cells, err := csvReader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read CSV row %v", row)
+		}
+		values := &record{values: map[string]interface{}{}}
+		for j, name := range header {
+			if j < len(cells) {
+				values.add(name, cells[j])
+			}
+		}
+		i.add(values, true)
+	}
+	return nil
+}
+
+//Provider returns a provider with inferred fields and inference report
+func (i *Inferrer) Provider() (*gtly.Provider, *Report, error) {
+	report := &Report{Samples: i.samples}
+	provider, err := i.newProvider(i.name, "", i.fields, report)
+	return provider, report, err
+}
+
+func (i *Inferrer) newProvider(name, path string, fields *fields, report *Report) (*gtly.Provider, error) {
+	var result = make([]*gtly.Field, 0, len(fields.order))
+	var names = map[string]bool{}
+	for _, aNode := range fields.order {
+		location := aNode.name
+		if path != "" {
+			location = path + "." + aNode.name
+		}
+		field, err := i.newField(aNode, location, report)
+		if err != nil {
+			return nil, err
+		}
+		field.Name = naming.FieldName(aNode.name, names)
+		if field.Name != aNode.name {
+			field.InputName = aNode.name
+			gtly.OutputNameOpt(aNode.name)(field)
+			report.rename(location, field.Name)
+		}
+		if aNode.present < fields.objects {
+			report.Optional = append(report.Optional, location)
+		}
+		result = append(result, field)
+	}
+	return gtly.NewProvider(name, result...)
+}
+
+func (i *Inferrer) newField(aNode *node, location string, report *Report) (*gtly.Field, error) {
+	if aNode.conflict() {
+		report.Conflicts = append(report.Conflicts, &Conflict{Field: location, Types: aNode.observed, Resolved: kindNames[aNode.kind]})
+	}
+	switch aNode.kind {
+	case kindObject:
+		provider, err := i.newProvider(aNode.name, location, aNode.fields, report)
+		if err != nil {
+			return nil, err
+		}
+		return gtly.NewField(aNode.name, gtly.FieldTypeObject, gtly.ProviderOpt(provider)), nil
+	case kindArray:
+		return i.newArrayField(aNode, location, report)
+	case kindTime:
+		return gtly.NewField(aNode.name, gtly.FieldTypeTime, gtly.DateLayoutOpt(aNode.layout)), nil
+	case kindNull:
+		return gtly.NewField(aNode.name, gtly.FieldTypeString), nil
+	}
+	return gtly.NewField(aNode.name, kindNames[aNode.kind]), nil
+}
+
+func (i *Inferrer) newArrayField(aNode *node, location string, report *Report) (*gtly.Field, error) {
+	item := aNode.item
+	if item.conflict() {
+		report.Conflicts = append(report.Conflicts, &Conflict{Field: location + "[]", Types: item.observed, Resolved: kindNames[item.kind]})
+	}
+	switch item.kind {
+	case kindObject:
+		provider, err := i.newProvider(aNode.name, location+"[]", item.fields, report)
+		if err != nil {
+			return nil, err
+		}
+		return gtly.NewField(aNode.name, gtly.FieldTypeArray, gtly.ProviderOpt(provider)), nil
+	case kindArray:
+		field := gtly.NewField(aNode.name, gtly.FieldTypeArray)
+		field.Type = typeInterfaces
+		return field, nil
+	case kindTime:
+		return gtly.NewField(aNode.name, gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeTime), gtly.DateLayoutOpt(item.layout)), nil
+	case kindNull:
+		return gtly.NewField(aNode.name, gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeString)), nil
+	}
+	return gtly.NewField(aNode.name, gtly.FieldTypeArray, gtly.ComponentTypeOpt(kindNames[item.kind])), nil
+}
+
+//NewInferrer creates a schema inferrer for supplied provider name
+func NewInferrer(name string, options ...Option) *Inferrer {
+	config := &config{timeLayouts: defaultTimeLayouts, delimiter: ','}
+	for _, option := range options {
+		option(config)
+	}
+	return &Inferrer{name: name, config: config, fields: newFields()}
+}
@@ -0,0 +1,198 @@
+package infer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInferrer_Provider(t *testing.T) {
+	testCases := []struct {
+		description     string
+		jsonLines       string
+		csv             string
+		maps            []map[string]interface{}
+		options         []Option
+		expectFields    []string
+		expectTypes     map[string]reflect.Type
+		expectLayouts   map[string]string
+		expectOptional  []string
+		expectRenamed   map[string]string
+		expectConflicts []*Conflict
+		expectSamples   int
+	}{
+		{
+			description:   "json lines numeric widening",
+			jsonLines:     `{"id":1,"qty":2,"price":3}` + "\n" + `{"id":2,"qty":3000000000,"price":1.5}`,
+			expectFields:  []string{"id:int", "qty:int64", "price:float64"},
+			expectSamples: 2,
+		},
+		{
+			description:     "json lines conflicts and optional fields",
+			jsonLines:       `{"id":1,"code":10,"active":true}{"id":2,"code":"A1","note":null}`,
+			expectFields:    []string{"id:int", "code:string", "active:bool", "note:string"},
+			expectOptional:  []string{"active", "note"},
+			expectConflicts: []*Conflict{{Field: "code", Types: []string{"int", "string"}, Resolved: "string"}},
+			expectSamples:   2,
+		},
+		{
+			description:   "json lines time detection",
+			jsonLines:     `{"created":"2021-03-04T10:11:12Z","day":"2021-03-04","name":"2021"}`,
+			expectFields:  []string{"created:time", "day:time", "name:string"},
+			expectLayouts: map[string]string{"created": time.RFC3339, "day": "2006-01-02"},
+			expectSamples: 1,
+		},
+		{
+			description:     "json lines mixed time layouts",
+			jsonLines:       `{"created":"2021-03-04T10:11:12Z"}{"created":"2021-03-04"}`,
+			expectFields:    []string{"created:string"},
+			expectConflicts: []*Conflict{{Field: "created", Types: []string{"time(" + time.RFC3339 + ")", "time(2006-01-02)"}, Resolved: "string"}},
+			expectSamples:   2,
+		},
+		{
+			description:    "json lines nested object and arrays",
+			jsonLines:      `{"id":1,"address":{"city":"Foo","zip":1},"tags":["a","b"],"items":[{"sku":"x","qty":1},{"sku":"y","qty":2.5}]}` + "\n" + `{"id":2,"address":{"city":"Bar"},"tags":[],"items":[]}`,
+			expectFields:   []string{"id:int", "address:object", "tags:array", "items:array"},
+			expectOptional: []string{"address.zip"},
+			expectTypes: map[string]reflect.Type{
+				"tags": reflect.TypeOf([]string{}),
+				"address": reflect.StructOf([]reflect.StructField{
+					{Name: "city", PkgPath: "github.com/viant/gtly", Type: reflect.TypeOf("")},
+					{Name: "zip", PkgPath: "github.com/viant/gtly", Type: reflect.TypeOf(0)},
+				}),
+				"items": reflect.StructOf([]reflect.StructField{
+					{Name: "sku", PkgPath: "github.com/viant/gtly", Type: reflect.TypeOf("")},
+					{Name: "qty", PkgPath: "github.com/viant/gtly", Type: reflect.TypeOf(0.0)},
+				}),
+			},
+			expectSamples: 2,
+		},
+		{
+			description:   "json lines sample limit",
+			jsonLines:     `{"id":1}{"id":"x"}`,
+			options:       []Option{SampleLimitOpt(1)},
+			expectFields:  []string{"id:int"},
+			expectSamples: 1,
+		},
+		{
+			description:    "csv text detection",
+			csv:            "id,name,active,score,created,first name\n1,Foo,true,1.5,2021-03-04,A\n2,Bar,false,,2021-03-05,B\n",
+			expectFields:   []string{"id:int", "name:string", "active:bool", "score:float64", "created:time", "first_name:string"},
+			expectOptional: []string{"score"},
+			expectRenamed:  map[string]string{"first name": "first_name"},
+			expectSamples:  2,
+		},
+		{
+			description:   "csv with custom delimiter and time layout",
+			csv:           "id;day\n1;04/03/2021\n2;05/03/2021\n",
+			options:       []Option{DelimiterOpt(';'), TimeLayoutsOpt("02/01/2006")},
+			expectFields:  []string{"id:int", "day:time"},
+			expectLayouts: map[string]string{"day": "02/01/2006"},
+			expectSamples: 2,
+		},
+		{
+			description: "maps",
+			maps: []map[string]interface{}{
+				{"id": 1, "ts": time.Now(), "ratio": float32(0.5)},
+				{"id": int64(2), "ts": nil},
+			},
+			expectFields:   []string{"id:int64", "ratio:float64", "ts:time"},
+			expectOptional: []string{"ratio", "ts"},
+			expectSamples:  2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		inferrer := NewInferrer("test", testCase.options...)
+		if testCase.jsonLines != "" {
+			if !assert.Nil(t, inferrer.AddJSONLines(strings.NewReader(testCase.jsonLines)), testCase.description) {
+				continue
+			}
+		}
+		if testCase.csv != "" {
+			if !assert.Nil(t, inferrer.AddCSV(strings.NewReader(testCase.csv)), testCase.description) {
+				continue
+			}
+		}
+		for _, values := range testCase.maps {
+			inferrer.AddMap(values)
+		}
+		provider, report, err := inferrer.Provider()
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var actual []string
+		for _, field := range provider.Fields() {
+			actual = append(actual, field.Name+":"+field.DataType)
+		}
+		assert.EqualValues(t, testCase.expectFields, actual, testCase.description)
+		for name, expect := range testCase.expectTypes {
+			actualType := provider.Field(name).Type
+			if itemProvider := provider.Field(name).ItemProvider(); itemProvider != nil {
+				actualType = itemProvider.Type()
+			}
+			assert.EqualValues(t, expect.String(), actualType.String(), testCase.description+" "+name)
+		}
+		for name, expect := range testCase.expectLayouts {
+			assert.EqualValues(t, expect, provider.Field(name).DataLayout, testCase.description+" "+name)
+		}
+		assert.EqualValues(t, testCase.expectSamples, report.Samples, testCase.description)
+		assert.EqualValues(t, testCase.expectOptional, report.Optional, testCase.description)
+		assert.EqualValues(t, testCase.expectRenamed, report.Renamed, testCase.description)
+		for input, name := range testCase.expectRenamed {
+			assert.EqualValues(t, input, provider.Field(name).OutputName(), testCase.description+" "+name)
+		}
+		assert.EqualValues(t, testCase.expectConflicts, report.Conflicts, testCase.description)
+	}
+}
+
+func TestInferrer_AddJSON(t *testing.T) {
+	testCases := []struct {
+		description  string
+		input        string
+		expectFields []string
+		expectError  bool
+	}{
+		{
+			description:  "json object",
+			input:        `{"id":1,"name":"Foo"}`,
+			expectFields: []string{"id", "name"},
+		},
+		{
+			description:  "json array of objects",
+			input:        `[{"id":1},{"name":"Foo"}]`,
+			expectFields: []string{"id", "name"},
+		},
+		{
+			description: "json scalar",
+			input:       `1`,
+			expectError: true,
+		},
+		{
+			description: "invalid json",
+			input:       `{"id":`,
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		inferrer := NewInferrer("test")
+		err := inferrer.AddJSON([]byte(testCase.input))
+		if testCase.expectError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		provider, _, err := inferrer.Provider()
+		assert.Nil(t, err, testCase.description)
+		var actual []string
+		for _, field := range provider.Fields() {
+			actual = append(actual, field.Name)
+		}
+		assert.EqualValues(t, testCase.expectFields, actual, testCase.description)
+	}
+}
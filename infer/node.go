@@ -0,0 +1,190 @@
+package infer
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type kind int
+
+//kinds are ordered, so that numeric kinds widen to the greater one
+const (
+	kindNull = kind(iota)
+	kindBool
+	kindInt
+	kindInt64
+	kindFloat64
+	kindTime
+	kindString
+	kindObject
+	kindArray
+)
+
+var kindNames = []string{"null", "bool", "int", "int64", "float64", "time", "string", "object", "array"}
+
+func (k kind) isNumeric() bool {
+	return k == kindInt || k == kindInt64 || k == kindFloat64
+}
+
+//node represents inferred field
+type node struct {
+	name     string
+	kind     kind
+	layout   string
+	observed []string
+	present  int
+	nulls    int
+	fields   *fields
+	item     *node
+}
+
+//fields represents object fields in the first seen order
+type fields struct {
+	objects int
+	order   []*node
+	byName  map[string]*node
+}
+
+func newFields() *fields {
+	return &fields{byName: map[string]*node{}}
+}
+
+func (f *fields) node(name string) *node {
+	result, ok := f.byName[name]
+	if !ok {
+		result = &node{name: name}
+		f.byName[name] = result
+		f.order = append(f.order, result)
+	}
+	return result
+}
+
+//observeObject merges record values into fields, absent fields are reported as optional
+func (f *fields) observeObject(values *record, config *config, text bool) {
+	f.objects++
+	for _, key := range values.keys {
+		f.node(key).observe(values.values[key], config, text)
+	}
+}
+
+//observe merges value into node, text values are detected as bool, number, time or string
+func (n *node) observe(value interface{}, config *config, text bool) {
+	switch actual := value.(type) {
+	case nil:
+		n.nulls++
+		return
+	case bool:
+		n.merge(kindBool, "")
+	case json.Number:
+		n.merge(numberKind(string(actual)), "")
+	case int, int8, int16, int32, uint8, uint16:
+		n.merge(kindInt, "")
+	case int64, uint, uint32, uint64:
+		n.merge(kindInt64, "")
+	case float32, float64:
+		n.merge(kindFloat64, "")
+	case time.Time, *time.Time:
+		n.merge(kindTime, time.RFC3339)
+	case string:
+		if text && strings.TrimSpace(actual) == "" {
+			n.nulls++
+			return
+		}
+		n.merge(textKind(actual, config, text))
+	case map[string]interface{}:
+		n.observe(newRecord(actual), config, text)
+		return
+	case *record:
+		n.merge(kindObject, "")
+		if n.fields == nil {
+			n.fields = newFields()
+		}
+		n.fields.observeObject(actual, config, text)
+	case []interface{}:
+		n.merge(kindArray, "")
+		if n.item == nil {
+			n.item = &node{}
+		}
+		for _, item := range actual {
+			n.item.observe(item, config, text)
+		}
+	default:
+		n.merge(kindString, "")
+	}
+	n.present++
+}
+
+//merge widens node kind: int -> int64 -> float64, other mismatches widen to string
+func (n *node) merge(k kind, layout string) {
+	name := kindNames[k]
+	if k == kindTime {
+		name += "(" + layout + ")"
+	}
+	seen := false
+	for _, observed := range n.observed {
+		if observed == name {
+			seen = true
+			break
+		}
+	}
+	if !seen {
+		n.observed = append(n.observed, name)
+	}
+	switch {
+	case n.kind == kindNull:
+		n.kind, n.layout = k, layout
+	case n.kind == k && n.layout == layout:
+	case n.kind.isNumeric() && k.isNumeric():
+		if k > n.kind {
+			n.kind = k
+		}
+	default:
+		n.kind, n.layout = kindString, ""
+	}
+}
+
+//conflict returns true if node widened observed kinds to string
+func (n *node) conflict() bool {
+	if len(n.observed) < 2 {
+		return false
+	}
+	for _, observed := range n.observed {
+		if observed != kindNames[kindInt] && observed != kindNames[kindInt64] && observed != kindNames[kindFloat64] {
+			return true
+		}
+	}
+	return false
+}
+
+func numberKind(text string) kind {
+	if value, err := strconv.ParseInt(text, 10, 64); err == nil {
+		if value >= math.MinInt32 && value <= math.MaxInt32 {
+			return kindInt
+		}
+		return kindInt64
+	}
+	return kindFloat64
+}
+
+//textKind detects text kind, bool and numbers are detected only in text (CSV) sources
+func textKind(value string, config *config, text bool) (kind, string) {
+	value = strings.TrimSpace(value)
+	if text {
+		switch strings.ToLower(value) {
+		case "true", "false":
+			return kindBool, ""
+		}
+		if _, err := strconv.ParseFloat(value, 64); err == nil && !strings.ContainsAny(value, "xXnN") {
+			return numberKind(value), ""
+		}
+	}
+	for _, layout := range config.timeLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return kindTime, layout
+		}
+	}
+	return kindString, ""
+}
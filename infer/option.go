@@ -0,0 +1,35 @@
+package infer
+
+import "time"
+
+var defaultTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+//Option represents inferrer option
+type Option func(c *config)
+
+type config struct {
+	timeLayouts []string
+	sampleLimit int
+	delimiter   rune
+}
+
+//TimeLayoutsOpt returns time layouts option, text values matching any layout are inferred as time
+func TimeLayoutsOpt(layouts ...string) Option {
+	return func(c *config) {
+		c.timeLayouts = layouts
+	}
+}
+
+//SampleLimitOpt returns sample limit option, samples above the limit are ignored
+func SampleLimitOpt(limit int) Option {
+	return func(c *config) {
+		c.sampleLimit = limit
+	}
+}
+
+//DelimiterOpt returns CSV delimiter option
+func DelimiterOpt(delimiter rune) Option {
+	return func(c *config) {
+		c.delimiter = delimiter
+	}
+}
@@ -0,0 +1,71 @@
+package infer
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"sort"
+)
+
+//record represents sample object with keys in the source order
+type record struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (r *record) add(key string, value interface{}) {
+	if _, ok := r.values[key]; !ok {
+		r.keys = append(r.keys, key)
+	}
+	r.values[key] = value
+}
+
+//newRecord creates a record from a map, map keys are sorted to keep field order stable
+func newRecord(values map[string]interface{}) *record {
+	result := &record{keys: make([]string, 0, len(values)), values: values}
+	for key := range values {
+		result.keys = append(result.keys, key)
+	}
+	sort.Strings(result.keys)
+	return result
+}
+
+//decodeJSON decodes the next JSON value, objects are decoded as records and numbers as json.Number
+func decodeJSON(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+	switch delim {
+	case '{':
+		result := &record{values: map[string]interface{}{}}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			result.add(key.(string), value)
+		}
+		_, err = decoder.Token()
+		return result, err
+	case '[':
+		var result = make([]interface{}, 0)
+		for decoder.More() {
+			value, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		}
+		_, err = decoder.Token()
+		return result, err
+	}
+	return nil, errors.Errorf("unexpected JSON delimiter: %v", delim)
+}
@@ -0,0 +1,28 @@
+package infer
+
+//Conflict represents field which observed types could not be widened without loss, the field is resolved as string
+type Conflict struct {
+	Field    string
+	Types    []string
+	Resolved string
+}
+
+//Report represents inference report
+type Report struct {
+	Samples   int
+	Optional  []string
+	Renamed   map[string]string
+	Conflicts []*Conflict
+}
+
+//HasConflicts returns true if any field had conflicting types
+func (r *Report) HasConflicts() bool {
+	return len(r.Conflicts) > 0
+}
+
+func (r *Report) rename(field, name string) {
+	if r.Renamed == nil {
+		r.Renamed = map[string]string{}
+	}
+	r.Renamed[field] = name
+}
@@ -0,0 +1,70 @@
+//Package compare provides value comparison and pattern matching shared by predicates, sorting and expressions
+package compare
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+//Int returns -1, 0 or 1 if x is less, equal or greater than y
+func Int(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+//Float returns -1, 0 or 1 if x is less, equal or greater than y
+func Float(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+//Bool returns -1, 0 or 1 if x is less, equal or greater than y, false is less than true
+func Bool(x, y bool) int {
+	switch {
+	case x == y:
+		return 0
+	case y:
+		return -1
+	}
+	return 1
+}
+
+//Time returns -1, 0 or 1 if x is before, equal or after y
+func Time(x, y time.Time) int {
+	switch {
+	case x.Before(y):
+		return -1
+	case x.After(y):
+		return 1
+	}
+	return 0
+}
+
+//Like converts SQL like pattern to regular expression, % matches any sequence, _ matches a single character
+func Like(pattern string) (*regexp.Regexp, error) {
+	expr := strings.Builder{}
+	expr.WriteString("(?s)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			expr.WriteString(".*")
+		case '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
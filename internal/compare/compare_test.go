@@ -0,0 +1,29 @@
+package compare
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLike(t *testing.T) {
+	var testCases = []struct {
+		description string
+		pattern     string
+		text        string
+		expect      bool
+	}{
+		{description: "any sequence", pattern: "ab%", text: "abcd", expect: true},
+		{description: "single character", pattern: "a_c", text: "abc", expect: true},
+		{description: "single character mismatch", pattern: "a_c", text: "abbc", expect: false},
+		{description: "regexp meta characters", pattern: "a.c%", text: "abc", expect: false},
+		{description: "multiline text", pattern: "a%c", text: "a\nc", expect: true},
+	}
+
+	for _, testCase := range testCases {
+		expr, err := Like(testCase.pattern)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, expr.MatchString(testCase.text), testCase.description)
+	}
+}
@@ -0,0 +1,63 @@
+//Package memory provides typed reads and writes of field memory shared by codecs and expressions
+package memory
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+//ReadInt reads signed or unsigned integer of supplied kind as int64
+func ReadInt(kind reflect.Kind, ptr unsafe.Pointer) int64 {
+	switch kind {
+	case reflect.Int:
+		return int64(*(*int)(ptr))
+	case reflect.Int32:
+		return int64(*(*int32)(ptr))
+	case reflect.Int16:
+		return int64(*(*int16)(ptr))
+	case reflect.Int8:
+		return int64(*(*int8)(ptr))
+	case reflect.Uint:
+		return int64(*(*uint)(ptr))
+	case reflect.Uint64:
+		return int64(*(*uint64)(ptr))
+	case reflect.Uint32:
+		return int64(*(*uint32)(ptr))
+	case reflect.Uint16:
+		return int64(*(*uint16)(ptr))
+	case reflect.Uint8:
+		return int64(*(*uint8)(ptr))
+	}
+	return *(*int64)(ptr)
+}
+
+//WriteInt writes int64 value as signed or unsigned integer of supplied kind
+func WriteInt(kind reflect.Kind, ptr unsafe.Pointer, value int64) {
+	switch kind {
+	case reflect.Int:
+		*(*int)(ptr) = int(value)
+	case reflect.Int32:
+		*(*int32)(ptr) = int32(value)
+	case reflect.Int16:
+		*(*int16)(ptr) = int16(value)
+	case reflect.Int8:
+		*(*int8)(ptr) = int8(value)
+	case reflect.Uint:
+		*(*uint)(ptr) = uint(value)
+	case reflect.Uint64:
+		*(*uint64)(ptr) = uint64(value)
+	case reflect.Uint32:
+		*(*uint32)(ptr) = uint32(value)
+	case reflect.Uint16:
+		*(*uint16)(ptr) = uint16(value)
+	case reflect.Uint8:
+		*(*uint8)(ptr) = uint8(value)
+	default:
+		*(*int64)(ptr) = value
+	}
+}
+
+//Slice returns slice value stored at supplied pointer
+func Slice(itemType reflect.Type, ptr unsafe.Pointer) reflect.Value {
+	return reflect.NewAt(reflect.SliceOf(itemType), ptr).Elem()
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func TestWriteInt(t *testing.T) {
+	var testCases = []struct {
+		description string
+		value       interface{}
+		input       int64
+		expect      int64
+	}{
+		{description: "int", value: new(int), input: -3, expect: -3},
+		{description: "int8", value: new(int8), input: -3, expect: -3},
+		{description: "int16", value: new(int16), input: 300, expect: 300},
+		{description: "int32", value: new(int32), input: -70000, expect: -70000},
+		{description: "int64", value: new(int64), input: 1 << 40, expect: 1 << 40},
+		{description: "uint", value: new(uint), input: 3, expect: 3},
+		{description: "uint8", value: new(uint8), input: 256 + 5, expect: 5},
+		{description: "uint16", value: new(uint16), input: 65535, expect: 65535},
+		{description: "uint32", value: new(uint32), input: 1 << 31, expect: 1 << 31},
+		{description: "uint64", value: new(uint64), input: 1 << 40, expect: 1 << 40},
+	}
+
+	for _, testCase := range testCases {
+		value := reflect.ValueOf(testCase.value)
+		kind := value.Elem().Kind()
+		ptr := unsafe.Pointer(value.Pointer())
+		WriteInt(kind, ptr, testCase.input)
+		assert.EqualValues(t, testCase.expect, ReadInt(kind, ptr), testCase.description)
+		assert.EqualValues(t, testCase.expect, value.Elem().Convert(reflect.TypeOf(int64(0))).Int(), testCase.description)
+	}
+}
+
+func TestSlice(t *testing.T) {
+	values := []int32{1, 2, 3}
+	slice := Slice(reflect.TypeOf(int32(0)), unsafe.Pointer(&values))
+	assert.EqualValues(t, 3, slice.Len())
+	assert.EqualValues(t, unsafe.Pointer(&values[0]), unsafe.Pointer(slice.Pointer()))
+}
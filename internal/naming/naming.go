@@ -0,0 +1,29 @@
+//Package naming provides field name conversion shared by schema importers
+package naming
+
+import (
+	"strconv"
+	"unicode"
+)
+
+//FieldName returns unique valid Go identifier for supplied name, invalid characters are replaced with underscore,
+//names not starting with a letter are prefixed with F, and supplied names map is updated with the result
+func FieldName(name string, names map[string]bool) string {
+	var result = make([]rune, 0, len(name))
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			result = append(result, r)
+			continue
+		}
+		result = append(result, '_')
+	}
+	if len(result) == 0 || !unicode.IsLetter(result[0]) {
+		result = append([]rune("F"), result...)
+	}
+	candidate := string(result)
+	for j := 2; names[candidate]; j++ {
+		candidate = string(result) + "_" + strconv.Itoa(j)
+	}
+	names[candidate] = true
+	return candidate
+}
@@ -0,0 +1,27 @@
+package naming
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFieldName(t *testing.T) {
+	names := map[string]bool{}
+	var testCases = []struct {
+		description string
+		name        string
+		expect      string
+	}{
+		{description: "valid identifier", name: "firstName", expect: "firstName"},
+		{description: "space", name: "first name", expect: "first_name"},
+		{description: "dash", name: "user-id", expect: "user_id"},
+		{description: "leading digit", name: "1st", expect: "F1st"},
+		{description: "empty", name: "", expect: "F"},
+		{description: "collision", name: "first-name", expect: "first_name_2"},
+		{description: "second collision", name: "first.name", expect: "first_name_3"},
+	}
+
+	for _, testCase := range testCases {
+		assert.EqualValues(t, testCase.expect, FieldName(testCase.name, names), testCase.description)
+	}
+}
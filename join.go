@@ -0,0 +1,228 @@
+package gtly
+
+import (
+	"github.com/pkg/errors"
+)
+
+//JoinType represents collections join type
+type JoinType string
+
+const (
+	//InnerJoin returns only matched objects
+	InnerJoin = JoinType("inner")
+	//LeftJoin returns all left objects
+	LeftJoin = JoinType("left")
+	//RightJoin returns all right objects
+	RightJoin = JoinType("right")
+	//FullJoin returns all left and right objects
+	FullJoin = JoinType("full")
+)
+
+//JoinOption represents join option
+type JoinOption func(j *join)
+
+type join struct {
+	joinType    JoinType
+	leftKeys    []string
+	rightKeys   []string
+	leftPrefix  string
+	rightPrefix string
+}
+
+//JoinRightKeysOpt returns right collection keys option, right keys default to left keys
+func JoinRightKeysOpt(keys ...string) JoinOption {
+	return func(j *join) {
+		j.rightKeys = keys
+	}
+}
+
+//JoinPrefixOpt returns colliding field name prefixes option, right prefix defaults to right proto simple name followed by underscore
+func JoinPrefixOpt(left, right string) JoinOption {
+	return func(j *join) {
+		j.leftPrefix = left
+		j.rightPrefix = right
+	}
+}
+
+//joinSide represents joined collection side
+type joinSide struct {
+	collection Collection
+	keys       []string
+	keyIndexes []int
+	index      objectIndex
+	offset     int
+	mutators   []*Mutator
+}
+
+func (s *joinSide) init(keys []string) error {
+	proto := s.collection.Proto()
+	s.keys = keys
+	s.keyIndexes = make([]int, len(keys))
+	for i, key := range keys {
+		field, _, err := lookupAccessor(proto, key)
+		if err != nil {
+			return err
+		}
+		s.keyIndexes[i] = field.Index
+	}
+	var err error
+	s.index, err = newObjectIndex(proto, keys)
+	return err
+}
+
+//key returns object join key, false if any key value is null, null keys never match
+func (s *joinSide) key(object *Object) (interface{}, bool) {
+	for _, index := range s.keyIndexes {
+		if fieldValue(object, index) == nil {
+			return nil, false
+		}
+	}
+	return s.index(object), true
+}
+
+//copy copies set object values into joined object
+func (s *joinSide) copy(source, target *Object) {
+	proto := source.Proto()
+	for i, mutator := range s.mutators {
+		if source.SetAt(i) {
+			mutator.SetValue(target, proto.AccessorAt(i).Value(source))
+		}
+	}
+}
+
+//Join joins collections on supplied key fields, result array provider merges left and right fields, colliding field names are prefixed.
+//Right collection is indexed with a multimap, so one left object produces an object for every matching right object.
+func Join(left, right Collection, joinType JoinType, keys []string, options ...JoinOption) (*Array, error) {
+	spec := &join{joinType: joinType, leftKeys: keys, rightPrefix: right.Proto().SimpleName() + "_"}
+	for _, option := range options {
+		option(spec)
+	}
+	switch joinType {
+	case InnerJoin, LeftJoin, RightJoin, FullJoin:
+	default:
+		return nil, errors.Errorf("unsupported join type: %v", joinType)
+	}
+	if len(spec.rightKeys) == 0 {
+		spec.rightKeys = spec.leftKeys
+	}
+	if len(spec.leftKeys) == 0 || len(spec.leftKeys) != len(spec.rightKeys) {
+		return nil, errors.Errorf("invalid join keys: %v, %v", spec.leftKeys, spec.rightKeys)
+	}
+	leftSide, rightSide := &joinSide{collection: left}, &joinSide{collection: right}
+	if err := leftSide.init(spec.leftKeys); err != nil {
+		return nil, errors.Wrap(err, "invalid left join key")
+	}
+	if err := rightSide.init(spec.rightKeys); err != nil {
+		return nil, errors.Wrap(err, "invalid right join key")
+	}
+	provider, err := spec.newProvider(left.Proto(), right.Proto())
+	if err != nil {
+		return nil, err
+	}
+	rightSide.offset = len(left.Proto().Fields())
+	for _, side := range []*joinSide{leftSide, rightSide} {
+		side.mutators = make([]*Mutator, len(side.collection.Proto().Fields()))
+		for i := range side.mutators {
+			side.mutators[i] = provider.MutatorAt(side.offset + i)
+		}
+	}
+	driver, lookup := leftSide, rightSide
+	if joinType == RightJoin {
+		driver, lookup = rightSide, leftSide
+	}
+	lookupMap := (&Provider{Proto: lookup.collection.Proto()}).NewMultimap(func(object *Object) interface{} {
+		key, _ := lookup.key(object)
+		return key
+	})
+	var lookupObjects []*Object
+	err = lookup.collection.Objects(func(item *Object) (bool, error) {
+		lookupObjects = append(lookupObjects, item)
+		if _, ok := lookup.key(item); ok {
+			lookupMap.AddObject(item)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := provider.NewArray()
+	emit := func(driverObject, lookupObject *Object) {
+		target := provider.NewObject()
+		if driverObject != nil {
+			driver.copy(driverObject, target)
+		}
+		if lookupObject != nil {
+			lookup.copy(lookupObject, target)
+		}
+		result.AddObject(target)
+	}
+	matched := map[*Object]bool{}
+	err = driver.collection.Objects(func(item *Object) (bool, error) {
+		var matches []*Object
+		if key, ok := driver.key(item); ok {
+			matches = lookupMap._map[key]
+		}
+		if len(matches) == 0 && joinType != InnerJoin {
+			emit(item, nil)
+		}
+		for _, match := range matches {
+			matched[match] = true
+			emit(item, match)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if joinType == FullJoin {
+		for _, item := range lookupObjects {
+			if !matched[item] {
+				emit(nil, item)
+			}
+		}
+	}
+	return result, nil
+}
+
+//newProvider creates joined provider with left fields followed by right fields
+func (j *join) newProvider(left, right *Proto) (*Provider, error) {
+	leftFields, rightFields := left.Fields(), right.Fields()
+	rightNames := map[string]bool{}
+	for i := range rightFields {
+		rightNames[rightFields[i].Name] = true
+	}
+	collisions := map[string]bool{}
+	for i := range leftFields {
+		if rightNames[leftFields[i].Name] {
+			collisions[leftFields[i].Name] = true
+		}
+	}
+	var fields = make([]*Field, 0, len(leftFields)+len(rightFields))
+	names := map[string]bool{}
+	for _, side := range []struct {
+		proto  *Proto
+		prefix string
+	}{{left, j.leftPrefix}, {right, j.rightPrefix}} {
+		protoFields := side.proto.Fields()
+		for i := range protoFields {
+			source := &protoFields[i]
+			name := source.Name
+			if collisions[name] {
+				name = side.prefix + name
+			}
+			if names[name] {
+				return nil, errors.Errorf("duplicate join field: %v, use join prefix option", name)
+			}
+			names[name] = true
+			fields = append(fields, &Field{
+				Name:          name,
+				Type:          side.proto.AccessorAt(source.Index).Type,
+				DataType:      source.DataType,
+				DataLayout:    source.DataLayout,
+				ComponentType: source.ComponentType,
+				itemProvider:  source.itemProvider,
+			})
+		}
+	}
+	return NewProvider(left.SimpleName()+"_"+right.SimpleName(), fields...)
+}
@@ -0,0 +1,194 @@
+package gtly_test
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	userProvider, err := gtly.NewProvider("user",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	orderProvider, err := gtly.NewProvider("order",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("userId", gtly.FieldTypeInt64),
+		gtly.NewField("amount", gtly.FieldTypeFloat64),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	users := userProvider.NewArray()
+	for _, values := range []map[string]interface{}{
+		{"id": 1, "name": "Adam"},
+		{"id": 2, "name": "Tom"},
+		{"name": "Nobody"},
+	} {
+		assert.Nil(t, users.Add(values))
+	}
+	orders := orderProvider.NewArray()
+	for _, values := range []map[string]interface{}{
+		{"id": 10, "userId": int64(1), "amount": 5.0},
+		{"id": 11, "userId": int64(3), "amount": 7.0},
+		{"id": 12, "userId": int64(1), "amount": 9.0},
+	} {
+		assert.Nil(t, orders.Add(values))
+	}
+
+	testCases := []struct {
+		description  string
+		joinType     gtly.JoinType
+		options      []gtly.JoinOption
+		expectFields []string
+		expect       []map[string]interface{}
+		expectError  string
+	}{
+		{
+			description:  "inner one to many",
+			joinType:     gtly.InnerJoin,
+			expectFields: []string{"id", "name", "order_id", "userId", "amount"},
+			expect: []map[string]interface{}{
+				{"id": 1, "name": "Adam", "order_id": 10, "userId": int64(1), "amount": 5.0},
+				{"id": 1, "name": "Adam", "order_id": 12, "userId": int64(1), "amount": 9.0},
+			},
+		},
+		{
+			description: "left",
+			joinType:    gtly.LeftJoin,
+			expect: []map[string]interface{}{
+				{"id": 1, "name": "Adam", "order_id": 10, "userId": int64(1), "amount": 5.0},
+				{"id": 1, "name": "Adam", "order_id": 12, "userId": int64(1), "amount": 9.0},
+				{"id": 2, "name": "Tom", "order_id": nil, "userId": nil, "amount": nil},
+				{"id": nil, "name": "Nobody", "order_id": nil, "userId": nil, "amount": nil},
+			},
+		},
+		{
+			description:  "right with prefixes",
+			joinType:     gtly.RightJoin,
+			options:      []gtly.JoinOption{gtly.JoinPrefixOpt("u_", "o_")},
+			expectFields: []string{"u_id", "name", "o_id", "userId", "amount"},
+			expect: []map[string]interface{}{
+				{"u_id": 1, "name": "Adam", "o_id": 10, "userId": int64(1), "amount": 5.0},
+				{"u_id": nil, "name": nil, "o_id": 11, "userId": int64(3), "amount": 7.0},
+				{"u_id": 1, "name": "Adam", "o_id": 12, "userId": int64(1), "amount": 9.0},
+			},
+		},
+		{
+			description: "full",
+			joinType:    gtly.FullJoin,
+			expect: []map[string]interface{}{
+				{"id": 1, "name": "Adam", "order_id": 10, "userId": int64(1), "amount": 5.0},
+				{"id": 1, "name": "Adam", "order_id": 12, "userId": int64(1), "amount": 9.0},
+				{"id": 2, "name": "Tom", "order_id": nil, "userId": nil, "amount": nil},
+				{"id": nil, "name": "Nobody", "order_id": nil, "userId": nil, "amount": nil},
+				{"id": nil, "name": nil, "order_id": 11, "userId": int64(3), "amount": 7.0},
+			},
+		},
+		{
+			description: "empty prefixes",
+			joinType:    gtly.InnerJoin,
+			options:     []gtly.JoinOption{gtly.JoinPrefixOpt("", "")},
+			expectError: "duplicate join field: id, use join prefix option",
+		},
+		{
+			description: "unknown key",
+			joinType:    gtly.InnerJoin,
+			options:     []gtly.JoinOption{gtly.JoinRightKeysOpt("user")},
+			expectError: "invalid right join key: unknown field: user",
+		},
+		{
+			description: "unsupported join type",
+			joinType:    gtly.JoinType("cross"),
+			expectError: "unsupported join type: cross",
+		},
+	}
+
+	for _, testCase := range testCases {
+		options := append([]gtly.JoinOption{gtly.JoinRightKeysOpt("userId")}, testCase.options...)
+		result, err := gtly.Join(users, orders, testCase.joinType, []string{"id"}, options...)
+		if testCase.expectError != "" {
+			if assert.NotNil(t, err, testCase.description) {
+				assert.Equal(t, testCase.expectError, err.Error(), testCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		if len(testCase.expectFields) > 0 {
+			var names []string
+			for _, field := range result.Proto().Fields() {
+				names = append(names, field.Name)
+			}
+			assert.EqualValues(t, testCase.expectFields, names, testCase.description)
+		}
+		if !assert.Equal(t, len(testCase.expect), result.Size(), testCase.description) {
+			continue
+		}
+		i := 0
+		_ = result.Objects(func(item *gtly.Object) (bool, error) {
+			for name, expect := range testCase.expect[i] {
+				assert.EqualValues(t, expect, item.Value(name), testCase.description+" "+name)
+			}
+			i++
+			return true, nil
+		})
+	}
+}
+
+func TestJoin_CompositeKey(t *testing.T) {
+	provider, err := gtly.NewProvider("path",
+		gtly.NewField("dir", gtly.FieldTypeString),
+		gtly.NewField("name", gtly.FieldTypeString),
+		gtly.NewField("size", gtly.FieldTypeInt),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	left, right := provider.NewArray(), provider.NewArray()
+	assert.Nil(t, left.Add(map[string]interface{}{"dir": "x/y", "name": "z", "size": 1}))
+	assert.Nil(t, left.Add(map[string]interface{}{"dir": "", "name": "a", "size": 2}))
+	assert.Nil(t, right.Add(map[string]interface{}{"dir": "x", "name": "y/z", "size": 3}))
+	assert.Nil(t, right.Add(map[string]interface{}{"dir": "x/y", "name": "z", "size": 4}))
+	assert.Nil(t, right.Add(map[string]interface{}{"name": "a", "size": 5}))
+
+	result, err := gtly.Join(left, right, gtly.InnerJoin, []string{"dir", "name"}, gtly.JoinPrefixOpt("l_", "r_"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	if assert.Equal(t, 1, result.Size()) {
+		assert.EqualValues(t, 1, result.First().Value("l_size"))
+		assert.EqualValues(t, 4, result.First().Value("r_size"))
+	}
+}
+
+//failingCollection represents a collection failing on objects iteration
+type failingCollection struct {
+	*gtly.Array
+}
+
+func (c *failingCollection) Objects(handler func(item *gtly.Object) (bool, error)) error {
+	return errors.New("failed to read objects")
+}
+
+func TestJoin_CollectionError(t *testing.T) {
+	provider, err := gtly.NewProvider("user",
+		gtly.NewField("id", gtly.FieldTypeInt),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	users := provider.NewArray(provider.NewObject())
+	failing := &failingCollection{Array: provider.NewArray()}
+	for _, joinType := range []gtly.JoinType{gtly.InnerJoin, gtly.RightJoin} {
+		_, err = gtly.Join(users, failing, joinType, []string{"id"}, gtly.JoinPrefixOpt("", "r_"))
+		if assert.NotNil(t, err, string(joinType)) {
+			assert.Equal(t, "failed to read objects", err.Error(), string(joinType))
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package gtly
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/francoispqt/gojay"
+	"github.com/pkg/errors"
+	"github.com/viant/toolbox"
+	"reflect"
+)
+
+var jsonNull = []byte("null")
+
+//MarshalJSON converts an object into JSON object, hidden fields are skipped
+func (o *Object) MarshalJSON() ([]byte, error) {
+	if o == nil || o.proto == nil {
+		return jsonNull, nil
+	}
+	return gojay.MarshalJSONObject(o)
+}
+
+//UnmarshalJSON decodes JSON object into object storage, object has to be created by a provider
+func (o *Object) UnmarshalJSON(data []byte) error {
+	if o.proto == nil {
+		return errors.New("failed to unmarshal JSON: object provider was not attached")
+	}
+	return gojay.UnmarshalJSONObject(data, o)
+}
+
+//MarshalJSONObject converts an object into JSON object
+func (o *Object) MarshalJSONObject(enc *gojay.Encoder) {
+	fields := o.proto.Fields()
+	for i := range fields {
+		field := &fields[i]
+		if field.hidden {
+			continue
+		}
+		value, ok := o.ValueAt(field.Index)
+		if field.ShallOmitEmpty() && !ok {
+			continue
+		}
+		if value == nil || value == NilValue {
+			enc.AddNullKey(field.OutputName())
+			continue
+		}
+		if err := o.encodeJSONValue(field, value, enc); err != nil {
+			enc.AddInterface(err)
+		}
+	}
+}
+
+func (o *Object) encodeJSONValue(field *Field, value interface{}, enc *gojay.Encoder) error {
+	fieldName := field.OutputName()
+	switch field.DataType {
+	case FieldTypeInt:
+		enc.IntKey(fieldName, toolbox.AsInt(value))
+		return nil
+	case FieldTypeInt64:
+		enc.Int64Key(fieldName, int64(toolbox.AsInt(value)))
+		return nil
+	case FieldTypeFloat32, FieldTypeFloat64:
+		enc.FloatKey(fieldName, toolbox.AsFloat(value))
+		return nil
+	case FieldTypeBool:
+		enc.BoolKey(fieldName, toolbox.AsBoolean(value))
+		return nil
+	case FieldTypeBytes:
+		if bs, ok := value.([]byte); ok {
+			value = base64.StdEncoding.EncodeToString(bs)
+		}
+	case FieldTypeArray:
+		var marshaler gojay.MarshalerJSONArray
+		if collection, ok := value.(Collection); ok {
+			if reflect.ValueOf(collection).IsNil() {
+				return nil
+			}
+			marshaler = &jsonCollection{collection}
+		} else {
+			marshaler = &jsonSlice{value}
+		}
+		enc.ArrayKeyOmitEmpty(fieldName, marshaler)
+		return nil
+	case FieldTypeObject:
+		if object, ok := value.(*Object); ok {
+			enc.ObjectKeyOmitEmpty(fieldName, object)
+			return nil
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		embedded := gojay.EmbeddedJSON(data)
+		enc.AddEmbeddedJSONKey(fieldName, &embedded)
+		return nil
+	case FieldTypeTime:
+		timeLayout := field.TimeLayout()
+		if timeLayout != "" {
+			if timeValue, err := toolbox.ToTime(value, timeLayout); err == nil {
+				value = timeValue.Format(timeLayout)
+			}
+		}
+	}
+	if field.ShallOmitEmpty() {
+		enc.StringKeyOmitEmpty(fieldName, toolbox.AsString(value))
+		return nil
+	}
+	enc.StringKey(fieldName, toolbox.AsString(value))
+	return nil
+}
+
+//NKeys returns number of keys to decode, 0 decodes all keys
+func (o *Object) NKeys() int {
+	return 0
+}
+
+//UnmarshalJSONObject decodes JSON key value straight into object storage, unknown keys are skipped
+func (o *Object) UnmarshalJSONObject(dec *gojay.Decoder, key string) error {
+	field := o.proto.Lookup(key)
+	if field == nil {
+		return nil
+	}
+	return decodeJSONValue(dec, o, field)
+}
+
+//MarshalJSON converts an array into JSON array
+func (a *Array) MarshalJSON() ([]byte, error) {
+	return marshalJSONCollection(a)
+}
+
+//MarshalJSONArray converts an array into JSON array
+func (a *Array) MarshalJSONArray(enc *gojay.Encoder) {
+	encodeJSONCollection(a, enc)
+}
+
+//UnmarshalJSON decodes JSON array items into new array objects, array has to be created by a provider
+func (a *Array) UnmarshalJSON(data []byte) error {
+	if a._provider == nil {
+		return errors.New("failed to unmarshal JSON: array provider was not attached")
+	}
+	return gojay.UnmarshalJSONArray(data, a)
+}
+
+//UnmarshalJSONArray decodes JSON array item into a new array object, null items are skipped
+func (a *Array) UnmarshalJSONArray(dec *gojay.Decoder) error {
+	item := a._provider.NewObject()
+	if err := dec.Object(item); err != nil {
+		return err
+	}
+	if item.IsNil() {
+		return nil
+	}
+	a.AddObject(item)
+	return nil
+}
+
+//IsNil returns true if array is empty
+func (a *Array) IsNil() bool {
+	return a == nil || len(a._data) == 0
+}
+
+//MarshalJSON converts map objects into JSON array
+func (m *Map) MarshalJSON() ([]byte, error) {
+	return marshalJSONCollection(m)
+}
+
+//MarshalJSONArray converts map objects into JSON array
+func (m *Map) MarshalJSONArray(enc *gojay.Encoder) {
+	encodeJSONCollection(m, enc)
+}
+
+//IsNil returns true if map is empty
+func (m *Map) IsNil() bool {
+	return m == nil || len(m._map) == 0
+}
+
+//MarshalJSON converts multimap objects into JSON array
+func (m *Multimap) MarshalJSON() ([]byte, error) {
+	return marshalJSONCollection(m)
+}
+
+//MarshalJSONArray converts multimap objects into JSON array
+func (m *Multimap) MarshalJSONArray(enc *gojay.Encoder) {
+	encodeJSONCollection(m, enc)
+}
+
+func marshalJSONCollection(collection Collection) ([]byte, error) {
+	if reflect.ValueOf(collection).IsNil() {
+		return jsonNull, nil
+	}
+	return gojay.MarshalJSONArray(&jsonCollection{collection})
+}
+
+func encodeJSONCollection(collection Collection, enc *gojay.Encoder) {
+	_ = collection.Objects(func(item *Object) (bool, error) {
+		enc.AddObject(item)
+		return true, nil
+	})
+}
+
+//jsonCollection represents JSON collection wrapper
+type jsonCollection struct {
+	Collection
+}
+
+//IsNil returns true if collection is empty
+func (c *jsonCollection) IsNil() bool {
+	return c.Collection.Size() == 0
+}
+
+//MarshalJSONArray converts collection into JSON array
+func (c *jsonCollection) MarshalJSONArray(enc *gojay.Encoder) {
+	encodeJSONCollection(c.Collection, enc)
+}
+
+//jsonSlice represents a primitive JSON slice
+type jsonSlice struct {
+	data interface{}
+}
+
+//IsNil returns true if slice is empty
+func (s *jsonSlice) IsNil() bool {
+	if s.data == nil {
+		return true
+	}
+	return reflect.ValueOf(s.data).Len() == 0
+}
+
+//MarshalJSONArray converts primitive slice into JSON array
+func (s *jsonSlice) MarshalJSONArray(enc *gojay.Encoder) {
+	encodeJSONSlice(s.data, enc)
+}
+
+func encodeJSONSlice(data interface{}, enc *gojay.Encoder) {
+	toolbox.ProcessSlice(data, func(item interface{}) bool {
+		if item == nil {
+			enc.AddNull()
+			return true
+		}
+		fields, err := MapFields(item)
+		if err != nil {
+			enc.AddInterface(item) // item has to be primitive
+			return true
+		}
+		provider, err := NewProvider("", fields...)
+		if err != nil {
+			enc.AddInterface(item)
+			return true
+		}
+		if object, err := provider.Object(item); err == nil {
+			item = object
+		}
+		enc.AddInterface(item)
+		return true
+	})
+}
@@ -0,0 +1,180 @@
+package gtly
+
+import (
+	"encoding/base64"
+	"github.com/francoispqt/gojay"
+	"github.com/pkg/errors"
+	"github.com/viant/toolbox"
+	"reflect"
+	"time"
+)
+
+var (
+	typeInts    = reflect.TypeOf([]int{})
+	typeStrings = reflect.TypeOf([]string{})
+	typeFloats  = reflect.TypeOf([]float64{})
+	typeBools   = reflect.TypeOf([]bool{})
+)
+
+//decodeJSONValue decodes JSON value straight into object field storage
+func decodeJSONValue(dec *gojay.Decoder, object *Object, field *Field) error {
+	mutator := object.Proto().MutatorAt(field.Index)
+	switch mutator.Kind() {
+	case reflect.Int:
+		var value *int
+		if err := dec.IntNull(&value); err != nil || value == nil {
+			return err
+		}
+		mutator.Int(object, *value)
+	case reflect.Int64:
+		var value *int64
+		if err := dec.Int64Null(&value); err != nil || value == nil {
+			return err
+		}
+		mutator.Int64(object, *value)
+	case reflect.Float32:
+		var value *float32
+		if err := dec.Float32Null(&value); err != nil || value == nil {
+			return err
+		}
+		mutator.Float32(object, *value)
+	case reflect.Float64:
+		var value *float64
+		if err := dec.Float64Null(&value); err != nil || value == nil {
+			return err
+		}
+		mutator.Float64(object, *value)
+	case reflect.Bool:
+		var value *bool
+		if err := dec.BoolNull(&value); err != nil || value == nil {
+			return err
+		}
+		mutator.Bool(object, *value)
+	case reflect.String:
+		var value *string
+		if err := dec.StringNull(&value); err != nil || value == nil {
+			return err
+		}
+		mutator.String(object, *value)
+	case reflect.Struct:
+		if mutator.Type == typeTime {
+			return decodeTime(dec, object, field, mutator)
+		}
+		return decodeInterface(dec, object, field, mutator)
+	case reflect.Ptr:
+		switch mutator.Type {
+		case typeTimePtr:
+			return decodeTime(dec, object, field, mutator)
+		case typeObject:
+			return decodeObject(dec, object, field, mutator)
+		case typeArray:
+			return decodeArray(dec, object, field, mutator)
+		}
+		return decodeInterface(dec, object, field, mutator)
+	case reflect.Slice:
+		return decodeSlice(dec, object, field, mutator)
+	default:
+		return decodeInterface(dec, object, field, mutator)
+	}
+	return nil
+}
+
+func decodeTime(dec *gojay.Decoder, object *Object, field *Field, mutator *Mutator) error {
+	var value *string
+	if err := dec.StringNull(&value); err != nil || value == nil {
+		return err
+	}
+	timeLayout := field.TimeLayout()
+	ts, err := time.Parse(timeLayout, *value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to decode %v with layout %v", field.Name, timeLayout)
+	}
+	if mutator.Kind() == reflect.Ptr {
+		mutator.TimePtr(object, &ts)
+		return nil
+	}
+	mutator.Time(object, ts)
+	return nil
+}
+
+func decodeSlice(dec *gojay.Decoder, object *Object, field *Field, mutator *Mutator) error {
+	var err error
+	switch mutator.Type {
+	case typeBytes:
+		var value *string
+		if err = dec.StringNull(&value); err != nil || value == nil {
+			return err
+		}
+		data, err := base64.StdEncoding.DecodeString(*value)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode %v", field.Name)
+		}
+		mutator.Bytes(object, data)
+		return nil
+	case typeInts:
+		var value []int
+		if err = dec.SliceInt(&value); err == nil && value != nil {
+			mutator.SetValue(object, value)
+		}
+	case typeStrings:
+		var value []string
+		if err = dec.SliceString(&value); err == nil && value != nil {
+			mutator.SetValue(object, value)
+		}
+	case typeFloats:
+		var value []float64
+		if err = dec.SliceFloat64(&value); err == nil && value != nil {
+			mutator.SetValue(object, value)
+		}
+	case typeBools:
+		var value []bool
+		if err = dec.SliceBool(&value); err == nil && value != nil {
+			mutator.SetValue(object, value)
+		}
+	default:
+		return decodeInterface(dec, object, field, mutator)
+	}
+	return err
+}
+
+//decodeObject decodes JSON object into a new nested object, empty object is skipped
+func decodeObject(dec *gojay.Decoder, object *Object, field *Field, mutator *Mutator) error {
+	provider := field.ItemProvider()
+	if provider == nil {
+		return errors.Errorf("failed to decode %v: nested object provider was nil", field.Name)
+	}
+	item := provider.NewObject()
+	if err := dec.Object(item); err != nil || item.IsNil() {
+		return err
+	}
+	mutator.SetValue(object, item)
+	return nil
+}
+
+//decodeArray decodes JSON array into a new nested array, empty array is skipped
+func decodeArray(dec *gojay.Decoder, object *Object, field *Field, mutator *Mutator) error {
+	provider := field.ItemProvider()
+	if provider == nil {
+		return errors.Errorf("failed to decode %v: nested array provider was nil", field.Name)
+	}
+	items := provider.NewArray()
+	if err := dec.Array(items); err != nil || items.Size() == 0 {
+		return err
+	}
+	mutator.SetValue(object, items)
+	return nil
+}
+
+func decodeInterface(dec *gojay.Decoder, object *Object, field *Field, mutator *Mutator) error {
+	var value interface{}
+	if err := dec.Interface(&value); err != nil || value == nil {
+		return err
+	}
+	target := reflect.New(mutator.Type)
+	converter := toolbox.NewConverter(field.TimeLayout(), "")
+	if err := converter.AssignConverted(target.Interface(), value); err != nil {
+		return errors.Wrapf(err, "failed to decode %v", field.Name)
+	}
+	mutator.SetValue(object, target.Elem().Interface())
+	return nil
+}
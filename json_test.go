@@ -0,0 +1,245 @@
+package gtly_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"github.com/viant/toolbox/format"
+	"testing"
+	"time"
+)
+
+func TestObject_MarshalJSON(t *testing.T) {
+	provider, err := gtly.NewProvider("user",
+		gtly.NewField("Id", gtly.FieldTypeInt),
+		gtly.NewField("Name", gtly.FieldTypeString),
+		gtly.NewField("Secret", gtly.FieldTypeString),
+		gtly.NewField("Email", gtly.FieldTypeString, gtly.OmitEmptyOpt(true)),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_ = provider.OutputCaseFormat(format.CaseUpperCamel, format.CaseLowerUnderscore)
+	provider.Hide("Secret")
+
+	type envelope struct {
+		Kind string
+		User *gtly.Object
+		Tags *gtly.Object `json:",omitempty"`
+	}
+
+	var testCases = []struct {
+		description string
+		values      map[string]interface{}
+		expect      string
+	}{
+		{
+			description: "output names, hidden and omit empty",
+			values:      map[string]interface{}{"Id": 1, "Name": "Bob", "Secret": "xyz"},
+			expect:      `{"Kind":"user","User":{"id":1,"name":"Bob"}}`,
+		},
+		{
+			description: "all visible fields",
+			values:      map[string]interface{}{"Id": 2, "Name": "Ann", "Email": "ann@test.io"},
+			expect:      `{"Kind":"user","User":{"id":2,"name":"Ann","email":"ann@test.io"}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		object := provider.NewObject()
+		assert.Nil(t, object.Set(testCase.values), testCase.description)
+		data, err := json.Marshal(envelope{Kind: "user", User: object})
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, string(data), testCase.description)
+	}
+}
+
+func TestObject_UnmarshalJSON(t *testing.T) {
+	provider, err := gtly.NewProvider("user",
+		gtly.NewField("Id", gtly.FieldTypeInt),
+		gtly.NewField("Name", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	type envelope struct {
+		Kind string
+		User *gtly.Object
+	}
+
+	var testCases = []struct {
+		description string
+		user        *gtly.Object
+		json        string
+		expect      map[string]interface{}
+		expectError bool
+	}{
+		{
+			description: "provider attached",
+			user:        provider.NewObject(),
+			json:        `{"Kind":"user","User":{"Id":3,"Name":"Tom","Other":true}}`,
+			expect:      map[string]interface{}{"Id": 3, "Name": "Tom"},
+		},
+		{
+			description: "provider not attached",
+			json:        `{"Kind":"user","User":{"Id":3}}`,
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		aStruct := envelope{User: testCase.user}
+		err := json.Unmarshal([]byte(testCase.json), &aStruct)
+		if testCase.expectError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, "user", aStruct.Kind, testCase.description)
+		assert.EqualValues(t, testCase.expect, aStruct.User.AsMap(), testCase.description)
+	}
+}
+
+func TestCollection_MarshalJSON(t *testing.T) {
+	provider, err := gtly.NewProvider("user",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	values := []map[string]interface{}{
+		{"id": 1, "name": "Bob"},
+	}
+	var testCases = []struct {
+		description string
+		collection  gtly.Collection
+		expect      string
+	}{
+		{
+			description: "array",
+			collection:  provider.NewArray(),
+			expect:      `[{"id":1,"name":"Bob"}]`,
+		},
+		{
+			description: "map",
+			collection:  provider.NewMap(gtly.NewKeyProvider("id")),
+			expect:      `[{"id":1,"name":"Bob"}]`,
+		},
+		{
+			description: "multimap",
+			collection:  provider.NewMultimap(gtly.NewKeyProvider("id")),
+			expect:      `[{"id":1,"name":"Bob"}]`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		for _, value := range values {
+			assert.Nil(t, testCase.collection.Add(value), testCase.description)
+		}
+		data, err := json.Marshal(map[string]interface{}{"items": testCase.collection})
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, `{"items":`+testCase.expect+`}`, string(data), testCase.description)
+	}
+	var array *gtly.Array
+	data, err := json.Marshal(struct{ Items *gtly.Array }{array})
+	assert.Nil(t, err)
+	assert.EqualValues(t, `{"Items":null}`, string(data))
+}
+
+func TestObject_JSONRoundTrip(t *testing.T) {
+	itemProvider, err := gtly.NewProvider("item",
+		gtly.NewField("sku", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	provider, err := gtly.NewProvider("all",
+		gtly.NewField("int", gtly.FieldTypeInt),
+		gtly.NewField("int64", gtly.FieldTypeInt64),
+		gtly.NewField("float32", gtly.FieldTypeFloat32),
+		gtly.NewField("float64", gtly.FieldTypeFloat64),
+		gtly.NewField("bool", gtly.FieldTypeBool),
+		gtly.NewField("string", gtly.FieldTypeString),
+		gtly.NewField("time", gtly.FieldTypeTime, gtly.DateLayoutOpt(time.RFC3339)),
+		gtly.NewField("bytes", gtly.FieldTypeBytes),
+		gtly.NewField("array", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeInt)),
+		gtly.NewField("object", gtly.FieldTypeObject, gtly.ProviderOpt(itemProvider)),
+		gtly.NewField("items", gtly.FieldTypeArray, gtly.ProviderOpt(itemProvider)),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	created := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	object := provider.NewObject()
+	assert.Nil(t, object.Set(map[string]interface{}{
+		"int":     1,
+		"int64":   int64(1) << 40,
+		"float32": float32(1.5),
+		"float64": 2.25,
+		"bool":    true,
+		"string":  "abc",
+		"time":    created,
+		"bytes":   []byte("xyz"),
+		"array":   []int{1, 2},
+		"object":  map[string]interface{}{"sku": "a"},
+		"items":   []interface{}{map[string]interface{}{"sku": "b"}},
+	}))
+	data, err := json.Marshal(object)
+	if !assert.Nil(t, err) {
+		return
+	}
+	actual := provider.NewObject()
+	if !assert.Nil(t, json.Unmarshal(data, actual), string(data)) {
+		return
+	}
+	assert.EqualValues(t, object.AsMap(), actual.AsMap(), string(data))
+	for _, field := range provider.Fields() {
+		assert.True(t, actual.SetAt(field.Index), field.Name)
+	}
+}
+
+func TestObject_JSONRoundTrip_CaseFormat(t *testing.T) {
+	addressProvider, err := gtly.NewProvider("address",
+		gtly.NewField("cityName", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	provider, err := gtly.NewProvider("user",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("firstName", gtly.FieldTypeString),
+		gtly.NewField("homeAddress", gtly.FieldTypeObject, gtly.ProviderOpt(addressProvider)),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, addressProvider.OutputCaseFormat(format.CaseLowerCamel, format.CaseLowerUnderscore))
+	assert.Nil(t, provider.OutputCaseFormat(format.CaseLowerCamel, format.CaseLowerUnderscore))
+	object, err := provider.Object(map[string]interface{}{
+		"id":          1,
+		"firstName":   "Bob",
+		"homeAddress": map[string]interface{}{"cityName": "Warsaw"},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	data, err := json.Marshal(object)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, `{"id":1,"first_name":"Bob","home_address":{"city_name":"Warsaw"}}`, string(data))
+	actual := provider.NewObject()
+	if !assert.Nil(t, json.Unmarshal(data, actual)) {
+		return
+	}
+	assert.EqualValues(t, object.AsMap(), actual.AsMap())
+	assert.Equal(t, "Bob", actual.Value("firstName"))
+}
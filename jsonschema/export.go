@@ -0,0 +1,172 @@
+package jsonschema
+
+import (
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"reflect"
+	"time"
+)
+
+var typeTime = reflect.TypeOf(time.Time{})
+
+//dialect represents schema flavour
+type dialect int
+
+const (
+	dialectJSONSchema = dialect(iota)
+	dialectOpenAPI
+)
+
+//FromProto converts proto into JSON schema (draft 2020-12) document
+func FromProto(proto *gtly.Proto) (*Schema, error) {
+	result, err := dialectJSONSchema.objectSchema(proto)
+	if err != nil {
+		return nil, err
+	}
+	result.Schema = Draft
+	result.Title = proto.Name
+	return result, nil
+}
+
+//OpenAPI converts proto into OpenAPI 3 schema object
+func OpenAPI(proto *gtly.Proto) (*Schema, error) {
+	return dialectOpenAPI.objectSchema(proto)
+}
+
+//Components converts protos into OpenAPI 3 components schemas keyed by proto name
+func Components(protos ...*gtly.Proto) (map[string]*Schema, error) {
+	var result = make(map[string]*Schema, len(protos))
+	for _, proto := range protos {
+		if _, ok := result[proto.Name]; ok {
+			return nil, errors.Errorf("duplicate component: %v", proto.Name)
+		}
+		schema, err := OpenAPI(proto)
+		if err != nil {
+			return nil, err
+		}
+		result[proto.Name] = schema
+	}
+	return result, nil
+}
+
+//objectSchema returns object schema, hidden fields are excluded, fields without omit empty are required
+func (d dialect) objectSchema(proto *gtly.Proto) (*Schema, error) {
+	result := &Schema{Type: "object"}
+	fields := proto.Fields()
+	for i := range fields {
+		field := &fields[i]
+		if field.IsHidden() {
+			continue
+		}
+		schema, err := d.fieldSchema(field)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert %v.%v", proto.Name, field.Name)
+		}
+		name := field.OutputName()
+		result.Properties = append(result.Properties, &Property{Name: name, Schema: schema})
+		if !field.ShallOmitEmpty() {
+			result.Required = append(result.Required, name)
+		}
+	}
+	return result, nil
+}
+
+func (d dialect) fieldSchema(field *gtly.Field) (*Schema, error) {
+	layout := field.TimeLayout()
+	switch field.DataType {
+	case gtly.FieldTypeObject:
+		if provider := field.ItemProvider(); provider != nil {
+			return d.objectSchema(provider.Proto)
+		}
+	case gtly.FieldTypeArray:
+		if provider := field.ItemProvider(); provider != nil {
+			items, err := d.objectSchema(provider.Proto)
+			return &Schema{Type: "array", Items: items}, err
+		}
+		if items := d.baseSchema(field.ComponentType, layout); items != nil {
+			return &Schema{Type: "array", Items: items}, nil
+		}
+	default:
+		if result := d.baseSchema(field.DataType, layout); result != nil && !gtly.IsComposite(field.Type) {
+			return result, nil
+		}
+	}
+	return d.typeSchema(field.Type, layout)
+}
+
+//baseSchema returns schema for gtly base data type, or nil for unsupported data type
+func (d dialect) baseSchema(dataType string, layout string) *Schema {
+	switch dataType {
+	case gtly.FieldTypeInt:
+		return &Schema{Type: "integer"}
+	case gtly.FieldTypeInt64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case gtly.FieldTypeFloat32:
+		return &Schema{Type: "number", Format: "float"}
+	case gtly.FieldTypeFloat64:
+		return &Schema{Type: "number", Format: "double"}
+	case gtly.FieldTypeBool:
+		return &Schema{Type: "boolean"}
+	case gtly.FieldTypeString:
+		return &Schema{Type: "string"}
+	case gtly.FieldTypeTime:
+		if layout == "2006-01-02" {
+			return &Schema{Type: "string", Format: "date"}
+		}
+		return &Schema{Type: "string", Format: "date-time"}
+	case gtly.FieldTypeBytes:
+		if d == dialectOpenAPI {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "string", ContentEncoding: "base64"}
+	}
+	return nil
+}
+
+//typeSchema returns schema for fields which data type does not describe its type
+func (d dialect) typeSchema(rType reflect.Type, layout string) (*Schema, error) {
+	switch rType.Kind() {
+	case reflect.Ptr:
+		return d.typeSchema(rType.Elem(), layout)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return d.baseSchema(gtly.FieldTypeInt, layout), nil
+	case reflect.Int64, reflect.Uint64:
+		return d.baseSchema(gtly.FieldTypeInt64, layout), nil
+	case reflect.Float32:
+		return d.baseSchema(gtly.FieldTypeFloat32, layout), nil
+	case reflect.Float64:
+		return d.baseSchema(gtly.FieldTypeFloat64, layout), nil
+	case reflect.Bool:
+		return d.baseSchema(gtly.FieldTypeBool, layout), nil
+	case reflect.String:
+		return d.baseSchema(gtly.FieldTypeString, layout), nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice:
+		if rType.Elem().Kind() == reflect.Uint8 {
+			return d.baseSchema(gtly.FieldTypeBytes, layout), nil
+		}
+		items, err := d.typeSchema(rType.Elem(), layout)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		return &Schema{Type: "object"}, nil
+	case reflect.Struct:
+		if rType == typeTime {
+			return d.baseSchema(gtly.FieldTypeTime, layout), nil
+		}
+		result := &Schema{Type: "object"}
+		for i := 0; i < rType.NumField(); i++ {
+			structField := rType.Field(i)
+			schema, err := d.typeSchema(structField.Type, layout)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to convert %v", structField.Name)
+			}
+			result.Properties = append(result.Properties, &Property{Name: structField.Name, Schema: schema})
+		}
+		return result, nil
+	}
+	return nil, errors.Errorf("unsupported type: %v", rType)
+}
@@ -0,0 +1,106 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"github.com/viant/toolbox/format"
+	"reflect"
+	"testing"
+)
+
+func newExportProvider(t *testing.T) *gtly.Provider {
+	address, err := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("zip", gtly.FieldTypeString, gtly.OmitEmptyOpt(true)),
+	)
+	assert.Nil(t, err)
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt64),
+		gtly.NewField("firstName", gtly.FieldTypeString, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("secret", gtly.FieldTypeString),
+		gtly.NewField("score", gtly.FieldTypeFloat64, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("updated", gtly.FieldTypeTime, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("birthDay", gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02"), gtly.OmitEmptyOpt(true)),
+		gtly.NewField("avatar", gtly.FieldTypeBytes, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("tags", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeString), gtly.OmitEmptyOpt(true)),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(address), gtly.OmitEmptyOpt(true)),
+		gtly.NewField("addresses", gtly.FieldTypeArray, gtly.ProviderOpt(address), gtly.OmitEmptyOpt(true)),
+		&gtly.Field{Name: "ratios", Type: reflect.TypeOf([]*float32{}), OmitEmpty: &[]bool{true}[0]},
+	)
+	assert.Nil(t, err)
+	provider.Hide("secret")
+	return provider
+}
+
+func TestFromProto(t *testing.T) {
+	provider := newExportProvider(t)
+	testCases := []struct {
+		description string
+		setup       func()
+		convert     func(proto *gtly.Proto) (*Schema, error)
+		expect      string
+	}{
+		{
+			description: "json schema",
+			convert:     FromProto,
+			expect: `{"$schema":"https://json-schema.org/draft/2020-12/schema","title":"foo","type":"object","properties":{` +
+				`"id":{"type":"integer","format":"int64"},"firstName":{"type":"string"},"score":{"type":"number","format":"double"},` +
+				`"updated":{"type":"string","format":"date-time"},"birthDay":{"type":"string","format":"date"},` +
+				`"avatar":{"type":"string","contentEncoding":"base64"},"tags":{"type":"array","items":{"type":"string"}},` +
+				`"address":{"type":"object","properties":{"city":{"type":"string"},"zip":{"type":"string"}},"required":["city"]},` +
+				`"addresses":{"type":"array","items":{"type":"object","properties":{"city":{"type":"string"},"zip":{"type":"string"}},"required":["city"]}},` +
+				`"ratios":{"type":"array","items":{"type":"number","format":"float"}}},"required":["id"]}`,
+		},
+		{
+			description: "openapi schema",
+			convert:     OpenAPI,
+			expect: `{"type":"object","properties":{` +
+				`"id":{"type":"integer","format":"int64"},"firstName":{"type":"string"},"score":{"type":"number","format":"double"},` +
+				`"updated":{"type":"string","format":"date-time"},"birthDay":{"type":"string","format":"date"},` +
+				`"avatar":{"type":"string","format":"byte"},"tags":{"type":"array","items":{"type":"string"}},` +
+				`"address":{"type":"object","properties":{"city":{"type":"string"},"zip":{"type":"string"}},"required":["city"]},` +
+				`"addresses":{"type":"array","items":{"type":"object","properties":{"city":{"type":"string"},"zip":{"type":"string"}},"required":["city"]}},` +
+				`"ratios":{"type":"array","items":{"type":"number","format":"float"}}},"required":["id"]}`,
+		},
+		{
+			description: "output case format",
+			setup: func() {
+				_ = provider.OutputCaseFormat(format.CaseLowerCamel, format.CaseLowerUnderscore)
+				provider.Show("secret")
+			},
+			convert: OpenAPI,
+			expect: `{"type":"object","properties":{` +
+				`"id":{"type":"integer","format":"int64"},"first_name":{"type":"string"},"secret":{"type":"string"},"score":{"type":"number","format":"double"},` +
+				`"updated":{"type":"string","format":"date-time"},"birth_day":{"type":"string","format":"date"},` +
+				`"avatar":{"type":"string","format":"byte"},"tags":{"type":"array","items":{"type":"string"}},` +
+				`"address":{"type":"object","properties":{"city":{"type":"string"},"zip":{"type":"string"}},"required":["city"]},` +
+				`"addresses":{"type":"array","items":{"type":"object","properties":{"city":{"type":"string"},"zip":{"type":"string"}},"required":["city"]}},` +
+				`"ratios":{"type":"array","items":{"type":"number","format":"float"}}},"required":["id","secret"]}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		if testCase.setup != nil {
+			testCase.setup()
+		}
+		schema, err := testCase.convert(provider.Proto)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		actual, err := json.Marshal(schema)
+		assert.Nil(t, err, testCase.description)
+		assert.EqualValues(t, testCase.expect, string(actual), testCase.description)
+	}
+}
+
+func TestComponents(t *testing.T) {
+	provider := newExportProvider(t)
+	components, err := Components(provider.Proto, provider.Field("address").ItemProvider().Proto)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(components))
+	assert.NotNil(t, components["foo"].Property("addresses").Items.Property("city"))
+
+	_, err = Components(provider.Proto, provider.Proto)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,224 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"github.com/viant/gtly/internal/naming"
+	"io"
+	"reflect"
+	"strings"
+)
+
+var (
+	typeInterfaces = reflect.TypeOf([]interface{}{})
+	typeMap        = reflect.TypeOf(map[string]interface{}{})
+)
+
+//importer resolves schema references against the root document
+type importer struct {
+	root      *Schema
+	providers map[string]*gtly.Provider
+	resolving map[string]bool
+}
+
+//LoadProvider loads a provider from JSON schema document
+func LoadProvider(reader io.Reader) (*gtly.Provider, error) {
+	schema := &Schema{}
+	if err := json.NewDecoder(reader).Decode(schema); err != nil {
+		return nil, errors.Wrap(err, "failed to decode JSON schema")
+	}
+	return NewProvider(schema)
+}
+
+//NewProvider creates a provider from JSON schema object, sub objects and array items objects use nested providers
+func NewProvider(schema *Schema) (*gtly.Provider, error) {
+	importer := &importer{root: schema, providers: map[string]*gtly.Provider{}, resolving: map[string]bool{}}
+	schema, name, err := importer.resolve(schema)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Title != "" {
+		name = schema.Title
+	}
+	if schema.Type != "object" || len(schema.Properties) == 0 {
+		return nil, errors.Errorf("expected object schema with properties, but had: %v", schema.Type)
+	}
+	return importer.newProvider(name, schema)
+}
+
+//newProvider creates object provider, property names are converted to Go identifiers, source names are kept as input and output names.
+//Optional and nullable properties use omit empty fields
+func (i *importer) newProvider(name string, schema *Schema) (*gtly.Provider, error) {
+	var fields = make([]*gtly.Field, 0, len(schema.Properties))
+	var names = map[string]bool{}
+	for _, property := range schema.Properties {
+		fieldName := naming.FieldName(property.Name, names)
+		field, err := i.newField(fieldName, property.Schema)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert %v.%v", name, property.Name)
+		}
+		if fieldName != property.Name {
+			field.InputName = property.Name
+			gtly.OutputNameOpt(property.Name)(field)
+		}
+		field.OmitEmpty = &[]bool{!schema.IsRequired(property.Name) || property.Schema.IsNullable()}[0]
+		fields = append(fields, field)
+	}
+	return gtly.NewProvider(name, fields...)
+}
+
+func (i *importer) newField(name string, schema *Schema) (*gtly.Field, error) {
+	schema, refName, err := i.resolve(schema)
+	if err != nil {
+		return nil, err
+	}
+	switch schema.Type {
+	case "object":
+		if len(schema.Properties) == 0 {
+			return &gtly.Field{Name: name, DataType: gtly.FieldTypeObject, Type: typeMap}, nil
+		}
+		provider, err := i.objectProvider(name, refName, schema)
+		if err != nil {
+			return nil, err
+		}
+		return gtly.NewField(name, gtly.FieldTypeObject, gtly.ProviderOpt(provider)), nil
+	case "array":
+		if schema.Items == nil {
+			return &gtly.Field{Name: name, DataType: gtly.FieldTypeArray, Type: typeInterfaces}, nil
+		}
+		items, itemsRefName, err := i.resolve(schema.Items)
+		if err != nil {
+			return nil, err
+		}
+		switch items.Type {
+		case "object":
+			if len(items.Properties) == 0 {
+				return &gtly.Field{Name: name, DataType: gtly.FieldTypeArray, Type: reflect.SliceOf(typeMap)}, nil
+			}
+			provider, err := i.objectProvider(name, itemsRefName, items)
+			if err != nil {
+				return nil, err
+			}
+			return gtly.NewField(name, gtly.FieldTypeArray, gtly.ProviderOpt(provider)), nil
+		case "array", "":
+			return &gtly.Field{Name: name, DataType: gtly.FieldTypeArray, Type: typeInterfaces}, nil
+		}
+		componentType, layout, err := baseType(items)
+		if err != nil {
+			return nil, err
+		}
+		return gtly.NewField(name, gtly.FieldTypeArray, gtly.ComponentTypeOpt(componentType), gtly.DateLayoutOpt(layout)), nil
+	case "":
+		return &gtly.Field{Name: name, Type: reflect.TypeOf((*interface{})(nil)).Elem()}, nil
+	}
+	dataType, layout, err := baseType(schema)
+	if err != nil {
+		return nil, err
+	}
+	return gtly.NewField(name, dataType, gtly.DateLayoutOpt(layout)), nil
+}
+
+//objectProvider returns nested provider, referenced definitions share the same provider
+func (i *importer) objectProvider(name, refName string, schema *Schema) (*gtly.Provider, error) {
+	if refName == "" {
+		return i.newProvider(name, schema)
+	}
+	if provider, ok := i.providers[refName]; ok {
+		return provider, nil
+	}
+	if i.resolving[refName] {
+		return nil, errors.Errorf("unsupported recursive reference: %v", refName)
+	}
+	i.resolving[refName] = true
+	provider, err := i.newProvider(refName, schema)
+	delete(i.resolving, refName)
+	if err != nil {
+		return nil, err
+	}
+	i.providers[refName] = provider
+	return provider, nil
+}
+
+//resolve resolves $ref and nullable anyOf/oneOf schema, it returns resolved schema and referenced definition name
+func (i *importer) resolve(schema *Schema) (*Schema, string, error) {
+	refName := ""
+	for depth := 0; ; depth++ {
+		if depth > 32 {
+			return nil, "", errors.Errorf("too many nested references: %v", refName)
+		}
+		if len(schema.AnyOf)+len(schema.OneOf) > 0 {
+			var actual *Schema
+			for _, variant := range append(append([]*Schema{}, schema.AnyOf...), schema.OneOf...) {
+				if variant.Type == "null" {
+					continue
+				}
+				if actual != nil {
+					return nil, "", errors.New("unsupported anyOf/oneOf with multiple non null schemas")
+				}
+				actual = variant
+			}
+			if actual == nil {
+				return nil, "", errors.New("anyOf/oneOf non null schema was empty")
+			}
+			schema = actual
+			continue
+		}
+		if schema.Ref == "" {
+			return schema, refName, nil
+		}
+		name, definition, err := i.definition(schema.Ref)
+		if err != nil {
+			return nil, "", err
+		}
+		schema, refName = definition, name
+	}
+}
+
+//definition returns local $defs or definitions schema for supplied reference
+func (i *importer) definition(ref string) (string, *Schema, error) {
+	var definitions map[string]*Schema
+	var name string
+	switch {
+	case strings.HasPrefix(ref, "#/$defs/"):
+		definitions, name = i.root.Defs, strings.TrimPrefix(ref, "#/$defs/")
+	case strings.HasPrefix(ref, "#/definitions/"):
+		definitions, name = i.root.Definitions, strings.TrimPrefix(ref, "#/definitions/")
+	default:
+		return "", nil, errors.Errorf("unsupported reference: %v", ref)
+	}
+	definition, ok := definitions[name]
+	if !ok {
+		return "", nil, errors.Errorf("unknown reference: %v", ref)
+	}
+	return name, definition, nil
+}
+
+//baseType returns gtly data type and time layout for scalar schema
+func baseType(schema *Schema) (string, string, error) {
+	switch schema.Type {
+	case "integer":
+		if schema.Format == "int64" {
+			return gtly.FieldTypeInt64, "", nil
+		}
+		return gtly.FieldTypeInt, "", nil
+	case "number":
+		if schema.Format == "float" {
+			return gtly.FieldTypeFloat32, "", nil
+		}
+		return gtly.FieldTypeFloat64, "", nil
+	case "boolean":
+		return gtly.FieldTypeBool, "", nil
+	case "string":
+		switch {
+		case schema.Format == "date-time":
+			return gtly.FieldTypeTime, "", nil
+		case schema.Format == "date":
+			return gtly.FieldTypeTime, "2006-01-02", nil
+		case schema.Format == "byte" || schema.ContentEncoding == "base64":
+			return gtly.FieldTypeBytes, "", nil
+		}
+		return gtly.FieldTypeString, "", nil
+	}
+	return "", "", errors.Errorf("unsupported schema type: %v", schema.Type)
+}
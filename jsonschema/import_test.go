@@ -0,0 +1,169 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadProvider(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		expectName     string
+		expectFields   []string
+		expectOptional []string
+		expectTypes    map[string]reflect.Type
+		expectLayouts  map[string]string
+		expectOutput   map[string]string
+		expectError    string
+	}{
+		{
+			description: "scalar types",
+			input: `{"title":"foo","type":"object","required":["id"],"properties":{
+				"id":{"type":"integer","format":"int64"},
+				"qty":{"type":["integer","null"]},
+				"price":{"type":"number"},
+				"ratio":{"type":"number","format":"float"},
+				"active":{"type":"boolean","nullable":true},
+				"name":{"type":"string"},
+				"created":{"type":"string","format":"date-time"},
+				"day":{"type":"string","format":"date"},
+				"avatar":{"type":"string","contentEncoding":"base64"}}}`,
+			expectName:     "foo",
+			expectFields:   []string{"id:int64", "qty:int", "price:float64", "ratio:float32", "active:bool", "name:string", "created:time", "day:time", "avatar:bytes"},
+			expectOptional: []string{"qty", "price", "ratio", "active", "name", "created", "day", "avatar"},
+			expectLayouts:  map[string]string{"created": time.RFC3339, "day": "2006-01-02"},
+		},
+		{
+			description: "nested objects, arrays and references",
+			input: `{"$ref":"#/$defs/order","$defs":{
+				"order":{"type":"object","required":["id","lines"],"properties":{
+					"id":{"type":"integer"},
+					"billing":{"anyOf":[{"$ref":"#/$defs/address"},{"type":"null"}]},
+					"shipping":{"$ref":"#/$defs/address"},
+					"lines":{"type":"array","items":{"type":"object","properties":{"sku":{"type":"string"},"qty":{"type":"integer"}}}},
+					"tags":{"type":"array","items":{"type":"string"}},
+					"attributes":{"type":"object"},
+					"values":{"type":"array"}}},
+				"address":{"type":"object","properties":{"city":{"type":"string"}}}}}`,
+			expectName:     "order",
+			expectFields:   []string{"id:int", "billing:object", "shipping:object", "lines:array", "tags:array", "attributes:object", "values:array"},
+			expectOptional: []string{"billing", "shipping", "tags", "attributes", "values"},
+			expectTypes: map[string]reflect.Type{
+				"billing":    reflect.StructOf([]reflect.StructField{{Name: "city", PkgPath: "github.com/viant/gtly", Type: reflect.TypeOf("")}}),
+				"lines":      reflect.StructOf([]reflect.StructField{{Name: "sku", PkgPath: "github.com/viant/gtly", Type: reflect.TypeOf("")}, {Name: "qty", PkgPath: "github.com/viant/gtly", Type: reflect.TypeOf(0)}}),
+				"tags":       reflect.TypeOf([]string{}),
+				"attributes": reflect.TypeOf(map[string]interface{}{}),
+				"values":     reflect.TypeOf([]interface{}{}),
+			},
+		},
+		{
+			description: "required nullable and non identifier names",
+			input: `{"title":"foo","type":"object","required":["id","first-name","note","user id"],"properties":{
+				"id":{"type":["integer","null"]},
+				"first-name":{"type":"string"},
+				"note":{"anyOf":[{"type":"string"},{"type":"null"}]},
+				"user id":{"type":"integer","nullable":true},
+				"1st":{"type":"string"}}}`,
+			expectName:     "foo",
+			expectFields:   []string{"id:int", "first_name:string", "note:string", "user_id:int", "F1st:string"},
+			expectOptional: []string{"id", "note", "user_id", "F1st"},
+			expectOutput:   map[string]string{"first_name": "first-name", "user_id": "user id", "F1st": "1st", "id": "id"},
+		},
+		{
+			description:    "draft 7 definitions",
+			input:          `{"type":"object","properties":{"address":{"$ref":"#/definitions/address"}},"definitions":{"address":{"type":"object","properties":{"city":{"type":"string"}}}}}`,
+			expectFields:   []string{"address:object"},
+			expectOptional: []string{"address"},
+		},
+		{
+			description: "recursive reference",
+			input:       `{"type":"object","properties":{"node":{"$ref":"#/$defs/node"}},"$defs":{"node":{"type":"object","properties":{"next":{"$ref":"#/$defs/node"}}}}}`,
+			expectError: "unsupported recursive reference: node",
+		},
+		{
+			description: "unknown reference",
+			input:       `{"type":"object","properties":{"node":{"$ref":"#/$defs/node"}}}`,
+			expectError: "unknown reference: #/$defs/node",
+		},
+		{
+			description: "non object schema",
+			input:       `{"type":"string"}`,
+			expectError: "expected object schema with properties, but had: string",
+		},
+		{
+			description: "multi type",
+			input:       `{"type":"object","properties":{"id":{"type":["integer","string"]}}}`,
+			expectError: "unsupported multi type",
+		},
+	}
+
+	for _, testCase := range testCases {
+		provider, err := LoadProvider(strings.NewReader(testCase.input))
+		if testCase.expectError != "" {
+			if assert.NotNil(t, err, testCase.description) {
+				assert.Contains(t, err.Error(), testCase.expectError, testCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expectName, provider.Name, testCase.description)
+		var actual, optional []string
+		for _, field := range provider.Fields() {
+			actual = append(actual, field.Name+":"+field.DataType)
+			if field.ShallOmitEmpty() {
+				optional = append(optional, field.Name)
+			}
+		}
+		assert.EqualValues(t, testCase.expectFields, actual, testCase.description)
+		assert.EqualValues(t, testCase.expectOptional, optional, testCase.description)
+		for name, expect := range testCase.expectTypes {
+			actualType := provider.Field(name).Type
+			if itemProvider := provider.Field(name).ItemProvider(); itemProvider != nil {
+				actualType = itemProvider.Type()
+			}
+			assert.EqualValues(t, expect.String(), actualType.String(), testCase.description+" "+name)
+		}
+		for name, expect := range testCase.expectLayouts {
+			assert.EqualValues(t, expect, provider.Field(name).TimeLayout(), testCase.description+" "+name)
+		}
+		for name, expect := range testCase.expectOutput {
+			assert.EqualValues(t, expect, provider.Field(name).OutputName(), testCase.description+" "+name)
+			assert.NotNil(t, provider.Lookup(expect), testCase.description+" "+name)
+		}
+	}
+}
+
+func TestNewProvider_RoundTrip(t *testing.T) {
+	provider := newExportProvider(t)
+	provider.Show("secret")
+	for _, export := range []func(proto *gtly.Proto) (*Schema, error){FromProto, OpenAPI} {
+		schema, err := export(provider.Proto)
+		if !assert.Nil(t, err) {
+			continue
+		}
+		data, err := json.Marshal(schema)
+		assert.Nil(t, err)
+		imported, err := LoadProvider(bytes.NewReader(data))
+		if !assert.Nil(t, err) {
+			continue
+		}
+		importedSchema, err := export(imported.Proto)
+		assert.Nil(t, err)
+		assert.EqualValues(t, schema, importedSchema)
+		for _, field := range provider.Fields() {
+			if field.Name == "ratios" { //pointer items are not represented in JSON schema
+				continue
+			}
+			assert.EqualValues(t, field.Type.String(), imported.Field(field.Name).Type.String(), field.Name)
+		}
+	}
+}
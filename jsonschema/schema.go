@@ -0,0 +1,156 @@
+//Package jsonschema provides JSON Schema (draft 2020-12) and OpenAPI 3 schema export and import for gtly proto
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pkg/errors"
+)
+
+//Draft represents JSON schema dialect URI
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+//Schema represents JSON schema or OpenAPI 3 schema object
+type Schema struct {
+	Schema          string             `json:"$schema,omitempty"`
+	ID              string             `json:"$id,omitempty"`
+	Ref             string             `json:"$ref,omitempty"`
+	Title           string             `json:"title,omitempty"`
+	Description     string             `json:"description,omitempty"`
+	Type            string             `json:"type,omitempty"`
+	Format          string             `json:"format,omitempty"`
+	ContentEncoding string             `json:"contentEncoding,omitempty"`
+	Nullable        bool               `json:"nullable,omitempty"`
+	Items           *Schema            `json:"items,omitempty"`
+	Properties      Properties         `json:"properties,omitempty"`
+	Required        []string           `json:"required,omitempty"`
+	AnyOf           []*Schema          `json:"anyOf,omitempty"`
+	OneOf           []*Schema          `json:"oneOf,omitempty"`
+	Defs            map[string]*Schema `json:"$defs,omitempty"`
+	Definitions     map[string]*Schema `json:"definitions,omitempty"`
+}
+
+//UnmarshalJSON unmarshals schema, type array with null type is unmarshaled as nullable type
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type schema Schema
+	aux := struct {
+		*schema
+		Type json.RawMessage `json:"type,omitempty"`
+	}{schema: (*schema)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.Type) == 0 {
+		return nil
+	}
+	if aux.Type[0] != '[' {
+		return json.Unmarshal(aux.Type, &s.Type)
+	}
+	var types []string
+	if err := json.Unmarshal(aux.Type, &types); err != nil {
+		return err
+	}
+	for _, aType := range types {
+		switch {
+		case aType == "null":
+			s.Nullable = true
+		case s.Type == "":
+			s.Type = aType
+		default:
+			return errors.Errorf("unsupported multi type: %v", types)
+		}
+	}
+	return nil
+}
+
+//IsRequired returns true if property is required
+func (s *Schema) IsRequired(name string) bool {
+	for _, required := range s.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+//IsNullable returns true if schema allows null with nullable flag, null type or null anyOf/oneOf variant
+func (s *Schema) IsNullable() bool {
+	if s.Nullable {
+		return true
+	}
+	for _, variant := range append(append([]*Schema{}, s.AnyOf...), s.OneOf...) {
+		if variant.Type == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+//Property returns named property schema, or nil if property does not exist
+func (s *Schema) Property(name string) *Schema {
+	for _, property := range s.Properties {
+		if property.Name == name {
+			return property.Schema
+		}
+	}
+	return nil
+}
+
+//Property represents named property schema
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+//Properties represents object properties, properties are marshaled in the field order
+type Properties []*Property
+
+//MarshalJSON marshals properties as JSON object
+func (p Properties) MarshalJSON() ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	buffer.WriteByte('{')
+	for i, property := range p {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		name, err := json.Marshal(property.Name)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(name)
+		buffer.WriteByte(':')
+		value, err := json.Marshal(property.Schema)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+//UnmarshalJSON unmarshals JSON object properties in the document order
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != json.Delim('{') {
+		return errors.Errorf("expected properties object, but had: %v", token)
+	}
+	*p = (*p)[:0]
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return err
+		}
+		property := &Property{Name: token.(string)}
+		if err = decoder.Decode(&property.Schema); err != nil {
+			return errors.Wrapf(err, "failed to decode property %v", property.Name)
+		}
+		*p = append(*p, property)
+	}
+	_, err = decoder.Token()
+	return err
+}
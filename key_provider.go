@@ -3,19 +3,22 @@ package gtly
 //KeyProvider represents a key provider
 type KeyProvider func(o *Object) interface{}
 
-//NewKeyProvider creates a key provider
+//NewKeyProvider creates a key provider, key field index is resolved again when object proto changes, nil is returned for unknown field
 func NewKeyProvider(fieldName string) KeyProvider {
-	uniqueKeyIndex := 0
-	wasKeyProduced := false
+	var keyProto *Proto
+	uniqueKeyIndex := -1
 	return func(o *Object) interface{} {
-		if wasKeyProduced {
-			value, _ := o.ValueAt(uniqueKeyIndex)
-			return value
+		if o.proto != keyProto {
+			keyProto = o.proto
+			uniqueKeyIndex = -1
+			if field := o.Field(fieldName); field != nil {
+				uniqueKeyIndex = field.Index
+			}
 		}
-
-		field := o.Field(fieldName)
-		uniqueKeyIndex = field.Index
-		wasKeyProduced = true
-		return o.Value(fieldName)
+		if uniqueKeyIndex == -1 {
+			return nil
+		}
+		value, _ := o.ValueAt(uniqueKeyIndex)
+		return value
 	}
 }
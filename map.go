@@ -93,3 +93,43 @@ func (m *Map) Pairs(handler func(key interface{}, item *Object) (bool, error)) e
 	}
 	return nil
 }
+
+//Filter returns a new map with objects matching predicate, the result shares map key provider
+func (m *Map) Filter(predicate Predicate) (*Map, error) {
+	matcher, err := predicate(m.Proto())
+	if err != nil {
+		return nil, err
+	}
+	result := m._provider.NewMap(m.keyProvider)
+	for key, item := range m._map {
+		if matcher(item) {
+			result._map[key] = item
+		}
+	}
+	return result, nil
+}
+
+//Find returns an object matching predicate or nil
+func (m *Map) Find(predicate Predicate) (*Object, error) {
+	return find(m, predicate)
+}
+
+//FindAll returns all objects matching predicate
+func (m *Map) FindAll(predicate Predicate) ([]*Object, error) {
+	return findAll(m, predicate)
+}
+
+//SortedObjects iterates over map objects in ascending key order
+func (m *Map) SortedObjects(handler func(key interface{}, item *Object) (bool, error)) error {
+	keys := make([]interface{}, 0, len(m._map))
+	for key := range m._map {
+		keys = append(keys, key)
+	}
+	for _, key := range sortedKeys(keys) {
+		next, err := handler(key, m._map[key])
+		if !next || err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -139,3 +139,50 @@ func addMapObjects(testCase MapTestCase, provider *gtly.Provider, aMap *gtly.Map
 		aMap.PutObject(value.key, anObject)
 	}
 }
+
+func TestMap_Filter(t *testing.T) {
+	provider := newPredicateProvider(t)
+	aMap := provider.NewMap(gtly.NewKeyProvider("id"))
+	_ = newPredicateArray(t, provider).Objects(func(item *gtly.Object) (bool, error) {
+		aMap.AddObject(item)
+		return true, nil
+	})
+	filtered, err := aMap.Filter(gtly.Eq("city", "Warsaw"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 2, filtered.Size())
+	assert.NotNil(t, filtered.Object(1))
+	assert.NotNil(t, filtered.Object(3))
+	assert.Nil(t, filtered.Object(2))
+
+	item := provider.NewObject()
+	item.SetValue("id", 5)
+	filtered.AddObject(item)
+	assert.NotNil(t, filtered.Object(5), "filtered map shall keep key provider")
+
+	found, err := aMap.Find(gtly.Eq("id", 2))
+	if assert.Nil(t, err) && assert.NotNil(t, found) {
+		assert.Equal(t, "Krakow", found.Value("city"))
+	}
+	objects, err := aMap.FindAll(gtly.Not(gtly.IsNull("active")))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(objects))
+}
+
+func TestMap_SortedObjects(t *testing.T) {
+	provider := newPredicateProvider(t)
+	aMap := provider.NewMap(gtly.NewKeyProvider("id"))
+	for _, id := range []int{10, 2, 33, 1} {
+		item := provider.NewObject()
+		item.SetValue("id", id)
+		aMap.AddObject(item)
+	}
+	var keys []interface{}
+	err := aMap.SortedObjects(func(key interface{}, item *gtly.Object) (bool, error) {
+		keys = append(keys, key)
+		return len(keys) < 3, nil
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []interface{}{1, 2, 10}, keys)
+}
@@ -118,3 +118,47 @@ func (m *Multimap) Size() int {
 func (m *Multimap) IsNil() bool {
 	return len(m._map) == 0
 }
+
+//Filter returns a new multimap with objects matching predicate, keys without matching objects are removed
+func (m *Multimap) Filter(predicate Predicate) (*Multimap, error) {
+	matcher, err := predicate(m.Proto())
+	if err != nil {
+		return nil, err
+	}
+	result := m._provider.NewMultimap(m.keyProvider)
+	for key, items := range m._map {
+		for _, item := range items {
+			if matcher(item) {
+				result._map[key] = append(result._map[key], item)
+			}
+		}
+	}
+	return result, nil
+}
+
+//Find returns an object matching predicate or nil
+func (m *Multimap) Find(predicate Predicate) (*Object, error) {
+	return find(m, predicate)
+}
+
+//FindAll returns all objects matching predicate
+func (m *Multimap) FindAll(predicate Predicate) ([]*Object, error) {
+	return findAll(m, predicate)
+}
+
+//SortedSlices iterates over multimap slices in ascending key order, any update to slices are applied to the multimap
+func (m *Multimap) SortedSlices(handler func(key interface{}, value *Array) (bool, error)) error {
+	keys := make([]interface{}, 0, len(m._map))
+	for key := range m._map {
+		keys = append(keys, key)
+	}
+	for _, key := range sortedKeys(keys) {
+		slice := &Array{_provider: m._provider, _data: m._map[key]}
+		next, err := handler(key, slice)
+		m._map[key] = slice._data
+		if !next || err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -86,3 +86,47 @@ func initMultimapValues(testCase MultimapTestCase, provider *gtly.Provider, mult
 		multiMap.AddObject(anObject)
 	}
 }
+
+func TestMultimap_Filter(t *testing.T) {
+	provider := newPredicateProvider(t)
+	multimap := provider.NewMultimap(gtly.NewKeyProvider("city"))
+	_ = newPredicateArray(t, provider).Objects(func(item *gtly.Object) (bool, error) {
+		multimap.AddObject(item)
+		return true, nil
+	})
+	filtered, err := multimap.Filter(gtly.Lt("income", 1000))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 2, filtered.Size())
+	assert.Equal(t, 1, filtered.Slice("Warsaw").Size())
+	assert.Equal(t, 1, filtered.Slice("Krakow").Size())
+
+	found, err := multimap.Find(gtly.Eq("id", 4))
+	if assert.Nil(t, err) && assert.NotNil(t, found) {
+		assert.Equal(t, 2000.0, found.Value("income"))
+	}
+	objects, err := multimap.FindAll(gtly.Eq("active", true))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(objects))
+}
+
+func TestMultimap_SortedSlices(t *testing.T) {
+	provider := newPredicateProvider(t)
+	multimap := provider.NewMultimap(gtly.NewKeyProvider("city"))
+	_ = newPredicateArray(t, provider).Objects(func(item *gtly.Object) (bool, error) {
+		multimap.AddObject(item)
+		return true, nil
+	})
+	var keys []interface{}
+	var sizes []int
+	err := multimap.SortedSlices(func(key interface{}, value *gtly.Array) (bool, error) {
+		keys = append(keys, key)
+		sizes = append(sizes, value.Size())
+		return true, value.Sort(gtly.Desc("id"))
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []interface{}{nil, "Krakow", "Warsaw"}, keys)
+	assert.EqualValues(t, []int{1, 1, 2}, sizes)
+	assert.Equal(t, 3, multimap.Slice("Warsaw").First().Value("id"))
+}
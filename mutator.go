@@ -8,17 +8,30 @@ import (
 
 //Mutator represents object mutator
 type Mutator struct {
-	index int
+	index    int
+	provider *Provider
 	*xunsafe.Field
 }
 
-func (m *Mutator) init(index int, field *xunsafe.Field) {
+func (m *Mutator) init(index int, field *xunsafe.Field, provider *Provider) {
 	m.index = index
 	m.Field = field
+	m.provider = provider
 }
 
-//SetValue sets value
+//SetValue sets value, nested object and array values are converted with the field item provider.
+//Value that can not be converted to a nested object or array is ignored, use Object.Set to get a conversion error
 func (m *Mutator) SetValue(object *Object, value interface{}) {
+	switch m.Field.Type {
+	case typeObject, typeArray:
+		nested, err := m.convert(value)
+		if err != nil {
+			return
+		}
+		m.Field.SetValue(object.addr, nested)
+		object.markFieldSet(m.index)
+		return
+	}
 	switch m.Field.Type.Kind() {
 	case reflect.Ptr, reflect.Slice, reflect.Struct, reflect.Func, reflect.Interface, reflect.Map:
 		m.Field.SetValue(object.addr, value)
@@ -126,3 +139,14 @@ func (m *Mutator) Bytes(object *Object, value []byte) {
 	m.Field.SetBytes(object.addr, value)
 	object.markFieldSet(m.index)
 }
+
+//convert returns value converted to nested object or array with the field item provider, other values are returned as is
+func (m *Mutator) convert(value interface{}) (interface{}, error) {
+	switch m.Field.Type {
+	case typeObject:
+		return nestedObject(m.provider, value)
+	case typeArray:
+		return nestedArray(m.provider, value)
+	}
+	return value, nil
+}
@@ -0,0 +1,79 @@
+package gtly
+
+import (
+	"fmt"
+	"github.com/viant/toolbox"
+	"reflect"
+)
+
+//nestedObject returns an object for supplied value, map and struct values are converted with the provider
+func nestedObject(provider *Provider, value interface{}) (*Object, error) {
+	switch actual := Value(value).(type) {
+	case nil:
+		return nil, nil
+	case *Object:
+		if provider != nil && actual.proto != provider.Proto {
+			return nil, fmt.Errorf("unable to use %v object as %v object", actual.proto.Name, provider.Proto.Name)
+		}
+		return actual, nil
+	case map[string]interface{}:
+		if provider == nil {
+			return nil, fmt.Errorf("unable to convert %T to object: provider was nil", value)
+		}
+		return provider.Object(actual)
+	}
+	if provider == nil || !(toolbox.IsMap(value) || toolbox.IsStruct(value)) {
+		return nil, fmt.Errorf("unable to convert %T to object", value)
+	}
+	if reflect.TypeOf(value) == provider.dataType {
+		result := provider.NewObject()
+		result.value.Elem().Set(reflect.ValueOf(value))
+		for i := range result.setAt {
+			result.setAt[i] = true
+		}
+		return result, nil
+	}
+	values, err := toolbox.ToMap(value)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Object(values)
+}
+
+//nestedArray returns an array for supplied value, slice items are converted with the provider
+func nestedArray(provider *Provider, value interface{}) (*Array, error) {
+	switch actual := Value(value).(type) {
+	case nil:
+		return nil, nil
+	case *Array:
+		if provider != nil && actual.Proto() != provider.Proto {
+			return nil, fmt.Errorf("unable to use %v array as %v array", actual.Proto().Name, provider.Proto.Name)
+		}
+		return actual, nil
+	case Collection:
+		if provider != nil && actual.Proto() != provider.Proto {
+			return nil, fmt.Errorf("unable to use %v collection as %v array", actual.Proto().Name, provider.Proto.Name)
+		}
+		result := &Array{_provider: &Provider{Proto: actual.Proto()}}
+		err := actual.Objects(func(item *Object) (bool, error) {
+			result.AddObject(item)
+			return true, nil
+		})
+		return result, err
+	}
+	if provider == nil || !toolbox.IsSlice(value) {
+		return nil, fmt.Errorf("unable to convert %T to array", value)
+	}
+	items := reflect.ValueOf(value)
+	result := provider.NewArray()
+	for i := 0; i < items.Len(); i++ {
+		item, err := nestedObject(provider, items.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		if item != nil {
+			result.AddObject(item)
+		}
+	}
+	return result, nil
+}
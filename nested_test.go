@@ -0,0 +1,246 @@
+package gtly_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"reflect"
+	"testing"
+)
+
+func TestObject_SetValue_Nested(t *testing.T) {
+	addressProvider, err := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("zip", gtly.FieldTypeInt),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	provider, err := gtly.NewProvider("person",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(addressProvider)),
+		gtly.NewField("addresses", gtly.FieldTypeArray, gtly.ProviderOpt(addressProvider)),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	home := addressProvider.NewObject()
+	home.SetValue("city", "Warsaw")
+
+	var testCases = []struct {
+		description string
+		field       string
+		value       interface{}
+		expect      interface{}
+	}{
+		{
+			description: "object from map",
+			field:       "address",
+			value:       map[string]interface{}{"city": "Cracow", "zip": 30},
+			expect:      map[string]interface{}{"city": "Cracow", "zip": 30},
+		},
+		{
+			description: "object from object",
+			field:       "address",
+			value:       home,
+			expect:      map[string]interface{}{"city": "Warsaw"},
+		},
+		{
+			description: "array from slice",
+			field:       "addresses",
+			value:       []interface{}{map[string]interface{}{"city": "Cracow"}, home},
+			expect:      []map[string]interface{}{{"city": "Cracow"}, {"city": "Warsaw"}},
+		},
+		{
+			description: "array from collection",
+			field:       "addresses",
+			value:       addressProvider.NewArray(home),
+			expect:      []map[string]interface{}{{"city": "Warsaw"}},
+		},
+		{
+			description: "nil value",
+			field:       "address",
+			value:       nil,
+			expect:      nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		object := provider.NewObject()
+		object.SetValue(testCase.field, testCase.value)
+		actual := object.AsMap()[testCase.field]
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+}
+
+func TestObject_Value_NestedView(t *testing.T) {
+	addressProvider, err := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	provider, err := gtly.NewProvider("person",
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(addressProvider)),
+		gtly.NewField("addresses", gtly.FieldTypeArray, gtly.ProviderOpt(addressProvider)),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	object := provider.NewObject()
+	assert.Nil(t, object.Value("address"))
+	object.SetValue("address", map[string]interface{}{})
+	object.SetValue("addresses", []interface{}{})
+
+	address, ok := object.Value("address").(*gtly.Object)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, addressProvider.Proto, address.Proto())
+	assert.False(t, address.SetAt(0))
+	address.SetValue("city", "Warsaw")
+	assert.True(t, address.SetAt(0))
+	assert.EqualValues(t, map[string]interface{}{"address": map[string]interface{}{"city": "Warsaw"}, "addresses": []map[string]interface{}{}}, object.AsMap())
+
+	addresses := provider.Accessor("addresses").Array(object)
+	addresses.AddObject(address)
+	assert.EqualValues(t, 1, object.Value("addresses").(*gtly.Array).Size())
+
+	JSON, err := object.MarshalJSON()
+	assert.Nil(t, err)
+	assert.EqualValues(t, `{"address":{"city":"Warsaw"},"addresses":[{"city":"Warsaw"}]}`, string(JSON))
+
+	decoded := provider.NewObject()
+	assert.Nil(t, decoded.UnmarshalJSON(JSON))
+	assert.EqualValues(t, object.AsMap(), decoded.AsMap())
+}
+
+func TestJoin_GroupBy_Nested(t *testing.T) {
+	addressProvider, err := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	userProvider, err := gtly.NewProvider("user",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(addressProvider)),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	orderProvider, err := gtly.NewProvider("order",
+		gtly.NewField("userId", gtly.FieldTypeInt),
+		gtly.NewField("addresses", gtly.FieldTypeArray, gtly.ProviderOpt(addressProvider)),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	users, orders := userProvider.NewArray(), orderProvider.NewArray()
+	assert.Nil(t, users.Add(map[string]interface{}{"id": 1, "address": map[string]interface{}{"city": "Warsaw"}}))
+	assert.Nil(t, orders.Add(map[string]interface{}{"userId": 1, "addresses": []interface{}{map[string]interface{}{"city": "Cracow"}}}))
+
+	joined, err := gtly.Join(users, orders, gtly.InnerJoin, []string{"id"}, gtly.JoinRightKeysOpt("userId"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	grouped, err := gtly.GroupBy(joined, []string{"address"}, gtly.First("addresses"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, array := range []*gtly.Array{joined, grouped} {
+		for _, name := range []string{"address", "addresses", "firstAddresses"} {
+			if field := array.Proto().Field(name); field != nil {
+				assert.NotNil(t, field.ItemProvider(), name)
+			}
+		}
+		data, err := array.First().MarshalJSON()
+		if !assert.Nil(t, err) {
+			continue
+		}
+		actual := (&gtly.Provider{Proto: array.Proto()}).NewObject()
+		if assert.Nil(t, actual.UnmarshalJSON(data), string(data)) {
+			assert.EqualValues(t, array.First().AsMap(), actual.AsMap(), string(data))
+		}
+	}
+}
+
+func TestObject_UnmarshalJSON_NestedWithoutProvider(t *testing.T) {
+	provider, err := gtly.NewProvider("user",
+		&gtly.Field{Name: "address", Type: reflect.TypeOf(&gtly.Object{})},
+		&gtly.Field{Name: "addresses", Type: reflect.TypeOf(&gtly.Array{})},
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, data := range []string{`{"address":{"city":"Warsaw"}}`, `{"addresses":[{"city":"Warsaw"}]}`} {
+		object := provider.NewObject()
+		assert.NotNil(t, object.UnmarshalJSON([]byte(data)), data)
+		assert.True(t, object.IsNil(), data)
+	}
+}
+
+func TestObject_Set_NestedError(t *testing.T) {
+	addressProvider, err := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	otherProvider, err := gtly.NewProvider("other",
+		gtly.NewField("id", gtly.FieldTypeInt),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	provider, err := gtly.NewProvider("person",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(addressProvider)),
+		gtly.NewField("addresses", gtly.FieldTypeArray, gtly.ProviderOpt(addressProvider)),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var testCases = []struct {
+		description string
+		values      interface{}
+		expectError string
+	}{
+		{
+			description: "nested object invalid value",
+			values:      []interface{}{1, "Warsaw"},
+			expectError: "invalid field address: unable to convert string to object",
+		},
+		{
+			description: "nested array item invalid value",
+			values:      map[string]interface{}{"id": 1, "addresses": []interface{}{"Main"}},
+			expectError: "invalid field addresses: unable to convert string to object",
+		},
+		{
+			description: "nested object other proto",
+			values:      map[string]interface{}{"id": 1, "address": otherProvider.NewObject()},
+			expectError: "invalid field address: unable to use other object as address object",
+		},
+		{
+			description: "nested array other proto",
+			values:      map[string]interface{}{"id": 1, "addresses": otherProvider.NewArray(otherProvider.NewObject())},
+			expectError: "invalid field addresses: unable to use other array as address array",
+		},
+	}
+
+	for _, testCase := range testCases {
+		object := provider.NewObject()
+		err := object.Set(testCase.values)
+		if assert.NotNil(t, err, testCase.description) {
+			assert.EqualValues(t, testCase.expectError, err.Error(), testCase.description)
+		}
+		assert.True(t, object.IsNil(), testCase.description)
+		_, err = provider.Object(testCase.values)
+		assert.NotNil(t, err, testCase.description)
+	}
+
+	object, err := provider.Object(map[string]interface{}{"id": 1, "address": map[string]interface{}{"city": "Warsaw", "street": "Main"}})
+	if assert.Nil(t, err, "nested object unknown key shall be skipped") {
+		assert.EqualValues(t, map[string]interface{}{"city": "Warsaw"}, object.Value("address").(*gtly.Object).AsMap())
+	}
+}
@@ -2,6 +2,7 @@ package gtly
 
 import (
 	"fmt"
+	"github.com/pkg/errors"
 	"reflect"
 	"unsafe"
 )
@@ -14,17 +15,42 @@ type Object struct {
 	value reflect.Value
 }
 
-//Set sets a value from a map of a slice (slice index has to match field index)
+//Set sets a value from a map of a slice (slice index has to match field index),
+//unknown keys are skipped, object is not modified if any nested object or array value can not be converted
 func (o *Object) Set(val interface{}) error {
 	switch actual := val.(type) {
 	case map[string]interface{}:
+		mutators := make(map[string]*Mutator, len(actual))
+		values := make(map[string]interface{}, len(actual))
 		for k, v := range actual {
-			o.proto.Mutator(k).SetValue(o, v)
+			mutator := o.proto.Mutator(k)
+			if mutator == nil {
+				continue
+			}
+			value, err := mutator.convert(v)
+			if err != nil {
+				return errors.Wrapf(err, "invalid field %v", k)
+			}
+			mutators[k], values[k] = mutator, value
+		}
+		for k, mutator := range mutators {
+			mutator.SetValue(o, values[k])
 		}
 		return nil
 	case []interface{}:
+		if len(actual) > len(o.proto.mutators) {
+			return fmt.Errorf("too many values: %v, expected up to %v", len(actual), len(o.proto.mutators))
+		}
+		values := make([]interface{}, len(actual))
 		for k, v := range actual {
-			o.proto.MutatorAt(k).SetValue(o, v)
+			value, err := o.proto.MutatorAt(k).convert(v)
+			if err != nil {
+				return errors.Wrapf(err, "invalid field %v", o.proto.fields[k].Name)
+			}
+			values[k] = value
+		}
+		for k, value := range values {
+			o.proto.MutatorAt(k).SetValue(o, value)
 		}
 		return nil
 	}
@@ -44,12 +70,15 @@ func (o *Object) Addr() unsafe.Pointer {
 
 //Interface returns a struct value
 func (o *Object) Interface() interface{} {
-	return o.proto.xType.Interface(o.addr)
+	return reflect.NewAt(o.proto.dataType, o.addr).Elem().Interface()
 }
 
-//SetValue sets fieldValues
+//SetValue sets fieldValues, unknown field is ignored, use Provider.AddField to extend proto
 func (o *Object) SetValue(fieldName string, value interface{}) {
 	field := o.proto.Field(fieldName)
+	if field == nil {
+		return
+	}
 	o.proto.mutators[field.Index].SetValue(o, value)
 }
 
@@ -61,7 +90,7 @@ func (o *Object) SetValueAt(fieldIndex int, value interface{}) {
 //Value get value for supplied filed name
 func (o *Object) Value(fieldName string) interface{} {
 	field := o.proto.Field(fieldName)
-	if field.Index >= len(o.setAt) || !o.setAt[field.Index] {
+	if field == nil || field.Index >= len(o.setAt) || !o.setAt[field.Index] {
 		return nil
 	}
 	return o.proto.accessors[field.Index].Value(o)
@@ -75,6 +104,25 @@ func (o *Object) ValueAt(fieldIndex int) (interface{}, bool) {
 	return o.proto.accessors[fieldIndex].Value(o), true
 }
 
+//ValueByPath returns value for dotted path or JSON Pointer, nil is returned for unset value or invalid path
+func (o *Object) ValueByPath(path string) interface{} {
+	aPath, err := o.proto.Path(path)
+	if err != nil {
+		return nil
+	}
+	value, _ := aPath.Value(o)
+	return value
+}
+
+//SetValueByPath sets value for dotted path or JSON Pointer, missing nested objects are created, use Proto.Path to reuse compiled path
+func (o *Object) SetValueByPath(path string, value interface{}) error {
+	aPath, err := o.proto.Path(path)
+	if err != nil {
+		return err
+	}
+	return aPath.SetValue(o, value)
+}
+
 //SetAt returns true if value was set at given index
 func (o *Object) SetAt(index int) bool {
 	if index >= len(o.setAt) {
@@ -83,10 +131,25 @@ func (o *Object) SetAt(index int) bool {
 	return o.setAt[index]
 }
 
+//MarkSetAt marks field at given index as set, use it after writing a value straight into Addr based storage
+func (o *Object) MarkSetAt(index int) {
+	o.markFieldSet(index)
+}
+
 func (o *Object) markFieldSet(index int) {
 	o.setAt[index] = true
 }
 
+//unsetAt resets field value to zero value and marks it as unset
+func (o *Object) unsetAt(index int) {
+	if index >= len(o.setAt) {
+		return
+	}
+	field := o.proto.accessors[index].Field
+	reflect.NewAt(field.Type, field.Pointer(o.addr)).Elem().Set(reflect.Zero(field.Type))
+	o.setAt[index] = false
+}
+
 //IsNil returns true if object is nil
 func (o *Object) IsNil() bool {
 	for _, v := range o.setAt {
@@ -111,6 +174,12 @@ func (o *Object) AsMap() map[string]interface{} {
 			continue
 		}
 		value := o.proto.accessors[i].Value(o)
+		switch actual := value.(type) {
+		case *Object:
+			value = actual.AsMap()
+		case *Array:
+			value = actual.asMaps()
+		}
 		result[outputName] = value
 	}
 	return result
@@ -125,7 +194,7 @@ func (o *Object) FieldOutputName(field *Field) string {
 	return outputName
 }
 
-//Field returns Field by name
+//Field returns Field by name, or nil if Field does not exist
 func (o *Object) Field(name string) *Field {
 	return o.proto.Field(name)
 }
@@ -30,6 +30,13 @@ func OmitEmptyOpt(omitEmpty bool) Option {
 	}
 }
 
+//OutputNameOpt returns a Field output name option, use it to keep a source name that is not a valid Go identifier
+func OutputNameOpt(name string) Option {
+	return func(field *Field) {
+		field.outputName = name
+	}
+}
+
 //ComponentTypeOpt return a Field component type option
 func ComponentTypeOpt(componentType string) Option {
 	return func(field *Field) {
@@ -92,7 +99,7 @@ func ValueOpt(value interface{}) (Option, error) {
 			return func(field *Field) {
 				provider.Name = field.Name
 				field.provider = provider
-				field.Type = provider.dataType
+				field.DataType = FieldTypeObject
 			}, nil
 		}
 
@@ -115,7 +122,8 @@ func ValueOpt(value interface{}) (Option, error) {
 				return nil, err
 			}
 			return func(field *Field) {
-				field.Type = provider.Type()
+				provider.Name = field.Name
+				field.provider = provider
 				field.DataType = FieldTypeArray
 			}, nil
 		}
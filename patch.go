@@ -0,0 +1,407 @@
+package gtly
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/viant/toolbox"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	//PatchOpAdd JSON Patch add operation
+	PatchOpAdd = "add"
+	//PatchOpRemove JSON Patch remove operation
+	PatchOpRemove = "remove"
+	//PatchOpReplace JSON Patch replace operation
+	PatchOpReplace = "replace"
+	//PatchOpMove JSON Patch move operation
+	PatchOpMove = "move"
+	//PatchOpCopy JSON Patch copy operation
+	PatchOpCopy = "copy"
+	//PatchOpTest JSON Patch test operation
+	PatchOpTest = "test"
+)
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+//PatchOperation represents JSON Patch (RFC 6902) operation, null or NilValue value unsets a field
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+//MarshalJSON encodes operation, value is always encoded for add, replace and test operations
+func (o *PatchOperation) MarshalJSON() ([]byte, error) {
+	switch o.Op {
+	case PatchOpAdd, PatchOpReplace, PatchOpTest:
+		return json.Marshal(&struct {
+			Op    string      `json:"op"`
+			Path  string      `json:"path"`
+			Value interface{} `json:"value"`
+		}{o.Op, o.Path, Value(o.Value)})
+	}
+	type operation PatchOperation
+	return json.Marshal((*operation)(o))
+}
+
+//Patch represents JSON Patch (RFC 6902) document
+type Patch []*PatchOperation
+
+//NewPatch decodes JSON Patch document
+func NewPatch(data []byte) (Patch, error) {
+	var result Patch
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, errors.Wrap(err, "failed to decode JSON patch")
+	}
+	return result, nil
+}
+
+//ApplyPatch applies JSON Patch (RFC 6902) operations in order to an object copy, the object is updated only if all operations succeed.
+//Nested objects and arrays are replaced with patched copies
+func (o *Object) ApplyPatch(patch Patch) error {
+	patched := o.patchCopy()
+	for _, operation := range patch {
+		if err := patched.applyOperation(operation); err != nil {
+			return errors.Wrapf(err, "failed to apply %v %v", operation.Op, operation.Path)
+		}
+	}
+	o.patchCommit(patched)
+	return nil
+}
+
+//ApplyMergePatch applies JSON Merge Patch (RFC 7386) supplied as JSON or a map, null or NilValue unsets a field.
+//The object is updated only if the whole patch is merged, nested objects and arrays are replaced with patched copies
+func (o *Object) ApplyMergePatch(patch interface{}) error {
+	var values map[string]interface{}
+	switch actual := patch.(type) {
+	case map[string]interface{}:
+		values = actual
+	case []byte, string:
+		data, ok := actual.([]byte)
+		if !ok {
+			data = []byte(actual.(string))
+		}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&values); err != nil {
+			return errors.Wrap(err, "failed to decode JSON merge patch")
+		}
+	default:
+		return errors.Errorf("unsupported merge patch type: %T", patch)
+	}
+	patched := o.patchCopy()
+	if err := patched.mergePatch(values); err != nil {
+		return err
+	}
+	o.patchCommit(patched)
+	return nil
+}
+
+//patchCopy returns object copy, nested objects and arrays are copied, so that patching the copy does not modify the object
+func (o *Object) patchCopy() *Object {
+	result := (&Provider{Proto: o.proto}).NewObject()
+	result.value.Elem().Set(reflect.NewAt(o.proto.dataType, o.addr).Elem())
+	copy(result.setAt, o.setAt)
+	for i := range result.setAt {
+		if !result.setAt[i] {
+			continue
+		}
+		switch value := o.proto.accessors[i].Value(o).(type) {
+		case *Object:
+			if value != nil {
+				o.proto.mutators[i].SetValue(result, value.patchCopy())
+			}
+		case *Array:
+			if value != nil {
+				array := &Array{_provider: value._provider, _data: make([]*Object, len(value._data))}
+				for j, item := range value._data {
+					if item != nil {
+						array._data[j] = item.patchCopy()
+					}
+				}
+				o.proto.mutators[i].SetValue(result, array)
+			}
+		}
+	}
+	return result
+}
+
+//patchCommit replaces object values with patched copy values
+func (o *Object) patchCommit(patched *Object) {
+	reflect.NewAt(o.proto.dataType, o.addr).Elem().Set(patched.value.Elem())
+	copy(o.setAt, patched.setAt)
+}
+
+func (o *Object) mergePatch(values map[string]interface{}) error {
+	for key, value := range values {
+		field := o.proto.Field(key)
+		if field == nil {
+			return errors.Errorf("unknown field: %v", key)
+		}
+		if Value(value) == nil {
+			o.unsetAt(field.Index)
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok && field.Type == typeObject && field.itemProvider != nil {
+			target := o.proto.accessors[field.Index].Object(o)
+			if target == nil || !o.SetAt(field.Index) {
+				target = field.itemProvider.NewObject()
+				o.proto.mutators[field.Index].SetValue(o, target)
+			}
+			if err := target.mergePatch(nested); err != nil {
+				return errors.Wrapf(err, "failed to merge %v", key)
+			}
+			continue
+		}
+		converted, err := patchValue(field, value)
+		if err != nil {
+			return errors.Wrapf(err, "failed to merge %v", key)
+		}
+		o.proto.mutators[field.Index].SetValue(o, converted)
+	}
+	return nil
+}
+
+func (o *Object) applyOperation(operation *PatchOperation) error {
+	path, err := o.proto.Path(operation.Path)
+	if err != nil {
+		return err
+	}
+	switch operation.Op {
+	case PatchOpAdd:
+		return o.patchAdd(path, operation.Value, true)
+	case PatchOpRemove:
+		return o.patchRemove(path)
+	case PatchOpReplace:
+		if _, ok := path.Value(o); !ok {
+			return errors.New("path does not exist")
+		}
+		return o.patchAdd(path, operation.Value, false)
+	case PatchOpMove, PatchOpCopy:
+		from, err := o.proto.Path(operation.From)
+		if err != nil {
+			return err
+		}
+		value, ok := from.Value(o)
+		if !ok {
+			return errors.Errorf("from path %v does not exist", operation.From)
+		}
+		value = patchExport(from.leaf().field, value)
+		if operation.Op == PatchOpMove {
+			if err = o.patchRemove(from); err != nil {
+				return err
+			}
+		}
+		return o.patchAdd(path, value, true)
+	case PatchOpTest:
+		actual, ok := path.Value(o)
+		if !ok {
+			return errors.New("path does not exist")
+		}
+		expect, err := path.leaf().patchValue(operation.Value)
+		if err != nil {
+			return err
+		}
+		if !patchEqual(actual, expect) {
+			return errors.Errorf("test failed: expected %v, but had %v", operation.Value, patchExport(path.leaf().field, actual))
+		}
+		return nil
+	}
+	return errors.Errorf("unsupported patch operation: %v", operation.Op)
+}
+
+//patchAdd sets value at path, insert is used to add an array item instead of replacing it
+func (o *Object) patchAdd(path *Path, value interface{}, insert bool) error {
+	parent, err := path.parent(o, true)
+	if err != nil {
+		return err
+	}
+	leaf := path.leaf()
+	if !leaf.indexed && Value(value) == nil {
+		parent.unsetAt(leaf.field.Index)
+		return nil
+	}
+	converted, err := leaf.patchValue(value)
+	if err != nil {
+		return err
+	}
+	if !leaf.indexed {
+		return leaf.setField(parent, converted)
+	}
+	array, err := leaf.array(parent, true)
+	if err != nil {
+		return err
+	}
+	item, _ := converted.(*Object)
+	if item == nil {
+		return errors.New("array item was nil")
+	}
+	if !insert || leaf.index == appendIndex || leaf.index >= array.Size() {
+		return leaf.setItem(array, item)
+	}
+	array._data = append(array._data, nil)
+	copy(array._data[leaf.index+1:], array._data[leaf.index:])
+	array._data[leaf.index] = item
+	return nil
+}
+
+func (o *Object) patchRemove(path *Path) error {
+	parent, err := path.parent(o, false)
+	if err != nil {
+		return err
+	}
+	if _, ok := path.Value(o); !ok {
+		return errors.New("path does not exist")
+	}
+	leaf := path.leaf()
+	if !leaf.indexed {
+		parent.unsetAt(leaf.field.Index)
+		return nil
+	}
+	array := leaf.accessor.Array(parent)
+	array._data = append(array._data[:leaf.index], array._data[leaf.index+1:]...)
+	return nil
+}
+
+//patchValue converts patch value to field value or array item
+func (s *pathStep) patchValue(value interface{}) (interface{}, error) {
+	if s.indexed {
+		return patchObject(s.field.itemProvider, value)
+	}
+	return patchValue(s.field, value)
+}
+
+//patchValue converts JSON patch value to field type
+func patchValue(field *Field, value interface{}) (interface{}, error) {
+	value = Value(value)
+	switch field.Type {
+	case typeObject:
+		return patchObject(field.itemProvider, value)
+	case typeArray:
+		if !toolbox.IsSlice(value) || field.itemProvider == nil {
+			return nestedArray(field.itemProvider, value)
+		}
+		items := reflect.ValueOf(value)
+		result := field.itemProvider.NewArray()
+		for i := 0; i < items.Len(); i++ {
+			item, err := patchObject(field.itemProvider, items.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			if item != nil {
+				result.AddObject(item)
+			}
+		}
+		return result, nil
+	case typeTime, typeTimePtr:
+		if text, ok := value.(string); ok {
+			timeValue, err := toolbox.ToTime(text, field.TimeLayout())
+			if err != nil || field.Type == typeTimePtr {
+				return timeValue, err
+			}
+			return *timeValue, nil
+		}
+	}
+	if value == nil || reflect.TypeOf(value) == field.Type {
+		return value, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	target := reflect.New(field.Type)
+	if err = json.Unmarshal(data, target.Interface()); err != nil {
+		return nil, errors.Wrapf(err, "unable to convert %T to %v", value, field.Type)
+	}
+	return target.Elem().Interface(), nil
+}
+
+//patchObject converts patch map value into a new object, other values are converted with nestedObject
+func patchObject(provider *Provider, value interface{}) (*Object, error) {
+	values, ok := value.(map[string]interface{})
+	if !ok || provider == nil {
+		return nestedObject(provider, value)
+	}
+	result := provider.NewObject()
+	return result, result.mergePatch(values)
+}
+
+//patchExport returns detached JSON friendly value
+func patchExport(field *Field, value interface{}) interface{} {
+	switch actual := value.(type) {
+	case *Object:
+		return actual.AsMap()
+	case *Array:
+		return actual.asMaps()
+	case time.Time:
+		return actual.Format(field.TimeLayout())
+	case *time.Time:
+		if actual == nil {
+			return nil
+		}
+		return actual.Format(field.TimeLayout())
+	}
+	return value
+}
+
+func patchEqual(x, y interface{}) bool {
+	switch actual := x.(type) {
+	case time.Time:
+		expect, ok := y.(time.Time)
+		return ok && actual.Equal(expect)
+	case *time.Time:
+		expect, ok := y.(*time.Time)
+		return ok && (actual == expect || (actual != nil && expect != nil && actual.Equal(*expect)))
+	case *Object:
+		expect, ok := y.(*Object)
+		return ok && (actual == expect || (actual != nil && expect != nil && reflect.DeepEqual(actual.AsMap(), expect.AsMap())))
+	case *Array:
+		expect, ok := y.(*Array)
+		return ok && (actual == expect || (actual != nil && expect != nil && reflect.DeepEqual(actual.asMaps(), expect.asMaps())))
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+//Diff returns JSON Patch (RFC 6902) transforming a into b, both objects have to share the same proto
+func Diff(a, b *Object) (Patch, error) {
+	if a.proto != b.proto {
+		return nil, errors.Errorf("unable to diff %v with %v: incompatible protos", a.proto.Name, b.proto.Name)
+	}
+	return diffObjects(a, b, "", nil), nil
+}
+
+func diffObjects(a, b *Object, prefix string, patch Patch) Patch {
+	for i := range a.proto.fields {
+		field := &a.proto.fields[i]
+		path := prefix + "/" + pointerEscaper.Replace(field.OutputName())
+		aValue, aSet := a.ValueAt(field.Index)
+		bValue, bSet := b.ValueAt(field.Index)
+		switch {
+		case !aSet && !bSet:
+			continue
+		case !bSet:
+			patch = append(patch, &PatchOperation{Op: PatchOpRemove, Path: path})
+			continue
+		case !aSet:
+			patch = append(patch, &PatchOperation{Op: PatchOpAdd, Path: path, Value: patchExport(field, bValue)})
+			continue
+		}
+		aObject, _ := aValue.(*Object)
+		bObject, _ := bValue.(*Object)
+		if aObject != nil && bObject != nil && aObject.proto == bObject.proto {
+			patch = diffObjects(aObject, bObject, path, patch)
+			continue
+		}
+		if !patchEqual(aValue, bValue) {
+			patch = append(patch, &PatchOperation{Op: PatchOpReplace, Path: path, Value: patchExport(field, bValue)})
+		}
+	}
+	return patch
+}
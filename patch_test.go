@@ -0,0 +1,305 @@
+package gtly_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"github.com/viant/toolbox/format"
+	"testing"
+	"time"
+)
+
+func newPatchTestProvider(t *testing.T) *gtly.Provider {
+	addressProvider, err := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("zip", gtly.FieldTypeInt),
+	)
+	assert.Nil(t, err)
+	provider, err := gtly.NewProvider("user",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+		gtly.NewField("score", gtly.FieldTypeFloat64),
+		gtly.NewField("updated", gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02")),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(addressProvider)),
+		gtly.NewField("addresses", gtly.FieldTypeArray, gtly.ProviderOpt(addressProvider)),
+	)
+	assert.Nil(t, err)
+	return provider
+}
+
+func newPatchTestObject(t *testing.T, provider *gtly.Provider) *gtly.Object {
+	object, err := provider.Object(map[string]interface{}{
+		"id":      1,
+		"name":    "Adam",
+		"address": map[string]interface{}{"city": "Warsaw", "zip": 10},
+		"addresses": []interface{}{
+			map[string]interface{}{"city": "Cracow"},
+			map[string]interface{}{"city": "Poznan"},
+		},
+	})
+	assert.Nil(t, err)
+	return object
+}
+
+func TestObject_ApplyMergePatch(t *testing.T) {
+	provider := newPatchTestProvider(t)
+
+	var testCases = []struct {
+		description string
+		patch       interface{}
+		expect      map[string]interface{}
+		expectError bool
+	}{
+		{
+			description: "JSON merge patch",
+			patch:       `{"name":null,"score":1.5,"updated":"2021-03-04","address":{"zip":null,"city":"Cracow"}}`,
+			expect: map[string]interface{}{
+				"id":        1,
+				"score":     1.5,
+				"updated":   time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+				"address":   map[string]interface{}{"city": "Cracow"},
+				"addresses": []map[string]interface{}{{"city": "Cracow"}, {"city": "Poznan"}},
+			},
+		},
+		{
+			description: "map merge patch with nil value",
+			patch:       map[string]interface{}{"id": 2, "addresses": gtly.NilValue, "address": nil},
+			expect:      map[string]interface{}{"id": 2, "name": "Adam"},
+		},
+		{
+			description: "array is replaced",
+			patch:       []byte(`{"addresses":[{"zip":3}]}`),
+			expect: map[string]interface{}{
+				"id":        1,
+				"name":      "Adam",
+				"address":   map[string]interface{}{"city": "Warsaw", "zip": 10},
+				"addresses": []map[string]interface{}{{"zip": 3}},
+			},
+		},
+		{
+			description: "unknown field",
+			patch:       `{"email":"a@b.c"}`,
+			expectError: true,
+		},
+		{
+			description: "unknown field after valid changes",
+			patch:       `{"name":"Bob","address":{"city":"Lodz"},"addresses":[{"zip":3}],"email":"a@b.c"}`,
+			expectError: true,
+		},
+		{
+			description: "unknown nested field",
+			patch:       `{"address":{"city":"Lodz","street":"Main"}}`,
+			expectError: true,
+		},
+		{
+			description: "invalid value type",
+			patch:       `{"id":"abc"}`,
+			expectError: true,
+		},
+		{
+			description: "not an object",
+			patch:       `[1]`,
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		object := newPatchTestObject(t, provider)
+		err := object.ApplyMergePatch(testCase.patch)
+		if testCase.expectError {
+			assert.NotNil(t, err, testCase.description)
+			assert.EqualValues(t, newPatchTestObject(t, provider).AsMap(), object.AsMap(), testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, object.AsMap(), testCase.description)
+	}
+}
+
+func TestObject_ApplyPatch(t *testing.T) {
+	provider := newPatchTestProvider(t)
+
+	var testCases = []struct {
+		description string
+		patch       string
+		expect      map[string]interface{}
+		expectError bool
+	}{
+		{
+			description: "add, replace and remove",
+			patch: `[
+				{"op":"add","path":"/score","value":2},
+				{"op":"replace","path":"/address/city","value":"Gdansk"},
+				{"op":"remove","path":"/name"},
+				{"op":"add","path":"/addresses/0","value":{"city":"Lodz"}},
+				{"op":"remove","path":"/addresses/2"}
+			]`,
+			expect: map[string]interface{}{
+				"id":        1,
+				"score":     2.0,
+				"address":   map[string]interface{}{"city": "Gdansk", "zip": 10},
+				"addresses": []map[string]interface{}{{"city": "Lodz"}, {"city": "Cracow"}},
+			},
+		},
+		{
+			description: "move, copy and test",
+			patch: `[
+				{"op":"test","path":"/address/zip","value":10},
+				{"op":"move","from":"/addresses/1","path":"/addresses/0"},
+				{"op":"copy","from":"/addresses/0","path":"/address"},
+				{"op":"test","path":"/address","value":{"city":"Poznan"}}
+			]`,
+			expect: map[string]interface{}{
+				"id":        1,
+				"name":      "Adam",
+				"address":   map[string]interface{}{"city": "Poznan"},
+				"addresses": []map[string]interface{}{{"city": "Poznan"}, {"city": "Cracow"}},
+			},
+		},
+		{
+			description: "add null value unsets field",
+			patch:       `[{"op":"add","path":"/name","value":null},{"op":"add","path":"/addresses/-","value":{"zip":1}}]`,
+			expect: map[string]interface{}{
+				"id":        1,
+				"address":   map[string]interface{}{"city": "Warsaw", "zip": 10},
+				"addresses": []map[string]interface{}{{"city": "Cracow"}, {"city": "Poznan"}, {"zip": 1}},
+			},
+		},
+		{
+			description: "failed test",
+			patch:       `[{"op":"test","path":"/name","value":"Bob"}]`,
+			expectError: true,
+		},
+		{
+			description: "failed test after replace",
+			patch:       `[{"op":"replace","path":"/name","value":"Bob"},{"op":"test","path":"/id","value":2}]`,
+			expectError: true,
+		},
+		{
+			description: "failed test after nested changes",
+			patch: `[
+				{"op":"replace","path":"/address/city","value":"Lodz"},
+				{"op":"remove","path":"/addresses/0"},
+				{"op":"add","path":"/addresses/0/zip","value":5},
+				{"op":"test","path":"/id","value":2}
+			]`,
+			expectError: true,
+		},
+		{
+			description: "replace unset field",
+			patch:       `[{"op":"replace","path":"/score","value":1}]`,
+			expectError: true,
+		},
+		{
+			description: "remove missing array item",
+			patch:       `[{"op":"remove","path":"/addresses/5"}]`,
+			expectError: true,
+		},
+		{
+			description: "unsupported operation",
+			patch:       `[{"op":"merge","path":"/name","value":"Bob"}]`,
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		object := newPatchTestObject(t, provider)
+		patch, err := gtly.NewPatch([]byte(testCase.patch))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		err = object.ApplyPatch(patch)
+		if testCase.expectError {
+			assert.NotNil(t, err, testCase.description)
+			assert.EqualValues(t, newPatchTestObject(t, provider).AsMap(), object.AsMap(), testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, object.AsMap(), testCase.description)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	provider := newPatchTestProvider(t)
+	from := newPatchTestObject(t, provider)
+	to := newPatchTestObject(t, provider)
+	to.SetValue("name", "Bob")
+	to.SetValue("updated", time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, to.SetValueByPath("address.zip", 20))
+	assert.Nil(t, to.SetValueByPath("addresses[2].city", "Lodz"))
+	assert.Nil(t, to.ApplyMergePatch(map[string]interface{}{"id": nil}))
+
+	patch, err := gtly.Diff(from, to)
+	if !assert.Nil(t, err) {
+		return
+	}
+	JSON, err := json.Marshal(patch)
+	assert.Nil(t, err)
+	assert.EqualValues(t, `[{"op":"remove","path":"/id"},{"op":"replace","path":"/name","value":"Bob"},{"op":"add","path":"/updated","value":"2021-03-04"},{"op":"replace","path":"/address/zip","value":20},{"op":"replace","path":"/addresses","value":[{"city":"Cracow"},{"city":"Poznan"},{"city":"Lodz"}]}]`, string(JSON))
+
+	decoded, err := gtly.NewPatch(JSON)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, from.ApplyPatch(decoded))
+	assert.EqualValues(t, to.AsMap(), from.AsMap())
+
+	patch, err = gtly.Diff(from, to)
+	assert.Nil(t, err)
+	assert.Empty(t, patch)
+
+	other, err := gtly.NewProvider("other", gtly.NewField("id", gtly.FieldTypeInt))
+	assert.Nil(t, err)
+	_, err = gtly.Diff(from, other.NewObject())
+	assert.NotNil(t, err)
+}
+
+func TestDiff_OutputCaseFormat(t *testing.T) {
+	addressProvider, err := gtly.NewProvider("address",
+		gtly.NewField("cityName", gtly.FieldTypeString),
+	)
+	assert.Nil(t, err)
+	provider, err := gtly.NewProvider("user",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("firstName", gtly.FieldTypeString),
+		gtly.NewField("homeAddress", gtly.FieldTypeObject, gtly.ProviderOpt(addressProvider)),
+		gtly.NewField("pastAddresses", gtly.FieldTypeArray, gtly.ProviderOpt(addressProvider)),
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, addressProvider.OutputCaseFormat(format.CaseLowerCamel, format.CaseLowerUnderscore))
+	assert.Nil(t, provider.OutputCaseFormat(format.CaseLowerCamel, format.CaseLowerUnderscore))
+
+	from, err := provider.Object(map[string]interface{}{
+		"id":            1,
+		"firstName":     "Adam",
+		"homeAddress":   map[string]interface{}{"cityName": "Warsaw"},
+		"pastAddresses": []interface{}{map[string]interface{}{"cityName": "Cracow"}},
+	})
+	assert.Nil(t, err)
+	to, err := provider.Object(map[string]interface{}{
+		"id":            1,
+		"firstName":     "Bob",
+		"homeAddress":   map[string]interface{}{"cityName": "Lodz"},
+		"pastAddresses": []interface{}{map[string]interface{}{"cityName": "Cracow"}, map[string]interface{}{"cityName": "Poznan"}},
+	})
+	assert.Nil(t, err)
+
+	patch, err := gtly.Diff(from, to)
+	if !assert.Nil(t, err) {
+		return
+	}
+	JSON, err := json.Marshal(patch)
+	assert.Nil(t, err)
+	assert.EqualValues(t, `[{"op":"replace","path":"/first_name","value":"Bob"},{"op":"replace","path":"/home_address/city_name","value":"Lodz"},{"op":"replace","path":"/past_addresses","value":[{"city_name":"Cracow"},{"city_name":"Poznan"}]}]`, string(JSON))
+
+	decoded, err := gtly.NewPatch(JSON)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, from.ApplyPatch(decoded))
+	assert.EqualValues(t, to.AsMap(), from.AsMap())
+}
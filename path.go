@@ -0,0 +1,273 @@
+package gtly
+
+import (
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+//appendIndex represents JSON Pointer "-" array index, it refers to the position past the last array item
+const appendIndex = -1
+
+//Path represents a dotted path or JSON Pointer compiled against a proto, it can be reused with any object of that proto
+type Path struct {
+	expr  string
+	steps []*pathStep
+}
+
+//pathStep represents a single field step, indexed steps refer to an array item
+type pathStep struct {
+	proto    *Proto
+	field    *Field
+	accessor *Accessor
+	mutator  *Mutator
+	indexed  bool
+	index    int
+}
+
+//String returns path expression
+func (p *Path) String() string {
+	return p.expr
+}
+
+//Value returns value at path, false is returned if any path element was not set
+func (p *Path) Value(object *Object) (interface{}, bool) {
+	parent, err := p.parent(object, false)
+	if err != nil || parent == nil {
+		return nil, false
+	}
+	return p.leaf().value(parent)
+}
+
+//SetValue sets value at path, missing nested objects and arrays are created with the nested field provider
+func (p *Path) SetValue(object *Object, value interface{}) error {
+	parent, err := p.parent(object, true)
+	if err == nil {
+		err = p.leaf().set(parent, value)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to set %v", p.expr)
+	}
+	return nil
+}
+
+func (p *Path) leaf() *pathStep {
+	return p.steps[len(p.steps)-1]
+}
+
+//parent returns an object holding the path leaf, nil is returned for missing element unless create is true
+func (p *Path) parent(object *Object, create bool) (*Object, error) {
+	last := len(p.steps) - 1
+	for _, step := range p.steps[:last] {
+		if err := step.check(object); err != nil {
+			return nil, err
+		}
+		next, err := step.object(object, create)
+		if err != nil || next == nil {
+			return nil, err
+		}
+		object = next
+	}
+	return object, p.steps[last].check(object)
+}
+
+func (s *pathStep) check(object *Object) error {
+	if object == nil || object.proto != s.proto {
+		return errors.Errorf("incompatible %v object", s.proto.Name)
+	}
+	return nil
+}
+
+//object returns nested object or array item, missing one is created if create is true
+func (s *pathStep) object(object *Object, create bool) (*Object, error) {
+	provider := s.field.itemProvider
+	if !s.indexed {
+		nested := s.accessor.Object(object)
+		if (nested == nil || !object.SetAt(s.field.Index)) && create {
+			nested = provider.NewObject()
+			s.mutator.SetValue(object, nested)
+		}
+		return nested, nil
+	}
+	array, err := s.array(object, create)
+	if err != nil || array == nil {
+		return nil, err
+	}
+	if s.index >= 0 && s.index < array.Size() {
+		return array._data[s.index], nil
+	}
+	if !create {
+		return nil, nil
+	}
+	item := provider.NewObject()
+	return item, s.setItem(array, item)
+}
+
+func (s *pathStep) array(object *Object, create bool) (*Array, error) {
+	provider := s.field.itemProvider
+	array := s.accessor.Array(object)
+	if array == nil || !object.SetAt(s.field.Index) {
+		if !create {
+			return nil, nil
+		}
+		array = provider.NewArray()
+		s.mutator.SetValue(object, array)
+	}
+	if array._provider == nil || array.Proto() != provider.Proto {
+		return nil, errors.Errorf("incompatible %v array", s.field.Name)
+	}
+	return array, nil
+}
+
+//value returns field value or array item
+func (s *pathStep) value(object *Object) (interface{}, bool) {
+	if !object.SetAt(s.field.Index) {
+		return nil, false
+	}
+	value := s.accessor.Value(object)
+	if !s.indexed {
+		return value, true
+	}
+	array, _ := value.(*Array)
+	if array == nil || s.index < 0 || s.index >= array.Size() {
+		return nil, false
+	}
+	return array._data[s.index], true
+}
+
+//set sets field value or replaces array item
+func (s *pathStep) set(object *Object, value interface{}) error {
+	if !s.indexed {
+		return s.setField(object, value)
+	}
+	array, err := s.array(object, true)
+	if err != nil {
+		return err
+	}
+	item, err := s.item(value)
+	if err != nil {
+		return err
+	}
+	return s.setItem(array, item)
+}
+
+func (s *pathStep) setField(object *Object, value interface{}) error {
+	var err error
+	switch s.field.Type {
+	case typeObject:
+		value, err = nestedObject(s.field.itemProvider, value)
+	case typeArray:
+		value, err = nestedArray(s.field.itemProvider, value)
+	}
+	if err != nil {
+		return err
+	}
+	s.mutator.SetValue(object, value)
+	return nil
+}
+
+func (s *pathStep) item(value interface{}) (*Object, error) {
+	item, err := nestedObject(s.field.itemProvider, value)
+	if err == nil && item == nil {
+		err = errors.New("array item was nil")
+	}
+	return item, err
+}
+
+//setItem replaces array item at index, index equal to array size or appendIndex appends item
+func (s *pathStep) setItem(array *Array, item *Object) error {
+	switch {
+	case s.index == appendIndex || s.index == array.Size():
+		array.AddObject(item)
+	case s.index >= 0 && s.index < array.Size():
+		array._data[s.index] = item
+	default:
+		return errors.Errorf("index %v out of range [0:%v]", s.index, array.Size())
+	}
+	return nil
+}
+
+//Path compiles dotted path (i.e. "items[2].price") or JSON Pointer (i.e. "/items/2/price"), compile a path once to reuse it in a loop
+func (p *Proto) Path(expr string) (*Path, error) {
+	tokens, err := pathTokens(expr)
+	if err != nil {
+		return nil, err
+	}
+	result := &Path{expr: expr}
+	proto := p
+	for i := 0; i < len(tokens); i++ {
+		if proto == nil {
+			return nil, errors.Errorf("invalid path %v: %v is not an object field", expr, tokens[i-1])
+		}
+		field := proto.Field(tokens[i])
+		if field == nil {
+			return nil, errors.Errorf("invalid path %v: unknown field %v", expr, tokens[i])
+		}
+		step := &pathStep{
+			proto:    proto,
+			field:    field,
+			accessor: &proto.accessors[field.Index],
+			mutator:  &proto.mutators[field.Index],
+		}
+		proto = nil
+		if provider := field.itemProvider; provider != nil {
+			switch field.Type {
+			case typeObject:
+				proto = provider.Proto
+			case typeArray:
+				if i+1 < len(tokens) {
+					i++
+					if step.index, err = pathIndex(tokens[i]); err != nil {
+						return nil, errors.Wrapf(err, "invalid path %v", expr)
+					}
+					step.indexed = true
+					proto = provider.Proto
+				}
+			}
+		}
+		result.steps = append(result.steps, step)
+	}
+	return result, nil
+}
+
+//pathTokens splits JSON Pointer or dotted path into field names and array indexes
+func pathTokens(expr string) ([]string, error) {
+	if strings.HasPrefix(expr, "/") {
+		tokens := strings.Split(expr[1:], "/")
+		for i, token := range tokens {
+			tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		}
+		return tokens, nil
+	}
+	if expr == "" {
+		return nil, errors.New("invalid path: path was empty")
+	}
+	var tokens []string
+	for _, element := range strings.Split(expr, ".") {
+		position := strings.Index(element, "[")
+		if position == -1 {
+			position = len(element)
+		} else if !strings.HasSuffix(element, "]") || position+2 >= len(element) {
+			return nil, errors.Errorf("invalid path %v: invalid index in %v", expr, element)
+		}
+		if position == 0 {
+			return nil, errors.Errorf("invalid path %v: empty field name", expr)
+		}
+		tokens = append(tokens, element[:position])
+		if position < len(element) {
+			tokens = append(tokens, element[position+1:len(element)-1])
+		}
+	}
+	return tokens, nil
+}
+
+func pathIndex(token string) (int, error) {
+	if token == "-" {
+		return appendIndex, nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, errors.Errorf("invalid array index: %v", token)
+	}
+	return index, nil
+}
@@ -0,0 +1,175 @@
+package gtly_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"testing"
+)
+
+func newPathTestProvider(t *testing.T) *gtly.Provider {
+	itemProvider, err := gtly.NewProvider("item",
+		gtly.NewField("name", gtly.FieldTypeString),
+		gtly.NewField("price", gtly.FieldTypeFloat64),
+	)
+	assert.Nil(t, err)
+	addressProvider, err := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("zip", gtly.FieldTypeInt),
+	)
+	assert.Nil(t, err)
+	provider, err := gtly.NewProvider("order",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(addressProvider)),
+		gtly.NewField("items", gtly.FieldTypeArray, gtly.ProviderOpt(itemProvider)),
+	)
+	assert.Nil(t, err)
+	return provider
+}
+
+func TestObject_ValueByPath(t *testing.T) {
+	provider := newPathTestProvider(t)
+	object, err := provider.Object(map[string]interface{}{
+		"id":      1,
+		"address": map[string]interface{}{"city": "Warsaw", "zip": 2},
+		"items": []interface{}{
+			map[string]interface{}{"name": "a", "price": 1.5},
+			map[string]interface{}{"name": "b"},
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var testCases = []struct {
+		description string
+		path        string
+		expect      interface{}
+	}{
+		{description: "top level field", path: "id", expect: 1},
+		{description: "dotted path", path: "address.city", expect: "Warsaw"},
+		{description: "dotted index path", path: "items[0].price", expect: 1.5},
+		{description: "json pointer", path: "/items/1/name", expect: "b"},
+		{description: "json pointer nested field", path: "/address/zip", expect: 2},
+		{description: "unset nested field", path: "items[1].price", expect: nil},
+		{description: "index out of range", path: "items[2].name", expect: nil},
+		{description: "unknown field", path: "address.street", expect: nil},
+		{description: "invalid index", path: "/items/x/name", expect: nil},
+		{description: "not an object field", path: "id.value", expect: nil},
+	}
+
+	for _, testCase := range testCases {
+		actual := object.ValueByPath(testCase.path)
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+
+	item, ok := object.ValueByPath("items[1]").(*gtly.Object)
+	if assert.True(t, ok) {
+		assert.EqualValues(t, "b", item.Value("name"))
+	}
+}
+
+func TestObject_SetValueByPath(t *testing.T) {
+	provider := newPathTestProvider(t)
+
+	var testCases = []struct {
+		description string
+		paths       []string
+		values      []interface{}
+		expect      map[string]interface{}
+		expectError bool
+	}{
+		{
+			description: "create missing nested object",
+			paths:       []string{"address.city"},
+			values:      []interface{}{"Warsaw"},
+			expect:      map[string]interface{}{"address": map[string]interface{}{"city": "Warsaw"}},
+		},
+		{
+			description: "create missing array items",
+			paths:       []string{"items[0].name", "/items/1/price", "items[0].price"},
+			values:      []interface{}{"a", 2.5, 1.5},
+			expect: map[string]interface{}{"items": []map[string]interface{}{
+				{"name": "a", "price": 1.5},
+				{"price": 2.5},
+			}},
+		},
+		{
+			description: "append and replace array item",
+			paths:       []string{"/items/-", "/items/-", "items[0]"},
+			values: []interface{}{
+				map[string]interface{}{"name": "a"},
+				map[string]interface{}{"name": "b"},
+				map[string]interface{}{"name": "c"},
+			},
+			expect: map[string]interface{}{"items": []map[string]interface{}{
+				{"name": "c"},
+				{"name": "b"},
+			}},
+		},
+		{
+			description: "set nested object",
+			paths:       []string{"/address"},
+			values:      []interface{}{map[string]interface{}{"city": "Cracow"}},
+			expect:      map[string]interface{}{"address": map[string]interface{}{"city": "Cracow"}},
+		},
+		{
+			description: "index out of range",
+			paths:       []string{"items[1].name"},
+			values:      []interface{}{"a"},
+			expectError: true,
+		},
+		{
+			description: "unknown field",
+			paths:       []string{"address.street"},
+			values:      []interface{}{1},
+			expectError: true,
+		},
+		{
+			description: "invalid object value",
+			paths:       []string{"address"},
+			values:      []interface{}{1},
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		object := provider.NewObject()
+		var err error
+		for i, path := range testCase.paths {
+			if err = object.SetValueByPath(path, testCase.values[i]); err != nil {
+				break
+			}
+		}
+		if testCase.expectError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, object.AsMap(), testCase.description)
+	}
+}
+
+func TestProto_Path(t *testing.T) {
+	provider := newPathTestProvider(t)
+	path, err := provider.Proto.Path("items[0].price")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, "items[0].price", path.String())
+	objects := []*gtly.Object{provider.NewObject(), provider.NewObject()}
+	for i, object := range objects {
+		assert.Nil(t, path.SetValue(object, float64(i)))
+	}
+	for i, object := range objects {
+		value, ok := path.Value(object)
+		assert.True(t, ok)
+		assert.EqualValues(t, float64(i), value)
+	}
+
+	for _, expr := range []string{"", "items[", "items[].price", "[0]", "/items/01/price", "id.value"} {
+		_, err = provider.Proto.Path(expr)
+		assert.NotNil(t, err, expr)
+	}
+}
@@ -0,0 +1,346 @@
+package gtly
+
+import (
+	"github.com/pkg/errors"
+	"github.com/viant/gtly/internal/compare"
+	"github.com/viant/toolbox"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+//Matcher represents a predicate compiled for a proto
+type Matcher func(object *Object) bool
+
+//Predicate represents object predicate, it is compiled once per collection proto, so that matching uses precompiled accessors
+type Predicate func(proto *Proto) (Matcher, error)
+
+//comparison returns field value comparison result with expected value, false if field is null or not comparable
+type comparison func(object *Object) (int, bool)
+
+//Eq returns predicate matching objects which field value is equal to supplied value, nil value matches null field
+func Eq(fieldName string, value interface{}) Predicate {
+	if Value(value) == nil {
+		return IsNull(fieldName)
+	}
+	return comparePredicate(fieldName, value, func(result int) bool {
+		return result == 0
+	})
+}
+
+//Gt returns predicate matching objects which field value is greater than supplied value
+func Gt(fieldName string, value interface{}) Predicate {
+	return comparePredicate(fieldName, value, func(result int) bool {
+		return result > 0
+	})
+}
+
+//Ge returns predicate matching objects which field value is greater or equal to supplied value
+func Ge(fieldName string, value interface{}) Predicate {
+	return comparePredicate(fieldName, value, func(result int) bool {
+		return result >= 0
+	})
+}
+
+//Lt returns predicate matching objects which field value is less than supplied value
+func Lt(fieldName string, value interface{}) Predicate {
+	return comparePredicate(fieldName, value, func(result int) bool {
+		return result < 0
+	})
+}
+
+//Le returns predicate matching objects which field value is less or equal to supplied value
+func Le(fieldName string, value interface{}) Predicate {
+	return comparePredicate(fieldName, value, func(result int) bool {
+		return result <= 0
+	})
+}
+
+//In returns predicate matching objects which field value is equal to any of supplied values
+func In(fieldName string, values ...interface{}) Predicate {
+	return func(proto *Proto) (Matcher, error) {
+		field, accessor, err := lookupAccessor(proto, fieldName)
+		if err != nil {
+			return nil, err
+		}
+		index := field.Index
+		switch accessor.Type {
+		case typeString:
+			expect := make(map[string]bool, len(values))
+			for _, value := range values {
+				expect[toolbox.AsString(value)] = true
+			}
+			return func(object *Object) bool {
+				return object.SetAt(index) && expect[accessor.String(object)]
+			}, nil
+		case typeInt, typeInt64:
+			expect := make(map[int64]bool, len(values))
+			for _, value := range values {
+				if _, ok := fraction(value); ok { //int field value is never equal to a number with a fractional part
+					continue
+				}
+				converted, err := toolbox.ToInt(value)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid %v value", fieldName)
+				}
+				expect[int64(converted)] = true
+			}
+			if accessor.Type == typeInt {
+				return func(object *Object) bool {
+					return object.SetAt(index) && expect[int64(accessor.Int(object))]
+				}, nil
+			}
+			return func(object *Object) bool {
+				return object.SetAt(index) && expect[accessor.Int64(object)]
+			}, nil
+		}
+		predicates := make([]Predicate, len(values))
+		for i, value := range values {
+			predicates[i] = Eq(fieldName, value)
+		}
+		return Or(predicates...)(proto)
+	}
+}
+
+//Like returns predicate matching objects which field text matches SQL like pattern, % matches any sequence, _ matches a single character
+func Like(fieldName string, pattern string) Predicate {
+	return func(proto *Proto) (Matcher, error) {
+		field, accessor, err := lookupAccessor(proto, fieldName)
+		if err != nil {
+			return nil, err
+		}
+		expr, err := compare.Like(pattern)
+		if err != nil {
+			return nil, err
+		}
+		index := field.Index
+		if accessor.Type == typeString {
+			return func(object *Object) bool {
+				return object.SetAt(index) && expr.MatchString(accessor.String(object))
+			}, nil
+		}
+		return func(object *Object) bool {
+			value := fieldValue(object, index)
+			return value != nil && expr.MatchString(toolbox.AsString(value))
+		}, nil
+	}
+}
+
+//IsNull returns predicate matching objects which field is not set or nil
+func IsNull(fieldName string) Predicate {
+	return func(proto *Proto) (Matcher, error) {
+		field, _, err := lookupAccessor(proto, fieldName)
+		if err != nil {
+			return nil, err
+		}
+		index := field.Index
+		return func(object *Object) bool {
+			return fieldValue(object, index) == nil
+		}, nil
+	}
+}
+
+//And returns predicate matching objects matched by all supplied predicates
+func And(predicates ...Predicate) Predicate {
+	return func(proto *Proto) (Matcher, error) {
+		matchers, err := compileAll(proto, predicates)
+		if err != nil {
+			return nil, err
+		}
+		return func(object *Object) bool {
+			for _, matcher := range matchers {
+				if !matcher(object) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	}
+}
+
+//Or returns predicate matching objects matched by any of supplied predicates
+func Or(predicates ...Predicate) Predicate {
+	return func(proto *Proto) (Matcher, error) {
+		matchers, err := compileAll(proto, predicates)
+		if err != nil {
+			return nil, err
+		}
+		return func(object *Object) bool {
+			for _, matcher := range matchers {
+				if matcher(object) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+}
+
+//Not returns predicate negating supplied predicate
+func Not(predicate Predicate) Predicate {
+	return func(proto *Proto) (Matcher, error) {
+		matcher, err := predicate(proto)
+		if err != nil {
+			return nil, err
+		}
+		return func(object *Object) bool {
+			return !matcher(object)
+		}, nil
+	}
+}
+
+func compileAll(proto *Proto, predicates []Predicate) ([]Matcher, error) {
+	var result = make([]Matcher, len(predicates))
+	for i, predicate := range predicates {
+		matcher, err := predicate(proto)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = matcher
+	}
+	return result, nil
+}
+
+func comparePredicate(fieldName string, value interface{}, accept func(result int) bool) Predicate {
+	return func(proto *Proto) (Matcher, error) {
+		compared, err := newComparison(proto, fieldName, value)
+		if err != nil {
+			return nil, err
+		}
+		return func(object *Object) bool {
+			result, ok := compared(object)
+			return ok && accept(result)
+		}, nil
+	}
+}
+
+//fraction returns value as float64 if value is a number with a fractional part
+func fraction(value interface{}) (float64, bool) {
+	number, err := toolbox.ToFloat(value)
+	if err != nil || number == math.Trunc(number) {
+		return 0, false
+	}
+	return number, true
+}
+
+func lookupAccessor(proto *Proto, fieldName string) (*Field, *Accessor, error) {
+	field := proto.Lookup(fieldName)
+	if field == nil {
+		return nil, nil, errors.Errorf("unknown field: %v", fieldName)
+	}
+	return field, proto.AccessorAt(field.Index), nil
+}
+
+//newComparison creates a comparison using typed accessor for base types
+func newComparison(proto *Proto, fieldName string, value interface{}) (comparison, error) {
+	field, accessor, err := lookupAccessor(proto, fieldName)
+	if err != nil {
+		return nil, err
+	}
+	index := field.Index
+	switch accessor.Type {
+	case typeInt, typeInt64:
+		if expect, ok := fraction(value); ok {
+			if accessor.Type == typeInt {
+				return func(object *Object) (int, bool) {
+					return compare.Float(float64(accessor.Int(object)), expect), object.SetAt(index)
+				}, nil
+			}
+			return func(object *Object) (int, bool) {
+				return compare.Float(float64(accessor.Int64(object)), expect), object.SetAt(index)
+			}, nil
+		}
+		expect, err := toolbox.ToInt(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %v value", fieldName)
+		}
+		if accessor.Type == typeInt {
+			return func(object *Object) (int, bool) {
+				return compare.Int(int64(accessor.Int(object)), int64(expect)), object.SetAt(index)
+			}, nil
+		}
+		return func(object *Object) (int, bool) {
+			return compare.Int(accessor.Int64(object), int64(expect)), object.SetAt(index)
+		}, nil
+	case typeFloat, typeFloat64:
+		expect, err := toolbox.ToFloat(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %v value", fieldName)
+		}
+		if accessor.Type == typeFloat {
+			return func(object *Object) (int, bool) {
+				return compare.Float(float64(accessor.Float32(object)), expect), object.SetAt(index)
+			}, nil
+		}
+		return func(object *Object) (int, bool) {
+			return compare.Float(accessor.Float64(object), expect), object.SetAt(index)
+		}, nil
+	case typeString:
+		expect := toolbox.AsString(value)
+		return func(object *Object) (int, bool) {
+			return strings.Compare(accessor.String(object), expect), object.SetAt(index)
+		}, nil
+	case typeBool:
+		expect, err := toolbox.ToBoolean(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %v value", fieldName)
+		}
+		return func(object *Object) (int, bool) {
+			return compare.Bool(accessor.Bool(object), expect), object.SetAt(index)
+		}, nil
+	case typeTime:
+		expect, err := toolbox.ToTime(value, field.TimeLayout())
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %v value", fieldName)
+		}
+		return func(object *Object) (int, bool) {
+			return compare.Time(accessor.Time(object), *expect), object.SetAt(index)
+		}, nil
+	}
+	timeLayout := field.TimeLayout()
+	return func(object *Object) (int, bool) {
+		return compareValues(fieldValue(object, index), value, timeLayout)
+	}, nil
+}
+
+//fieldValue returns dereferenced field value or nil if field is not set or nil
+func fieldValue(object *Object, index int) interface{} {
+	value, ok := object.ValueAt(index)
+	if !ok || Value(value) == nil {
+		return nil
+	}
+	reflectValue := reflect.ValueOf(value)
+	for reflectValue.Kind() == reflect.Ptr || reflectValue.Kind() == reflect.Interface {
+		if reflectValue.IsNil() {
+			return nil
+		}
+		reflectValue = reflectValue.Elem()
+	}
+	return reflectValue.Interface()
+}
+
+//compareValues compares values of any type, returns false if values are not comparable
+func compareValues(actual, expect interface{}, timeLayout string) (int, bool) {
+	if actual == nil || Value(expect) == nil {
+		return 0, false
+	}
+	switch value := actual.(type) {
+	case string:
+		return strings.Compare(value, toolbox.AsString(expect)), true
+	case bool:
+		expected, err := toolbox.ToBoolean(expect)
+		return compare.Bool(value, expected), err == nil
+	case time.Time:
+		expected, err := toolbox.ToTime(expect, timeLayout)
+		if err != nil {
+			return 0, false
+		}
+		return compare.Time(value, *expected), true
+	}
+	if toolbox.IsNumber(actual) {
+		expected, err := toolbox.ToFloat(expect)
+		return compare.Float(toolbox.AsFloat(actual), expected), err == nil
+	}
+	return 0, reflect.DeepEqual(actual, expect)
+}
@@ -0,0 +1,169 @@
+package gtly_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"testing"
+	"time"
+)
+
+func newPredicateProvider(t *testing.T) *gtly.Provider {
+	provider, err := gtly.NewProvider("person",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("income", gtly.FieldTypeFloat64),
+		gtly.NewField("active", gtly.FieldTypeBool),
+		gtly.NewField("joined", gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02")),
+		gtly.NewField("code", gtly.FieldTypeInt64),
+	)
+	assert.Nil(t, err)
+	return provider
+}
+
+func newPredicateArray(t *testing.T, provider *gtly.Provider) *gtly.Array {
+	array := provider.NewArray()
+	for _, values := range []map[string]interface{}{
+		{"id": 1, "city": "Warsaw", "income": 1500.0, "active": true, "joined": time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), "code": int64(10)},
+		{"id": 2, "city": "Krakow", "income": 900.0, "active": false, "joined": time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"id": 3, "city": "Warsaw", "income": 800.0},
+		{"id": 4, "income": 2000.0, "active": true},
+	} {
+		assert.Nil(t, array.Add(values))
+	}
+	return array
+}
+
+func TestPredicate(t *testing.T) {
+	provider := newPredicateProvider(t)
+	array := newPredicateArray(t, provider)
+	testCases := []struct {
+		description string
+		predicate   gtly.Predicate
+		expectIDs   []int
+		expectError string
+	}{
+		{
+			description: "eq string",
+			predicate:   gtly.Eq("city", "Warsaw"),
+			expectIDs:   []int{1, 3},
+		},
+		{
+			description: "eq nil",
+			predicate:   gtly.Eq("city", nil),
+			expectIDs:   []int{4},
+		},
+		{
+			description: "gt float with int value",
+			predicate:   gtly.Gt("income", 1000),
+			expectIDs:   []int{1, 4},
+		},
+		{
+			description: "le int",
+			predicate:   gtly.Le("id", 2),
+			expectIDs:   []int{1, 2},
+		},
+		{
+			description: "ge time with text value",
+			predicate:   gtly.Ge("joined", "2020-06-01"),
+			expectIDs:   []int{2},
+		},
+		{
+			description: "lt int64",
+			predicate:   gtly.Lt("code", 11),
+			expectIDs:   []int{1},
+		},
+		{
+			description: "in string",
+			predicate:   gtly.In("city", "Krakow", "Gdansk"),
+			expectIDs:   []int{2},
+		},
+		{
+			description: "in int",
+			predicate:   gtly.In("id", 1, "4"),
+			expectIDs:   []int{1, 4},
+		},
+		{
+			description: "eq int with fractional value",
+			predicate:   gtly.Eq("id", 2.5),
+			expectIDs:   []int{},
+		},
+		{
+			description: "lt int with fractional value",
+			predicate:   gtly.Lt("id", 2.5),
+			expectIDs:   []int{1, 2},
+		},
+		{
+			description: "ge int64 with fractional value",
+			predicate:   gtly.Ge("code", 9.5),
+			expectIDs:   []int{1},
+		},
+		{
+			description: "in int with fractional value",
+			predicate:   gtly.In("id", 2.5, 3.0),
+			expectIDs:   []int{3},
+		},
+		{
+			description: "in float",
+			predicate:   gtly.In("income", 800, 900.0),
+			expectIDs:   []int{2, 3},
+		},
+		{
+			description: "like",
+			predicate:   gtly.Like("city", "W%s_w"),
+			expectIDs:   []int{1, 3},
+		},
+		{
+			description: "like non string field",
+			predicate:   gtly.Like("income", "1%"),
+			expectIDs:   []int{1},
+		},
+		{
+			description: "is null",
+			predicate:   gtly.IsNull("active"),
+			expectIDs:   []int{3},
+		},
+		{
+			description: "and",
+			predicate:   gtly.And(gtly.Eq("city", "Warsaw"), gtly.Eq("active", true)),
+			expectIDs:   []int{1},
+		},
+		{
+			description: "or",
+			predicate:   gtly.Or(gtly.Eq("id", 2), gtly.Gt("income", 1900)),
+			expectIDs:   []int{2, 4},
+		},
+		{
+			description: "not",
+			predicate:   gtly.Not(gtly.Eq("city", "Warsaw")),
+			expectIDs:   []int{2, 4},
+		},
+		{
+			description: "unknown field",
+			predicate:   gtly.And(gtly.Eq("id", 1), gtly.Eq("country", "PL")),
+			expectError: "unknown field: country",
+		},
+		{
+			description: "invalid value",
+			predicate:   gtly.Gt("income", "abc"),
+			expectError: "invalid income value",
+		},
+	}
+
+	for _, testCase := range testCases {
+		objects, err := array.FindAll(testCase.predicate)
+		if testCase.expectError != "" {
+			if assert.NotNil(t, err, testCase.description) {
+				assert.Contains(t, err.Error(), testCase.expectError, testCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var ids = make([]int, 0)
+		for _, object := range objects {
+			ids = append(ids, object.Value("id").(int))
+		}
+		assert.EqualValues(t, testCase.expectIDs, ids, testCase.description)
+	}
+}
@@ -10,7 +10,8 @@ import (
 )
 
 const (
-	defaultPackage = "github.com/viant/gtly"
+	defaultPackage    = "github.com/viant/gtly"
+	defaultTimeLayout = time.RFC3339
 )
 
 var defaultEmptyValues = map[interface{}]bool{
@@ -35,6 +36,7 @@ type Proto struct {
 	dataType         reflect.Type
 	xType            *xunsafe.Type
 	kind             reflect.Kind
+	version          int
 }
 
 //Type returns proto data type
@@ -72,9 +74,11 @@ func (p *Proto) initField(field *Field, dataType reflect.Type) {
 	if p.caseFormat != p.outputCaseFormat {
 		field.outputName = p.caseFormat.Format(field.Name, p.outputCaseFormat)
 	}
-
+	if p.caseFormat != p.inputCaseFormat {
+		p.indexInputName(field)
+	}
 	p.accessors[field.Index].init(field.Index, xField)
-	p.mutators[field.Index].init(field.Index, xField)
+	p.mutators[field.Index].init(field.Index, xField, field.itemProvider)
 	p.indexByNames(field)
 }
 
@@ -88,6 +92,11 @@ func (p *Proto) indexByNames(field *Field) {
 	}
 }
 
+//Version returns proto version, version is incremented with every provider schema change
+func (p *Proto) Version() int {
+	return p.version
+}
+
 //SimpleName returns simple name
 func (p *Proto) SimpleName() string {
 	if index := strings.LastIndex(p.Name, "."); index != -1 {
@@ -106,6 +115,16 @@ func (p *Proto) SetOmitEmpty(omitEmpty bool) {
 	}
 }
 
+//SetTimeLayout sets default time layout for fields without data layout
+func (p *Proto) SetTimeLayout(layout string) {
+	p.timeLayout = layout
+}
+
+//TimeLayout returns default time layout
+func (p *Proto) TimeLayout() string {
+	return p.timeLayout
+}
+
 //SetEmptyValues sets empty values, use only if empty values are non in default map: nil, empty string
 func (p *Proto) SetEmptyValues(values ...interface{}) {
 	p.emptyValues = make(map[interface{}]bool)
@@ -114,23 +133,43 @@ func (p *Proto) SetEmptyValues(values ...interface{}) {
 	}
 }
 
-//OutputCaseFormat set output case format
+//OutputCaseFormat set output case format, output names are indexed for Field lookup
 func (p *Proto) OutputCaseFormat(source, output format.Case) error {
-	for i, field := range p.fields {
-		p.fields[i].outputName = source.Format(field.Name, output)
+	if p.inputCaseFormat == p.caseFormat { //input case format was not set
+		p.inputCaseFormat = source
+	}
+	p.caseFormat = source
+	p.outputCaseFormat = output
+	for i := range p.fields {
+		field := &p.fields[i]
+		field.outputName = source.Format(field.Name, output)
+		if _, ok := p.fieldNames[field.outputName]; !ok {
+			p.fieldNames[field.outputName] = field.Index
+		}
 	}
 	return nil
 }
 
-//InputCaseFormat set output case format
+//InputCaseFormat set input case format, input names are indexed for Field lookup
 func (p *Proto) InputCaseFormat(source, input format.Case) error {
-	p.inputCaseFormat = source
-	for i, field := range p.fields {
-		p.fields[i].outputName = source.Format(field.Name, input)
+	if p.outputCaseFormat == p.caseFormat { //output case format was not set
+		p.outputCaseFormat = source
+	}
+	p.caseFormat = source
+	p.inputCaseFormat = input
+	for i := range p.fields {
+		p.indexInputName(&p.fields[i])
 	}
 	return nil
 }
 
+func (p *Proto) indexInputName(field *Field) {
+	inputName := p.caseFormat.Format(field.Name, p.inputCaseFormat)
+	if _, ok := p.fieldNames[inputName]; !ok {
+		p.fieldNames[inputName] = field.Index
+	}
+}
+
 //Hide set hidden flag for the Field
 func (p *Proto) Hide(name string) {
 	field := p.Field(name)
@@ -188,9 +227,17 @@ func (p *Proto) Fields() []Field {
 	return p.fields
 }
 
-//Field returns Field for specified Name
+//Field returns Field for specified Name, or nil if Field does not exist
 func (p *Proto) Field(name string) *Field {
-	index := p.fieldNames[name]
+	return p.Lookup(name)
+}
+
+//Lookup returns Field for specified Name, input name or output name, or nil if Field does not exist
+func (p *Proto) Lookup(name string) *Field {
+	index, ok := p.fieldNames[name]
+	if !ok {
+		return nil
+	}
 	return &p.fields[index]
 }
 
@@ -199,9 +246,12 @@ func (p *Proto) FieldAt(index int) *Field {
 	return &p.fields[index]
 }
 
-//Mutator returns field mutator
+//Mutator returns field mutator, or nil if Field does not exist
 func (p *Proto) Mutator(fieldName string) *Mutator {
 	field := p.Field(fieldName)
+	if field == nil {
+		return nil
+	}
 	return &p.mutators[field.Index]
 }
 
@@ -210,9 +260,12 @@ func (p *Proto) MutatorAt(index int) *Mutator {
 	return &p.mutators[index]
 }
 
-//Accessor returns a field accessor
+//Accessor returns a field accessor, or nil if Field does not exist
 func (p *Proto) Accessor(fieldName string) *Accessor {
 	field := p.Field(fieldName)
+	if field == nil {
+		return nil
+	}
 	return &p.accessors[field.Index]
 }
 
@@ -230,7 +283,7 @@ func newProto(name string, fields []*Field) *Proto {
 		accessors:  make([]Accessor, len(fields)),
 		mutators:   make([]Mutator, len(fields)),
 	}
-	result.timeLayout = time.RFC3339
+	result.timeLayout = defaultTimeLayout
 	for i := range fields {
 		result.fields[i] = *fields[i]
 	}
@@ -27,7 +27,7 @@ func (p *Provider) NewObject() *Object {
 //NewArray creates a slice
 func (p *Provider) NewArray(items ...*Object) *Array {
 	return &Array{
-		_provider: p,
+		_provider: p.pinned(),
 		_data:     items,
 	}
 }
@@ -42,7 +42,7 @@ func (p *Provider) Object(value interface{}) (*Object, error) {
 func (p *Provider) NewMap(keyProvider KeyProvider) *Map {
 	return &Map{
 		_map:        map[interface{}]*Object{},
-		_provider:   p,
+		_provider:   p.pinned(),
 		keyProvider: keyProvider,
 	}
 }
@@ -51,7 +51,7 @@ func (p *Provider) NewMap(keyProvider KeyProvider) *Map {
 func (p *Provider) NewMultimap(keyProvider KeyProvider) *Multimap {
 	return &Multimap{
 		_map:        map[interface{}][]*Object{},
-		_provider:   p,
+		_provider:   p.pinned(),
 		keyProvider: keyProvider,
 	}
 }
@@ -0,0 +1,163 @@
+package gtly
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly/internal/compare"
+	"github.com/viant/toolbox"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//SortKey represents sort key, null values are ordered last unless NullsFirst is set regardless of direction
+type SortKey struct {
+	Name       string
+	Descending bool
+	NullsFirst bool
+}
+
+//Asc returns ascending sort key
+func Asc(name string) SortKey {
+	return SortKey{Name: name}
+}
+
+//Desc returns descending sort key
+func Desc(name string) SortKey {
+	return SortKey{Name: name, Descending: true}
+}
+
+//objectComparator compares objects, returns negative value if x goes before y
+type objectComparator func(x, y *Object) int
+
+//newComparator creates comparator for supplied keys using typed accessors
+func newComparator(proto *Proto, keys []SortKey) (objectComparator, error) {
+	comparators := make([]objectComparator, len(keys))
+	for i, key := range keys {
+		field, accessor, err := lookupAccessor(proto, key.Name)
+		if err != nil {
+			return nil, err
+		}
+		comparators[i] = keyComparator(key, field, accessor)
+	}
+	return func(x, y *Object) int {
+		for _, comparator := range comparators {
+			if result := comparator(x, y); result != 0 {
+				return result
+			}
+		}
+		return 0
+	}, nil
+}
+
+func keyComparator(key SortKey, field *Field, accessor *Accessor) objectComparator {
+	index := field.Index
+	var compareSet objectComparator
+	switch accessor.Type {
+	case typeInt:
+		compareSet = func(x, y *Object) int {
+			return compare.Int(int64(accessor.Int(x)), int64(accessor.Int(y)))
+		}
+	case typeInt64:
+		compareSet = func(x, y *Object) int {
+			return compare.Int(accessor.Int64(x), accessor.Int64(y))
+		}
+	case typeFloat:
+		compareSet = func(x, y *Object) int {
+			return compare.Float(float64(accessor.Float32(x)), float64(accessor.Float32(y)))
+		}
+	case typeFloat64:
+		compareSet = func(x, y *Object) int {
+			return compare.Float(accessor.Float64(x), accessor.Float64(y))
+		}
+	case typeString:
+		compareSet = func(x, y *Object) int {
+			return strings.Compare(accessor.String(x), accessor.String(y))
+		}
+	case typeBool:
+		compareSet = func(x, y *Object) int {
+			return compare.Bool(accessor.Bool(x), accessor.Bool(y))
+		}
+	case typeTime:
+		compareSet = func(x, y *Object) int {
+			return compare.Time(accessor.Time(x), accessor.Time(y))
+		}
+	default:
+		timeLayout := field.TimeLayout()
+		compareSet = func(x, y *Object) int {
+			result, _ := compareValues(fieldValue(x, index), fieldValue(y, index), timeLayout)
+			return result
+		}
+	}
+	isNull := func(object *Object) bool {
+		return !object.SetAt(index)
+	}
+	if accessor.Type.Kind() == reflect.Ptr || accessor.Type.Kind() == reflect.Interface {
+		isNull = func(object *Object) bool {
+			return fieldValue(object, index) == nil
+		}
+	}
+	nullOrder := 1
+	if key.NullsFirst {
+		nullOrder = -1
+	}
+	return func(x, y *Object) int {
+		xNull, yNull := isNull(x), isNull(y)
+		switch {
+		case xNull && yNull:
+			return 0
+		case xNull:
+			return nullOrder
+		case yNull:
+			return -nullOrder
+		}
+		if key.Descending {
+			return compareSet(y, x)
+		}
+		return compareSet(x, y)
+	}
+}
+
+//sortedKeys returns map keys in ascending order, nil key goes first
+func sortedKeys(keys []interface{}) []interface{} {
+	sort.SliceStable(keys, func(i, j int) bool {
+		return compareKeys(keys[i], keys[j]) < 0
+	})
+	return keys
+}
+
+func compareKeys(x, y interface{}) int {
+	switch {
+	case x == nil && y == nil:
+		return 0
+	case x == nil:
+		return -1
+	case y == nil:
+		return 1
+	}
+	if toolbox.IsInt(x) && toolbox.IsInt(y) {
+		return compare.Int(int64(toolbox.AsInt(x)), int64(toolbox.AsInt(y)))
+	}
+	if result, ok := compareValues(x, y, ""); ok && reflect.TypeOf(x) == reflect.TypeOf(y) {
+		return result
+	}
+	if toolbox.IsNumber(x) && toolbox.IsNumber(y) {
+		return compare.Float(toolbox.AsFloat(x), toolbox.AsFloat(y))
+	}
+	return strings.Compare(fmt.Sprintf("%T%v", x, x), fmt.Sprintf("%T%v", y, y))
+}
+
+//Sort sorts array in place with a stable sort
+func (a *Array) Sort(keys ...SortKey) error {
+	if len(keys) == 0 {
+		return errors.New("sort keys were empty")
+	}
+	comparator, err := newComparator(a.Proto(), keys)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(a._data, func(i, j int) bool {
+		return comparator(a._data[i], a._data[j]) < 0
+	})
+	return nil
+}
@@ -0,0 +1,108 @@
+package gtly_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newSortArray(t *testing.T) *gtly.Array {
+	var nick *string
+	provider, err := gtly.NewProvider("person",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("income", gtly.FieldTypeFloat64),
+		gtly.NewField("active", gtly.FieldTypeBool),
+		gtly.NewField("joined", gtly.FieldTypeTime),
+		gtly.NewField("code", gtly.FieldTypeInt64),
+		&gtly.Field{Name: "nick", Type: reflect.TypeOf(nick)},
+	)
+	assert.Nil(t, err)
+	bob, al := "bob", "al"
+	array := provider.NewArray()
+	for _, values := range []map[string]interface{}{
+		{"id": 1, "city": "Warsaw", "income": 1500.0, "active": true, "joined": time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), "code": int64(10), "nick": &bob},
+		{"id": 2, "city": "Krakow", "income": 900.0, "active": false, "joined": time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), "nick": &bob},
+		{"id": 3, "city": "Warsaw", "income": 800.0, "code": int64(20), "nick": &al},
+		{"id": 4, "income": 2000.0, "active": true, "code": int64(5)},
+	} {
+		assert.Nil(t, array.Add(values))
+	}
+	return array
+}
+
+func TestArray_Sort(t *testing.T) {
+	testCases := []struct {
+		description string
+		keys        []gtly.SortKey
+		expectIDs   []int
+		expectError string
+	}{
+		{
+			description: "string asc, nulls last",
+			keys:        []gtly.SortKey{gtly.Asc("city")},
+			expectIDs:   []int{2, 1, 3, 4},
+		},
+		{
+			description: "string desc, nulls first",
+			keys:        []gtly.SortKey{{Name: "city", Descending: true, NullsFirst: true}},
+			expectIDs:   []int{4, 1, 3, 2},
+		},
+		{
+			description: "multi key",
+			keys:        []gtly.SortKey{gtly.Asc("city"), gtly.Asc("income")},
+			expectIDs:   []int{2, 3, 1, 4},
+		},
+		{
+			description: "float desc",
+			keys:        []gtly.SortKey{gtly.Desc("income")},
+			expectIDs:   []int{4, 1, 2, 3},
+		},
+		{
+			description: "bool then int64",
+			keys:        []gtly.SortKey{gtly.Asc("active"), gtly.Desc("code")},
+			expectIDs:   []int{2, 1, 4, 3},
+		},
+		{
+			description: "time desc",
+			keys:        []gtly.SortKey{gtly.Desc("joined")},
+			expectIDs:   []int{2, 1, 3, 4},
+		},
+		{
+			description: "pointer",
+			keys:        []gtly.SortKey{gtly.Asc("nick"), gtly.Asc("id")},
+			expectIDs:   []int{3, 1, 2, 4},
+		},
+		{
+			description: "unknown field",
+			keys:        []gtly.SortKey{gtly.Asc("country")},
+			expectError: "unknown field: country",
+		},
+		{
+			description: "no keys",
+			expectError: "sort keys were empty",
+		},
+	}
+
+	for _, testCase := range testCases {
+		array := newSortArray(t)
+		err := array.Sort(testCase.keys...)
+		if testCase.expectError != "" {
+			if assert.NotNil(t, err, testCase.description) {
+				assert.Contains(t, err.Error(), testCase.expectError, testCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var ids []int
+		_ = array.Objects(func(item *gtly.Object) (bool, error) {
+			ids = append(ids, item.Value("id").(int))
+			return true, nil
+		})
+		assert.EqualValues(t, testCase.expectIDs, ids, testCase.description)
+	}
+}
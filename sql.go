@@ -0,0 +1,90 @@
+package gtly
+
+import (
+	"database/sql/driver"
+	"github.com/pkg/errors"
+)
+
+//SQLObject represents an object stored in a JSON column, provider is used to create an object at scan time
+type SQLObject struct {
+	Provider *Provider
+	Object   *Object
+}
+
+//Scan decodes JSON column value into a new object, NULL sets nil object
+func (o *SQLObject) Scan(src interface{}) error {
+	data, err := jsonColumn(src)
+	if err != nil || data == nil {
+		o.Object = nil
+		return err
+	}
+	if o.Provider == nil {
+		return errors.New("failed to scan object: provider was nil")
+	}
+	object := o.Provider.NewObject()
+	if err = object.UnmarshalJSON(data); err != nil {
+		return errors.Wrapf(err, "failed to scan %v object", o.Provider.Name)
+	}
+	o.Object = object
+	return nil
+}
+
+//Value returns object JSON text, nil object returns NULL
+func (o SQLObject) Value() (driver.Value, error) {
+	if o.Object == nil {
+		return nil, nil
+	}
+	data, err := o.Object.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+//SQLArray represents an array stored in a JSON column, provider is used to create an array at scan time
+type SQLArray struct {
+	Provider *Provider
+	Array    *Array
+}
+
+//Scan decodes JSON column value into a new array, NULL sets nil array
+func (a *SQLArray) Scan(src interface{}) error {
+	data, err := jsonColumn(src)
+	if err != nil || data == nil {
+		a.Array = nil
+		return err
+	}
+	if a.Provider == nil {
+		return errors.New("failed to scan array: provider was nil")
+	}
+	array := a.Provider.NewArray()
+	if err = array.UnmarshalJSON(data); err != nil {
+		return errors.Wrapf(err, "failed to scan %v array", a.Provider.Name)
+	}
+	a.Array = array
+	return nil
+}
+
+//Value returns array JSON text, nil array returns NULL
+func (a SQLArray) Value() (driver.Value, error) {
+	if a.Array == nil {
+		return nil, nil
+	}
+	data, err := a.Array.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func jsonColumn(src interface{}) ([]byte, error) {
+	switch actual := src.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return actual, nil
+	case string:
+		return []byte(actual), nil
+	}
+	return nil, errors.Errorf("unsupported JSON column type: %T", src)
+}
@@ -0,0 +1,117 @@
+package gtly_test
+
+import (
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"github.com/viant/toolbox/format"
+	"testing"
+)
+
+func TestSQLObject(t *testing.T) {
+	provider, err := gtly.NewProvider("user",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	db, err := sql.Open("sqlite3", ":memory:")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec("CREATE TABLE doc(id INTEGER, user TEXT, users TEXT)")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var testCases = []struct {
+		description string
+		id          int
+		user        map[string]interface{}
+		users       []map[string]interface{}
+	}{
+		{
+			description: "object and array",
+			id:          1,
+			user:        map[string]interface{}{"id": 1, "name": "Bob"},
+			users:       []map[string]interface{}{{"id": 2, "name": "Ann"}, {"id": 3, "name": "Tom"}},
+		},
+		{
+			description: "null columns",
+			id:          2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		record := gtly.SQLObject{}
+		if testCase.user != nil {
+			record.Object = provider.NewObject()
+			assert.Nil(t, record.Object.Set(testCase.user), testCase.description)
+		}
+		records := gtly.SQLArray{}
+		if testCase.users != nil {
+			records.Array = provider.NewArray()
+			for _, user := range testCase.users {
+				assert.Nil(t, records.Array.Add(user), testCase.description)
+			}
+		}
+		_, err = db.Exec("INSERT INTO doc(id, user, users) VALUES(?, ?, ?)", testCase.id, record, records)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+
+		actual := &gtly.SQLObject{Provider: provider}
+		actuals := &gtly.SQLArray{Provider: provider}
+		err = db.QueryRow("SELECT user, users FROM doc WHERE id = ?", testCase.id).Scan(actual, actuals)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		if testCase.user == nil {
+			assert.Nil(t, actual.Object, testCase.description)
+			assert.Nil(t, actuals.Array, testCase.description)
+			continue
+		}
+		assert.EqualValues(t, testCase.user, actual.Object.AsMap(), testCase.description)
+		var users []map[string]interface{}
+		_ = actuals.Array.Objects(func(item *gtly.Object) (bool, error) {
+			users = append(users, item.AsMap())
+			return true, nil
+		})
+		assert.EqualValues(t, testCase.users, users, testCase.description)
+	}
+}
+
+func TestSQLObject_CaseFormat(t *testing.T) {
+	provider, err := gtly.NewProvider("user",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("firstName", gtly.FieldTypeString),
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, provider.OutputCaseFormat(format.CaseLowerCamel, format.CaseLowerUnderscore))
+	object, err := provider.Object(map[string]interface{}{"id": 1, "firstName": "Bob"})
+	if !assert.Nil(t, err) {
+		return
+	}
+	value, err := gtly.SQLObject{Object: object}.Value()
+	if !assert.Nil(t, err) {
+		return
+	}
+	actual := &gtly.SQLObject{Provider: provider}
+	if assert.Nil(t, actual.Scan(value)) {
+		assert.EqualValues(t, map[string]interface{}{"id": 1, "first_name": "Bob"}, actual.Object.AsMap())
+	}
+	values, err := gtly.SQLArray{Array: provider.NewArray(object)}.Value()
+	if !assert.Nil(t, err) {
+		return
+	}
+	actuals := &gtly.SQLArray{Provider: provider}
+	if assert.Nil(t, actuals.Scan(values)) && assert.Equal(t, 1, actuals.Array.Size()) {
+		assert.Equal(t, "Bob", actuals.Array.First().Value("firstName"))
+	}
+}
@@ -0,0 +1,102 @@
+package sqlx
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	typeInt       = reflect.TypeOf(0)
+	typeInt64     = reflect.TypeOf(int64(0))
+	typeFloat32   = reflect.TypeOf(float32(0))
+	typeFloat64   = reflect.TypeOf(float64(0))
+	typeBool      = reflect.TypeOf(true)
+	typeString    = reflect.TypeOf("")
+	typeTime      = reflect.TypeOf(time.Time{})
+	typeBytes     = reflect.TypeOf([]byte{})
+	typeInterface = reflect.TypeOf((*interface{})(nil)).Elem()
+	typeObject    = reflect.TypeOf(&gtly.Object{})
+	typeArray     = reflect.TypeOf(&gtly.Array{})
+)
+
+//NewProvider creates a provider from query column types, nullable columns use omit empty
+func NewProvider(name string, columnTypes []*sql.ColumnType) (*gtly.Provider, error) {
+	if len(columnTypes) == 0 {
+		return nil, errors.New("column types were empty")
+	}
+	var fields = make([]*gtly.Field, len(columnTypes))
+	for i, columnType := range columnTypes {
+		field := columnField(columnType.Name(), columnType.DatabaseTypeName(), columnType.ScanType())
+		if nullable, ok := columnType.Nullable(); ok {
+			field.OmitEmpty = &nullable
+		}
+		fields[i] = field
+	}
+	return gtly.NewProvider(name, fields...)
+}
+
+//columnField returns a field for column scan type, database type name is used when driver scan type is not specific
+func columnField(name, databaseType string, scanType reflect.Type) *gtly.Field {
+	databaseType = strings.ToUpper(databaseType)
+	if databaseType == "JSON" || databaseType == "JSONB" {
+		return &gtly.Field{Name: name, Type: typeInterface}
+	}
+	if databaseType == "DATE" {
+		return gtly.NewField(name, gtly.FieldTypeTime, gtly.DateLayoutOpt("2006-01-02"))
+	}
+	if scanType != nil {
+		if dataType := scanDataType(scanType); dataType != "" {
+			return gtly.NewField(name, dataType)
+		}
+	}
+	switch {
+	case strings.Contains(databaseType, "INT"):
+		return gtly.NewField(name, gtly.FieldTypeInt64)
+	case strings.Contains(databaseType, "REAL"), strings.Contains(databaseType, "FLOA"), strings.Contains(databaseType, "DOUB"),
+		strings.Contains(databaseType, "NUMERIC"), strings.Contains(databaseType, "DECIMAL"):
+		return gtly.NewField(name, gtly.FieldTypeFloat64)
+	case strings.Contains(databaseType, "BOOL"):
+		return gtly.NewField(name, gtly.FieldTypeBool)
+	case strings.Contains(databaseType, "TIME"):
+		return gtly.NewField(name, gtly.FieldTypeTime)
+	case strings.Contains(databaseType, "BLOB"), strings.Contains(databaseType, "BINARY"), databaseType == "BYTEA", databaseType == "BYTES":
+		return gtly.NewField(name, gtly.FieldTypeBytes)
+	}
+	return gtly.NewField(name, gtly.FieldTypeString)
+}
+
+//scanDataType returns data type for driver scan type, or empty string if scan type is not specific
+func scanDataType(scanType reflect.Type) string {
+	switch scanType {
+	case reflect.TypeOf(sql.NullInt64{}), reflect.TypeOf(sql.NullInt32{}):
+		return gtly.FieldTypeInt64
+	case reflect.TypeOf(sql.NullFloat64{}):
+		return gtly.FieldTypeFloat64
+	case reflect.TypeOf(sql.NullBool{}):
+		return gtly.FieldTypeBool
+	case reflect.TypeOf(sql.NullString{}):
+		return gtly.FieldTypeString
+	case reflect.TypeOf(sql.NullTime{}), typeTime:
+		return gtly.FieldTypeTime
+	case typeBytes:
+		return gtly.FieldTypeBytes
+	}
+	switch scanType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return gtly.FieldTypeInt64
+	case reflect.Float32, reflect.Float64:
+		return gtly.FieldTypeFloat64
+	case reflect.Bool:
+		return gtly.FieldTypeBool
+	case reflect.String:
+		return gtly.FieldTypeString
+	case reflect.Ptr:
+		return scanDataType(scanType.Elem())
+	}
+	return ""
+}
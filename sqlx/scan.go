@@ -0,0 +1,269 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"github.com/viant/xunsafe"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05.999999999", "2006-01-02T15:04:05.999999999", "2006-01-02"}
+
+//Read runs the query and reads rows into a new array, array provider is built from query column types
+func Read(ctx context.Context, db Querier, name string, query string, args ...interface{}) (*gtly.Array, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to run query: %v", query)
+	}
+	defer rows.Close()
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	provider, err := NewProvider(name, columnTypes)
+	if err != nil {
+		return nil, err
+	}
+	result := provider.NewArray()
+	return result, Scan(rows, result)
+}
+
+//Query runs the query and scans rows into the collection
+func Query(ctx context.Context, db Querier, collection gtly.Collection, query string, args ...interface{}) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to run query: %v", query)
+	}
+	defer rows.Close()
+	return Scan(rows, collection)
+}
+
+//Scan scans rows into collection objects, column values are written straight into matching object fields, unmatched columns are skipped
+func Scan(rows *sql.Rows, collection gtly.Collection) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	provider := &gtly.Provider{Proto: collection.Proto()}
+	scanners := make([]*fieldScanner, len(columns))
+	destinations := make([]interface{}, len(columns))
+	for i, column := range columns {
+		field := lookupField(provider.Proto, column)
+		if field == nil {
+			destinations[i] = &discard{}
+			continue
+		}
+		scanners[i] = &fieldScanner{
+			index:    field.Index,
+			name:     field.Name,
+			field:    provider.MutatorAt(field.Index).Field,
+			layout:   field.TimeLayout(),
+			provider: field.ItemProvider(),
+		}
+		destinations[i] = scanners[i]
+	}
+	for rows.Next() {
+		object := provider.NewObject()
+		for _, scanner := range scanners {
+			if scanner != nil {
+				scanner.object = object
+			}
+		}
+		if err = rows.Scan(destinations...); err != nil {
+			return err
+		}
+		collection.AddObject(object)
+	}
+	return rows.Err()
+}
+
+//lookupField returns field matching column name, input or output name, column names are matched case insensitively as the last resort
+func lookupField(proto *gtly.Proto, column string) *gtly.Field {
+	if field := proto.Lookup(column); field != nil {
+		return field
+	}
+	fields := proto.Fields()
+	for i := range fields {
+		if strings.EqualFold(fields[i].Name, column) || strings.EqualFold(fields[i].OutputName(), column) {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+//discard represents unmatched column destination
+type discard struct{}
+
+//Scan skips column value
+func (d *discard) Scan(src interface{}) error {
+	return nil
+}
+
+//fieldScanner scans column value into the current object field memory, NULL leaves field unset
+type fieldScanner struct {
+	object   *gtly.Object
+	index    int
+	name     string
+	field    *xunsafe.Field
+	layout   string
+	provider *gtly.Provider
+}
+
+//Scan sets column value
+func (s *fieldScanner) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	if err := s.scan(s.field.Pointer(s.object.Addr()), src); err != nil {
+		return errors.Wrapf(err, "failed to scan column %v", s.name)
+	}
+	s.object.MarkSetAt(s.index)
+	return nil
+}
+
+func (s *fieldScanner) scan(ptr unsafe.Pointer, src interface{}) error {
+	switch s.field.Type {
+	case typeInt:
+		value, err := asInt64(src)
+		*(*int)(ptr) = int(value)
+		return err
+	case typeInt64:
+		value, err := asInt64(src)
+		*(*int64)(ptr) = value
+		return err
+	case typeFloat32:
+		value, err := asFloat64(src)
+		*(*float32)(ptr) = float32(value)
+		return err
+	case typeFloat64:
+		value, err := asFloat64(src)
+		*(*float64)(ptr) = value
+		return err
+	case typeBool:
+		value, err := asBool(src)
+		*(*bool)(ptr) = value
+		return err
+	case typeString:
+		*(*string)(ptr) = asString(src, s.layout)
+		return nil
+	case typeBytes:
+		switch actual := src.(type) {
+		case []byte:
+			*(*[]byte)(ptr) = append([]byte{}, actual...)
+		default:
+			*(*[]byte)(ptr) = []byte(asString(src, s.layout))
+		}
+		return nil
+	case typeTime:
+		value, err := asTime(src, s.layout)
+		*(*time.Time)(ptr) = value
+		return err
+	case typeObject:
+		value := &gtly.SQLObject{Provider: s.provider}
+		err := value.Scan(src)
+		*(**gtly.Object)(ptr) = value.Object
+		return err
+	case typeArray:
+		value := &gtly.SQLArray{Provider: s.provider}
+		err := value.Scan(src)
+		*(**gtly.Array)(ptr) = value.Array
+		return err
+	}
+	return assignJSON(s.field.Type, ptr, src)
+}
+
+func asInt64(src interface{}) (int64, error) {
+	switch actual := src.(type) {
+	case int64:
+		return actual, nil
+	case float64:
+		return int64(actual), nil
+	case bool:
+		if actual {
+			return 1, nil
+		}
+		return 0, nil
+	case []byte:
+		return strconv.ParseInt(string(actual), 10, 64)
+	case string:
+		return strconv.ParseInt(actual, 10, 64)
+	}
+	return 0, errors.Errorf("unable to convert %T to int", src)
+}
+
+func asFloat64(src interface{}) (float64, error) {
+	switch actual := src.(type) {
+	case float64:
+		return actual, nil
+	case int64:
+		return float64(actual), nil
+	case []byte:
+		return strconv.ParseFloat(string(actual), 64)
+	case string:
+		return strconv.ParseFloat(actual, 64)
+	}
+	return 0, errors.Errorf("unable to convert %T to float", src)
+}
+
+func asBool(src interface{}) (bool, error) {
+	switch actual := src.(type) {
+	case bool:
+		return actual, nil
+	case int64:
+		return actual != 0, nil
+	case []byte:
+		return strconv.ParseBool(string(actual))
+	case string:
+		return strconv.ParseBool(actual)
+	}
+	return false, errors.Errorf("unable to convert %T to bool", src)
+}
+
+func asString(src interface{}, layout string) string {
+	switch actual := src.(type) {
+	case string:
+		return actual
+	case []byte:
+		return string(actual)
+	case int64:
+		return strconv.FormatInt(actual, 10)
+	case float64:
+		return strconv.FormatFloat(actual, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(actual)
+	case time.Time:
+		return actual.Format(layout)
+	}
+	return ""
+}
+
+//asTime converts time or text value, text is parsed with field layout and common SQL layouts
+func asTime(src interface{}, layout string) (time.Time, error) {
+	var text string
+	switch actual := src.(type) {
+	case time.Time:
+		return actual, nil
+	case int64:
+		return time.Unix(actual, 0).UTC(), nil
+	case []byte:
+		text = string(actual)
+	case string:
+		text = actual
+	default:
+		return time.Time{}, errors.Errorf("unable to convert %T to time", src)
+	}
+	if value, err := time.Parse(layout, text); err == nil {
+		return value, nil
+	}
+	for _, candidate := range timeLayouts {
+		if value, err := time.Parse(candidate, text); err == nil {
+			return value, nil
+		}
+	}
+	return time.Time{}, errors.Errorf("invalid time: %v", text)
+}
@@ -0,0 +1,157 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"github.com/viant/gtly/ddl"
+	"testing"
+	"time"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	db.SetMaxOpenConns(1)
+	return db
+}
+
+func newSQLProvider(t *testing.T) *gtly.Provider {
+	address, err := gtly.NewProvider("address",
+		gtly.NewField("city", gtly.FieldTypeString),
+		gtly.NewField("zip", gtly.FieldTypeInt),
+	)
+	assert.Nil(t, err)
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("price", gtly.FieldTypeFloat64, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("active", gtly.FieldTypeBool, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("updated", gtly.FieldTypeTime, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("avatar", gtly.FieldTypeBytes, gtly.OmitEmptyOpt(true)),
+		gtly.NewField("tags", gtly.FieldTypeArray, gtly.ComponentTypeOpt(gtly.FieldTypeString), gtly.OmitEmptyOpt(true)),
+		gtly.NewField("address", gtly.FieldTypeObject, gtly.ProviderOpt(address), gtly.OmitEmptyOpt(true)),
+	)
+	assert.Nil(t, err)
+	return provider
+}
+
+func newSQLTable(t *testing.T, db *sql.DB, provider *gtly.Provider) {
+	DDL, err := ddl.CreateTable(provider.Proto, ddl.SQLite, ddl.PrimaryKeyOpt("id"))
+	assert.Nil(t, err)
+	_, err = db.Exec(DDL)
+	assert.Nil(t, err)
+}
+
+func TestScan(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+	provider := newSQLProvider(t)
+	newSQLTable(t, db, provider)
+	updated := time.Date(2021, 3, 4, 10, 11, 12, 0, time.UTC)
+	_, err := db.Exec(`INSERT INTO foo (id, name, price, active, updated, avatar, tags, address) VALUES
+		(1, 'Foo', 1.5, 1, ?, X'0102', '["a","b"]', '{"city":"Warsaw","zip":10}'),
+		(2, NULL, NULL, NULL, NULL, NULL, NULL, NULL)`, updated)
+	assert.Nil(t, err)
+
+	testCases := []struct {
+		description string
+		query       string
+		collection  func() gtly.Collection
+		expect      []map[string]interface{}
+		expectError bool
+	}{
+		{
+			description: "array with all columns",
+			query:       "SELECT * FROM foo ORDER BY id",
+			collection: func() gtly.Collection {
+				return provider.NewArray()
+			},
+			expect: []map[string]interface{}{
+				{"id": 1, "name": "Foo", "price": 1.5, "active": true, "updated": updated, "avatar": []byte{1, 2}, "tags": []string{"a", "b"}},
+				{"id": 2},
+			},
+		},
+		{
+			description: "map with renamed and unmatched columns",
+			query:       "SELECT id AS ID, name AS Name, 'x' AS extra FROM foo ORDER BY id",
+			collection: func() gtly.Collection {
+				return provider.NewMap(gtly.NewKeyProvider("id"))
+			},
+			expect: []map[string]interface{}{
+				{"id": 1, "name": "Foo"},
+				{"id": 2},
+			},
+		},
+		{
+			description: "invalid column value",
+			query:       "SELECT 'abc' AS id",
+			collection: func() gtly.Collection {
+				return provider.NewArray()
+			},
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		collection := testCase.collection()
+		err := Query(context.Background(), db, collection, testCase.query)
+		if testCase.expectError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var actual []map[string]interface{}
+		_ = collection.Objects(func(item *gtly.Object) (bool, error) {
+			values := item.AsMap()
+			delete(values, "address")
+			actual = append(actual, values)
+			return true, nil
+		})
+		if _, ok := collection.(*gtly.Map); ok {
+			assert.ElementsMatch(t, testCase.expect, actual, testCase.description)
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+
+	collection := provider.NewArray()
+	assert.Nil(t, Query(context.Background(), db, collection, "SELECT address FROM foo WHERE id = 1"))
+	data, err := json.Marshal(collection.First().Value("address"))
+	assert.Nil(t, err)
+	assert.EqualValues(t, `{"city":"Warsaw","zip":10}`, string(data))
+}
+
+func TestRead(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+	_, err := db.Exec(`CREATE TABLE bar (id INTEGER NOT NULL, name TEXT, score REAL, created TIMESTAMP, payload BLOB, day DATE)`)
+	assert.Nil(t, err)
+	created := time.Date(2021, 3, 4, 10, 11, 12, 0, time.UTC)
+	_, err = db.Exec(`INSERT INTO bar VALUES (1, 'Foo', 2.5, ?, X'01', '2021-03-04')`, created)
+	assert.Nil(t, err)
+
+	array, err := Read(context.Background(), db, "bar", "SELECT * FROM bar")
+	if !assert.Nil(t, err) {
+		return
+	}
+	var fields []string
+	for _, field := range array.Proto().Fields() {
+		fields = append(fields, field.Name+":"+field.DataType)
+	}
+	assert.EqualValues(t, []string{"id:int64", "name:string", "score:float64", "created:time", "payload:bytes", "day:time"}, fields)
+	assert.EqualValues(t, map[string]interface{}{
+		"id": int64(1), "name": "Foo", "score": 2.5, "created": created, "payload": []byte{1},
+		"day": time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+	}, array.First().AsMap())
+
+	_, err = Read(context.Background(), db, "bar", "SELECT * FROM missing")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,51 @@
+//Package sqlx provides database/sql helpers to read rows into gtly collections and write collections with batched INSERT statements
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"github.com/viant/gtly/ddl"
+)
+
+const defaultBatchSize = 100
+
+//Querier represents database/sql query executor, *sql.DB, *sql.Tx and *sql.Conn are supported
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+//Execer represents database/sql statement executor, *sql.DB, *sql.Tx and *sql.Conn are supported
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+//Option represents writer option
+type Option func(w *Writer)
+
+//TableOpt returns table name option, proto name is used by default
+func TableOpt(table string) Option {
+	return func(w *Writer) {
+		w.table = table
+	}
+}
+
+//DialectOpt returns SQL dialect option, dialect controls identifier quoting, placeholders and upsert syntax
+func DialectOpt(dialect ddl.Dialect) Option {
+	return func(w *Writer) {
+		w.dialect = dialect
+	}
+}
+
+//BatchSizeOpt returns batch size option, batch size is the max number of rows in a single INSERT statement
+func BatchSizeOpt(batchSize int) Option {
+	return func(w *Writer) {
+		w.batchSize = batchSize
+	}
+}
+
+//UpsertOpt returns upsert option, rows conflicting on supplied key columns are updated
+func UpsertOpt(keys ...string) Option {
+	return func(w *Writer) {
+		w.keys = keys
+	}
+}
@@ -0,0 +1,181 @@
+package sqlx
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"reflect"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+//assignJSON decodes JSON column value into field memory, nested struct fields are matched by name
+func assignJSON(target reflect.Type, ptr unsafe.Pointer, src interface{}) error {
+	var data []byte
+	switch actual := src.(type) {
+	case []byte:
+		data = actual
+	case string:
+		data = []byte(actual)
+	default:
+		return assign(reflect.NewAt(target, ptr).Elem(), src)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return errors.Wrap(err, "invalid JSON column value")
+	}
+	return assign(reflect.NewAt(target, ptr).Elem(), value)
+}
+
+//assign sets decoded JSON value into addressable target
+func assign(target reflect.Value, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch target.Kind() {
+	case reflect.Interface:
+		target.Set(reflect.ValueOf(value))
+		return nil
+	case reflect.Ptr:
+		item := reflect.New(target.Type().Elem())
+		if err := assign(item.Elem(), value); err != nil {
+			return err
+		}
+		target.Set(item)
+		return nil
+	case reflect.Struct:
+		if target.Type() == typeTime {
+			text, ok := value.(string)
+			if !ok {
+				return errors.Errorf("unable to convert %T to time", value)
+			}
+			ts, err := time.Parse(time.RFC3339Nano, text)
+			if err != nil {
+				return err
+			}
+			target.Set(reflect.ValueOf(ts))
+			return nil
+		}
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return errors.Errorf("unable to convert %T to %v", value, target.Type())
+		}
+		for i := 0; i < target.NumField(); i++ {
+			name := target.Type().Field(i).Name
+			fieldValue, ok := values[name]
+			if !ok {
+				for key, candidate := range values {
+					if strings.EqualFold(key, name) {
+						fieldValue, ok = candidate, true
+						break
+					}
+				}
+			}
+			if !ok {
+				continue
+			}
+			field := target.Field(i)
+			if err := assign(reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem(), fieldValue); err != nil {
+				return errors.Wrapf(err, "failed to assign %v", name)
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if target.Type() == typeBytes {
+			text, ok := value.(string)
+			if !ok {
+				return errors.Errorf("unable to convert %T to bytes", value)
+			}
+			data, err := base64.StdEncoding.DecodeString(text)
+			if err != nil {
+				return err
+			}
+			target.SetBytes(data)
+			return nil
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			return errors.Errorf("unable to convert %T to %v", value, target.Type())
+		}
+		result := reflect.MakeSlice(target.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := assign(result.Index(i), item); err != nil {
+				return err
+			}
+		}
+		target.Set(result)
+		return nil
+	case reflect.Map:
+		values, ok := value.(map[string]interface{})
+		if !ok || target.Type().Key().Kind() != reflect.String {
+			return errors.Errorf("unable to convert %T to %v", value, target.Type())
+		}
+		result := reflect.MakeMapWithSize(target.Type(), len(values))
+		for key, item := range values {
+			entry := reflect.New(target.Type().Elem()).Elem()
+			if err := assign(entry, item); err != nil {
+				return err
+			}
+			result.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), entry)
+		}
+		target.Set(result)
+		return nil
+	}
+	source := reflect.ValueOf(value)
+	if !source.Type().ConvertibleTo(target.Type()) || (source.Kind() == reflect.String) != (target.Kind() == reflect.String) {
+		return errors.Errorf("unable to convert %T to %v", value, target.Type())
+	}
+	target.Set(source.Convert(target.Type()))
+	return nil
+}
+
+//jsonValue returns JSON encodable value for field memory, nested struct fields are encoded by name
+func jsonValue(value reflect.Value) interface{} {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return jsonValue(value.Elem())
+	case reflect.Struct:
+		if value.Type() == typeTime {
+			return valueInterface(value)
+		}
+		result := make(map[string]interface{}, value.NumField())
+		for i := 0; i < value.NumField(); i++ {
+			result[value.Type().Field(i).Name] = jsonValue(value.Field(i))
+		}
+		return result
+	case reflect.Slice:
+		if value.IsNil() {
+			return nil
+		}
+		if value.Type() == typeBytes {
+			return valueInterface(value)
+		}
+		result := make([]interface{}, value.Len())
+		for i := range result {
+			result[i] = jsonValue(value.Index(i))
+		}
+		return result
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+		result := make(map[string]interface{}, value.Len())
+		iterator := value.MapRange()
+		for iterator.Next() {
+			result[iterator.Key().String()] = jsonValue(iterator.Value())
+		}
+		return result
+	}
+	return valueInterface(value)
+}
+
+func valueInterface(value reflect.Value) interface{} {
+	if value.CanInterface() {
+		return value.Interface()
+	}
+	return reflect.NewAt(value.Type(), unsafe.Pointer(value.UnsafeAddr())).Elem().Interface()
+}
@@ -0,0 +1,213 @@
+package sqlx
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/viant/gtly"
+	"github.com/viant/gtly/ddl"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//Writer represents batched INSERT or upsert writer, all proto fields are written using field output names as columns
+type Writer struct {
+	table     string
+	dialect   ddl.Dialect
+	batchSize int
+	keys      []string
+	proto     *gtly.Proto
+	columns   []string
+	composite []bool
+}
+
+//Insert writes collection objects with batched INSERT statements, it returns number of affected rows
+func Insert(ctx context.Context, db Execer, collection gtly.Collection, options ...Option) (int64, error) {
+	writer, err := NewWriter(collection.Proto(), options...)
+	if err != nil {
+		return 0, err
+	}
+	return writer.Write(ctx, db, collection)
+}
+
+//SQL returns INSERT statement for supplied number of rows
+func (w *Writer) SQL(rows int) string {
+	builder := new(strings.Builder)
+	builder.WriteString("INSERT INTO ")
+	builder.WriteString(w.dialect.Quote(w.table))
+	builder.WriteString(" (")
+	for i, column := range w.columns {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(w.dialect.Quote(column))
+	}
+	builder.WriteString(") VALUES ")
+	placeholder := 0
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteByte('(')
+		for j := range w.columns {
+			if j > 0 {
+				builder.WriteString(", ")
+			}
+			placeholder++
+			builder.WriteString(w.placeholder(placeholder))
+		}
+		builder.WriteByte(')')
+	}
+	if len(w.keys) > 0 {
+		builder.WriteString(w.upsert())
+	}
+	return builder.String()
+}
+
+func (w *Writer) placeholder(position int) string {
+	if w.dialect == ddl.PostgreSQL {
+		return "$" + strconv.Itoa(position)
+	}
+	return "?"
+}
+
+//upsert returns conflict clause, non key columns are updated with inserted values
+func (w *Writer) upsert() string {
+	isKey := map[string]bool{}
+	var keys = make([]string, len(w.keys))
+	for i, key := range w.keys {
+		isKey[key] = true
+		keys[i] = w.dialect.Quote(key)
+	}
+	var updates []string
+	for _, column := range w.columns {
+		if isKey[column] {
+			continue
+		}
+		quoted := w.dialect.Quote(column)
+		if w.dialect == ddl.MySQL {
+			updates = append(updates, quoted+" = VALUES("+quoted+")")
+			continue
+		}
+		updates = append(updates, quoted+" = excluded."+quoted)
+	}
+	if w.dialect == ddl.MySQL {
+		if len(updates) == 0 {
+			quoted := w.dialect.Quote(w.keys[0])
+			updates = append(updates, quoted+" = "+quoted)
+		}
+		return " ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+	}
+	if len(updates) == 0 {
+		return " ON CONFLICT (" + strings.Join(keys, ", ") + ") DO NOTHING"
+	}
+	return " ON CONFLICT (" + strings.Join(keys, ", ") + ") DO UPDATE SET " + strings.Join(updates, ", ")
+}
+
+//Write writes collection objects in batches, it returns number of affected rows
+func (w *Writer) Write(ctx context.Context, db Execer, collection gtly.Collection) (int64, error) {
+	if collection.Proto() != w.proto {
+		return 0, errors.Errorf("incompatible collection proto: %v", collection.Proto().Name)
+	}
+	var affected int64
+	var args = make([]interface{}, 0, w.batchSize*len(w.columns))
+	rows := 0
+	flush := func() error {
+		if rows == 0 {
+			return nil
+		}
+		result, err := db.ExecContext(ctx, w.SQL(rows), args...)
+		if err != nil {
+			return errors.Wrapf(err, "failed to insert into %v", w.table)
+		}
+		count, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		affected += count
+		args = args[:0]
+		rows = 0
+		return nil
+	}
+	err := collection.Objects(func(item *gtly.Object) (bool, error) {
+		values, err := w.values(item)
+		if err != nil {
+			return false, err
+		}
+		args = append(args, values...)
+		if rows++; rows >= w.batchSize {
+			return true, flush()
+		}
+		return true, nil
+	})
+	if err != nil {
+		return affected, err
+	}
+	return affected, flush()
+}
+
+//values returns object column values, unset fields are written as NULL and composite fields as JSON text
+func (w *Writer) values(object *gtly.Object) ([]interface{}, error) {
+	var result = make([]interface{}, len(w.columns))
+	for i := range w.columns {
+		if !object.SetAt(i) {
+			continue
+		}
+		accessor := w.proto.AccessorAt(i)
+		if !w.composite[i] {
+			value := gtly.Value(accessor.Value(object))
+			if value != nil {
+				if pointer := reflect.ValueOf(value); pointer.Kind() == reflect.Ptr {
+					if pointer.IsNil() {
+						continue
+					}
+					value = pointer.Elem().Interface()
+				}
+			}
+			result[i] = value
+			continue
+		}
+		var value interface{}
+		if marshaler, ok := accessor.Value(object).(json.Marshaler); ok {
+			value = marshaler
+		} else if value = jsonValue(reflect.NewAt(accessor.Type, accessor.Pointer(object.Addr())).Elem()); value == nil {
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode %v", w.columns[i])
+		}
+		result[i] = string(data)
+	}
+	return result, nil
+}
+
+//NewWriter creates a writer for supplied proto
+func NewWriter(proto *gtly.Proto, options ...Option) (*Writer, error) {
+	result := &Writer{table: proto.Name, dialect: ddl.SQLite, batchSize: defaultBatchSize, proto: proto}
+	for _, option := range options {
+		option(result)
+	}
+	if result.batchSize <= 0 {
+		return nil, errors.Errorf("invalid batch size: %v", result.batchSize)
+	}
+	if len(result.keys) > 0 && result.dialect == ddl.BigQuery {
+		return nil, errors.Errorf("upsert is not supported for %v dialect", result.dialect)
+	}
+	fields := proto.Fields()
+	result.columns = make([]string, len(fields))
+	result.composite = make([]bool, len(fields))
+	names := map[string]bool{}
+	for i := range fields {
+		result.columns[i] = fields[i].OutputName()
+		result.composite[i] = gtly.IsComposite(fields[i].Type)
+		names[result.columns[i]] = true
+	}
+	for _, key := range result.keys {
+		if !names[key] {
+			return nil, errors.Errorf("unknown upsert key column: %v", key)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,128 @@
+package sqlx
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gtly"
+	"github.com/viant/gtly/ddl"
+	"testing"
+	"time"
+)
+
+func TestWriter_SQL(t *testing.T) {
+	provider, err := gtly.NewProvider("foo",
+		gtly.NewField("id", gtly.FieldTypeInt),
+		gtly.NewField("name", gtly.FieldTypeString),
+	)
+	assert.Nil(t, err)
+	testCases := []struct {
+		description string
+		options     []Option
+		rows        int
+		expect      string
+		expectError bool
+	}{
+		{
+			description: "sqlite insert",
+			rows:        2,
+			expect:      `INSERT INTO "foo" ("id", "name") VALUES (?, ?), (?, ?)`,
+		},
+		{
+			description: "postgres upsert",
+			options:     []Option{DialectOpt(ddl.PostgreSQL), TableOpt("foos"), UpsertOpt("id")},
+			rows:        2,
+			expect:      `INSERT INTO "foos" ("id", "name") VALUES ($1, $2), ($3, $4) ON CONFLICT ("id") DO UPDATE SET "name" = excluded."name"`,
+		},
+		{
+			description: "postgres upsert with key columns only",
+			options:     []Option{DialectOpt(ddl.PostgreSQL), UpsertOpt("id", "name")},
+			rows:        1,
+			expect:      `INSERT INTO "foo" ("id", "name") VALUES ($1, $2) ON CONFLICT ("id", "name") DO NOTHING`,
+		},
+		{
+			description: "mysql upsert",
+			options:     []Option{DialectOpt(ddl.MySQL), UpsertOpt("id")},
+			rows:        1,
+			expect:      "INSERT INTO `foo` (`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)",
+		},
+		{
+			description: "bigquery upsert",
+			options:     []Option{DialectOpt(ddl.BigQuery), UpsertOpt("id")},
+			expectError: true,
+		},
+		{
+			description: "unknown upsert key",
+			options:     []Option{UpsertOpt("key")},
+			expectError: true,
+		},
+		{
+			description: "invalid batch size",
+			options:     []Option{BatchSizeOpt(0)},
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		writer, err := NewWriter(provider.Proto, testCase.options...)
+		if testCase.expectError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, writer.SQL(testCase.rows), testCase.description)
+	}
+}
+
+func TestInsert(t *testing.T) {
+	db := newTestDB(t)
+	defer db.Close()
+	provider := newSQLProvider(t)
+	newSQLTable(t, db, provider)
+
+	updated := time.Date(2021, 3, 4, 10, 11, 12, 0, time.UTC)
+	array := provider.NewArray()
+	for i := 1; i <= 5; i++ {
+		object := provider.NewObject()
+		object.SetValue("id", i)
+		if i%2 == 1 {
+			object.SetValue("name", "Foo")
+			object.SetValue("price", float64(i)/2)
+			object.SetValue("active", true)
+			object.SetValue("updated", updated)
+			object.SetValue("avatar", []byte{byte(i)})
+			object.SetValue("tags", []string{"a", "b"})
+		}
+		array.AddObject(object)
+	}
+	affected, err := Insert(context.Background(), db, array, BatchSizeOpt(2))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5, affected)
+
+	actual := provider.NewArray()
+	assert.Nil(t, Query(context.Background(), db, actual, "SELECT * FROM foo ORDER BY id"))
+	assert.EqualValues(t, objectValues(array), objectValues(actual))
+
+	update := provider.NewObject()
+	update.SetValue("id", 1)
+	update.SetValue("name", "Bar")
+	affected, err = Insert(context.Background(), db, provider.NewArray(update), UpsertOpt("id"))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, affected)
+	updated1 := provider.NewArray()
+	assert.Nil(t, Query(context.Background(), db, updated1, "SELECT id, name, price FROM foo WHERE id = 1"))
+	assert.EqualValues(t, map[string]interface{}{"id": 1, "name": "Bar"}, updated1.First().AsMap())
+
+	_, err = Insert(context.Background(), db, provider.NewArray(update))
+	assert.NotNil(t, err)
+}
+
+func objectValues(collection gtly.Collection) []map[string]interface{} {
+	var result []map[string]interface{}
+	_ = collection.Objects(func(item *gtly.Object) (bool, error) {
+		result = append(result, item.AsMap())
+		return true, nil
+	})
+	return result
+}
@@ -39,6 +39,8 @@ var (
 	typeBytes   = reflect.TypeOf([]byte(""))
 	typeTime    = reflect.TypeOf(time.Time{})
 	typeTimePtr = reflect.TypeOf(&time.Time{})
+	typeObject  = reflect.TypeOf(&Object{})
+	typeArray   = reflect.TypeOf(&Array{})
 )
 
 //typeNameForValue returns base type
@@ -64,6 +66,12 @@ func typeNameForType(t reflect.Type) string {
 			return FieldTypeBytes
 		}
 	case reflect.Ptr:
+		switch t {
+		case typeObject:
+			return FieldTypeObject
+		case typeArray:
+			return FieldTypeArray
+		}
 		switch t.Elem().Kind() {
 		case reflect.Float32:
 			return FieldTypeFloat32
@@ -129,3 +137,27 @@ func getBaseType(typeName string) reflect.Type {
 	}
 	return nil
 }
+
+//getArrayType returns slice type for supplied component type name or nil if component type is not a base type
+func getArrayType(componentType string) reflect.Type {
+	if itemType := getBaseType(componentType); itemType != nil {
+		return reflect.SliceOf(itemType)
+	}
+	return nil
+}
+
+//IsComposite returns true for slice, map, struct and interface types (or pointers to them), except bytes and time
+func IsComposite(rType reflect.Type) bool {
+	if rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+	switch rType.Kind() {
+	case reflect.Slice:
+		return rType.Elem().Kind() != reflect.Uint8
+	case reflect.Struct:
+		return rType != typeTime
+	case reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}